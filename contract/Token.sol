@@ -9,12 +9,42 @@ import "node_modules/@openzeppelin/contracts/token/ERC20/ERC20.sol";
  */
 
 contract Token is ERC20 {
+    uint8 private immutable _decimals;
+
+    /**
+     * @notice The initial supply is minted upon deployment, and split evenly between
+     *         the initial holders, or assigned to the deployer if there are none.
+     * @dev The remainder of an uneven split is assigned to the first holder.
+     * @param name_ The name of the token
+     * @param symbol_ The symbol of the token
+     * @param decimals_ The number of decimals of the token amounts
+     * @param initialSupply The initial supply, in base units
+     * @param holders The accounts the initial supply is minted to
+     */
+    constructor(
+        string memory name_,
+        string memory symbol_,
+        uint8 decimals_,
+        uint256 initialSupply,
+        address[] memory holders
+    ) ERC20(name_, symbol_) {
+        _decimals = decimals_;
+
+        if (holders.length == 0) {
+            _mint(msg.sender, initialSupply);
+        } else {
+            uint256 share = initialSupply / holders.length;
+            _mint(holders[0], share + (initialSupply % holders.length));
+            for (uint256 i = 1; i < holders.length; i++) {
+                _mint(holders[i], share);
+            }
+        }
+    }
+
     /**
-     * @notice 100 TOK are minted, and assigned to the deployer of the contract
-     *         upon deployment.
-     * @dev decimals is set to 18
+     * @notice Returns the number of decimals set upon deployment
      */
-    constructor() ERC20("Token", "TOK") {
-        _mint(msg.sender, 100 * 10**decimals());
+    function decimals() public view override returns (uint8) {
+        return _decimals;
     }
 }
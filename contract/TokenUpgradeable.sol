@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.4;
+
+import "node_modules/@openzeppelin/contracts/token/ERC20/ERC20.sol";
+import "node_modules/@openzeppelin/contracts/access/Ownable.sol";
+import "node_modules/@openzeppelin/contracts/proxy/utils/Initializable.sol";
+import "node_modules/@openzeppelin/contracts/proxy/utils/UUPSUpgradeable.sol";
+
+/** @title TokenUpgradeable
+ *  @author K1-R1
+ *  @notice This contract is the Token contract for deployment behind an ERC-1967
+ *          proxy, so that it can be upgraded without losing its balances
+ */
+
+contract TokenUpgradeable is ERC20, Ownable, Initializable, UUPSUpgradeable {
+    string private _tokenName;
+    string private _tokenSymbol;
+    uint8 private _tokenDecimals;
+
+    /**
+     * @notice The implementation is locked upon deployment, the state is only set
+     *         through the proxy.
+     * @dev The name and symbol of ERC20 are stored by the proxy in initialize.
+     */
+    constructor() ERC20("", "") {
+        _disableInitializers();
+    }
+
+    /**
+     * @notice Initialises the proxy with the parameters of the Token constructor. The
+     *         initial supply is split evenly between the initial holders, or assigned
+     *         to the caller if there are none.
+     * @dev The remainder of an uneven split is assigned to the first holder.
+     * @param name_ The name of the token
+     * @param symbol_ The symbol of the token
+     * @param decimals_ The number of decimals of the token amounts
+     * @param initialSupply The initial supply, in base units
+     * @param holders The accounts the initial supply is minted to
+     * @param owner_ The account allowed to upgrade a UUPS proxy
+     */
+    function initialize(
+        string memory name_,
+        string memory symbol_,
+        uint8 decimals_,
+        uint256 initialSupply,
+        address[] memory holders,
+        address owner_
+    ) external initializer {
+        _tokenName = name_;
+        _tokenSymbol = symbol_;
+        _tokenDecimals = decimals_;
+        _transferOwnership(owner_);
+
+        if (holders.length == 0) {
+            _mint(_msgSender(), initialSupply);
+        } else {
+            uint256 share = initialSupply / holders.length;
+            _mint(holders[0], share + (initialSupply % holders.length));
+            for (uint256 i = 1; i < holders.length; i++) {
+                _mint(holders[i], share);
+            }
+        }
+    }
+
+    /**
+     * @notice Returns the name set upon initialisation
+     */
+    function name() public view override returns (string memory) {
+        return _tokenName;
+    }
+
+    /**
+     * @notice Returns the symbol set upon initialisation
+     */
+    function symbol() public view override returns (string memory) {
+        return _tokenSymbol;
+    }
+
+    /**
+     * @notice Returns the number of decimals set upon initialisation
+     */
+    function decimals() public view override returns (uint8) {
+        return _tokenDecimals;
+    }
+
+    /**
+     * @dev Only the owner can upgrade a UUPS proxy of the contract
+     */
+    function _authorizeUpgrade(address) internal override onlyOwner {}
+}
@@ -1,31 +1,45 @@
-# run_all.sh sets the necessary variables, runs a script to deploy an ERC20 contract.
-# Runs a script to query, transfer, and then query again, the token balances of two accounts.
+# run_all.sh sets the necessary variables, and uses tokencli to deploy an ERC20 contract.
+# Queries, transfers, and then queries again, the token balances of two accounts.
 # Runs tests on the ERC20 contract, and a script containing helper functions
 
-# File paths to go scripts
-DEPLOY=scripts/deploy/deploy.go
-QUERY=scripts/query_and_transfer/query_and_transfer.go
+# Path to the tokencli command
+TOKENCLI="go run ./scripts/tokencli"
 
-# Account variables
+# Network profile from networks.json
+export EVMOS_NETWORK=${EVMOS_NETWORK:-local}
+
+# Account variables, the deployer signs with its key in the evmosd test keyring.
+# tokencli takes the evmos1 bech32 addresses of the keys as they are.
 DEPLOYER_KEY="mykey"
 RECEIVER_KEY="mykey2"
-DEPLOYER_PK=$(evmosd keys unsafe-export-eth-key $DEPLOYER_KEY --keyring-backend=test)
-RECEIVER_PK=$(evmosd keys unsafe-export-eth-key $RECEIVER_KEY --keyring-backend=test)
+DEPLOYER_ADDRESS=$(evmosd keys show $DEPLOYER_KEY -a --keyring-backend=test)
+RECEIVER_ADDRESS=$(evmosd keys show $RECEIVER_KEY -a --keyring-backend=test)
+
+# Deploy contract, recording it as "Token" in the deployment manifest (deployments.json).
+# Later commands resolve the contract by this name for the active network.
+echo "Deployed Contract"
+echo "---------------------------------------------"
+$TOKENCLI deploy -name Token -from $DEPLOYER_KEY
+$TOKENCLI info -contract Token
 
-# Deploy contract, and output to tmp.txt
-go run $DEPLOY $DEPLOYER_PK > tmp.txt
-# Extract contract addresss of deployed contract, and delete tmp.txt
-cat tmp.txt
-CONTRACT_ADDRESS=$(cat tmp.txt | grep 'contract address' | grep -o '0x[0-9a-zA-Z]*')
-rm -f tmp.txt
+# Query starting balances
+echo "Starting balances"
+echo "---------------------------------------------"
+$TOKENCLI balance -contract Token -account $DEPLOYER_ADDRESS
+$TOKENCLI balance -contract Token -account $RECEIVER_ADDRESS
 
-# Wait for the contract deployment transaction to be executed
-sleep 5
+# Transfer 10 tokens from the deployer to the receiver
+echo "Transfer"
+echo "---------------------------------------------"
+$TOKENCLI transfer -from $DEPLOYER_KEY -contract Token -to $RECEIVER_ADDRESS -amount 10
 
-# Query and transfer tokens
-go run $QUERY $CONTRACT_ADDRESS $DEPLOYER_PK $RECEIVER_PK
+# Query ending balances
+echo "Ending balances"
+echo "---------------------------------------------"
+$TOKENCLI balance -contract Token -account $DEPLOYER_ADDRESS
+$TOKENCLI balance -contract Token -account $RECEIVER_ADDRESS
 
 # Run tests
 echo "Beginning tests"
 echo "---------------------------------------------"
-go test ./tests -v
\ No newline at end of file
+go test ./tests -v
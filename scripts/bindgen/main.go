@@ -0,0 +1,174 @@
+/** bindgen compiles the contracts with solc in standard JSON mode, writes their ABI
+    and bytecode to contract/build, and regenerates the Go binding of the Token
+    contract in scripts/token/Token.go. The compiler version, settings and source
+    hashes are recorded in contract/build/build-info.json.
+    With -check, nothing is written, and the command fails if the committed binding,
+    artifacts and sources disagree.
+    Usage, from the repository root:
+	go run ./scripts/bindgen [-solc path] [-solc-output file] [-check]
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// defaultSources are the Token contracts, and the OpenZeppelin proxies the deploy
+// command puts in front of TokenUpgradeable
+var defaultSources = []string{
+	"contract/Token.sol",
+	"contract/TokenUpgradeable.sol",
+	"node_modules/@openzeppelin/contracts/proxy/ERC1967/ERC1967Proxy.sol",
+	"node_modules/@openzeppelin/contracts/proxy/transparent/TransparentUpgradeableProxy.sol",
+}
+
+func main() {
+	log.SetFlags(0)
+
+	sourcesFlag := flag.String("sources", strings.Join(defaultSources, ","), "comma separated Solidity sources to compile")
+	buildDirFlag := flag.String("build-dir", util.DefaultBuildDir, "directory of the ABI, bytecode and build info files")
+	solcFlag := flag.String("solc", "solc", "path of the solc binary")
+	solcOutputFlag := flag.String("solc-output", "", "standard JSON output of solc to use instead of running solc, or - for stdin")
+	optimizeFlag := flag.Bool("optimize", util.DefaultCompilerSettings.Optimizer.Enabled, "enable the solc optimizer")
+	runsFlag := flag.Int("optimize-runs", util.DefaultCompilerSettings.Optimizer.Runs, "number of runs the optimizer optimizes for")
+	// Evmos v8 runs the london EVM, later solc defaults emit opcodes it does not have
+	evmVersionFlag := flag.String("evm-version", "london", "EVM version to compile for")
+	contractFlag := flag.String("contract", "Token", "contract to generate the Go binding of")
+	pkgFlag := flag.String("pkg", "token", "package of the Go binding")
+	outFlag := flag.String("out", "scripts/token/Token.go", "file of the Go binding")
+	checkFlag := flag.Bool("check", false, "check the committed binding, artifacts and sources agree, without writing anything")
+	flag.Parse()
+
+	infoPath := filepath.Join(*buildDirFlag, util.DefaultBuildInfoFile)
+
+	if *checkFlag {
+		if err := check(infoPath, *buildDirFlag, *solcFlag, *solcOutputFlag); err != nil {
+			log.Fatalf("Check failed: %v", err)
+		}
+		fmt.Println("Binding, artifacts and sources are in sync")
+		return
+	}
+
+	settings := util.CompilerSettings{
+		Optimizer:  util.OptimizerSettings{Enabled: *optimizeFlag, Runs: *runsFlag},
+		EVMVersion: *evmVersionFlag,
+	}
+	binding := util.BindingConfig{Contract: *contractFlag, Package: *pkgFlag, Out: *outFlag}
+
+	output, err := compile(strings.Split(*sourcesFlag, ","), settings, *solcFlag, *solcOutputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	info, artifacts, err := util.NewBuildInfo(output, settings, binding)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := util.WriteArtifacts(*buildDirFlag, artifacts); err != nil {
+		log.Fatalf("Failed to write artifacts: %v", err)
+	}
+
+	var bound util.Artifact
+	for _, artifact := range artifacts {
+		if artifact.Name == binding.Contract {
+			bound = artifact
+		}
+	}
+	code, err := util.GenerateBinding(bound.ABI, bound.Bin, binding)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(binding.Out, []byte(code), 0o644); err != nil {
+		log.Fatalf("Failed to write binding: %v", err)
+	}
+
+	if err := info.Save(infoPath); err != nil {
+		log.Fatalf("Failed to write build info: %v", err)
+	}
+
+	fmt.Printf("Compiled %d contracts with solc %s, and generated %s\n", len(artifacts), info.Compiler.Version, binding.Out)
+}
+
+// compile returns the solc output of the sources, read from solcOutput if it is set,
+// or else from running solc
+func compile(sources []string, settings util.CompilerSettings, solc, solcOutput string) (*util.SolcOutput, error) {
+	var data []byte
+	var err error
+
+	switch solcOutput {
+	case "":
+		input, err := util.NewSolcInput(sources, settings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sources: %w", err)
+		}
+		if data, err = util.RunSolc(context.Background(), solc, input); err != nil {
+			return nil, err
+		}
+	case "-":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(solcOutput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read solc output: %w", err)
+	}
+
+	return util.ParseSolcOutput(data)
+}
+
+// check checks the committed build against its build info, and against a fresh
+// compilation with the recorded settings. It fails if solc is not available and no
+// solc output is given, as the artifacts could not be checked against their sources.
+func check(infoPath, buildDir, solc, solcOutput string) error {
+	info, err := util.LoadBuildInfo(infoPath)
+	if err != nil {
+		return err
+	}
+
+	if err := util.CheckBuild(".", buildDir, info); err != nil {
+		return err
+	}
+
+	if solcOutput == "" {
+		if _, err := exec.LookPath(solc); err != nil {
+			return fmt.Errorf("cannot recompile the sources, give the path of solc with -solc, or its standard JSON output with -solc-output: %w", err)
+		}
+	}
+
+	// Recompile the sources of the compiled contracts, including the dependencies
+	// compiled for their own artifacts, such as the proxies
+	var sources []string
+	seen := map[string]bool{}
+	for _, contract := range info.Contracts {
+		if !seen[contract.Source] {
+			seen[contract.Source] = true
+			sources = append(sources, contract.Source)
+		}
+	}
+
+	output, err := compile(sources, info.Compiler.Settings, solc, solcOutput)
+	if err != nil {
+		return err
+	}
+	compiled, artifacts, err := util.NewBuildInfo(output, info.Compiler.Settings, info.Binding)
+	if err != nil {
+		return err
+	}
+	if compiled.Compiler.Version != info.Compiler.Version && !strings.HasPrefix(compiled.Compiler.Version, info.Compiler.Version+"+") {
+		return fmt.Errorf("solc %s is not the recorded compiler %s", compiled.Compiler.Version, info.Compiler.Version)
+	}
+
+	return util.CompareArtifacts(buildDir, artifacts)
+}
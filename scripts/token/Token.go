@@ -30,8 +30,8 @@ var (
 
 // TokenMetaData contains all meta data concerning the Token contract.
 var TokenMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"subtractedValue\",\"type\":\"uint256\"}],\"name\":\"decreaseAllowance\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"addedValue\",\"type\":\"uint256\"}],\"name\":\"increaseAllowance\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
-	Bin: "0x60806040523480156200001157600080fd5b506040518060400160405280600581526020017f546f6b656e0000000000000000000000000000000000000000000000000000008152506040518060400160405280600381526020017f544f4b000000000000000000000000000000000000000000000000000000000081525081600390805190602001906200009692919062000281565b508060049080519060200190620000af92919062000281565b505050620000ef33620000c7620000f560201b60201c565b600a620000d5919062000471565b6064620000e39190620005ae565b620000fe60201b60201c565b620006f0565b60006012905090565b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff16141562000171576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401620001689062000369565b60405180910390fd5b62000185600083836200027760201b60201c565b8060026000828254620001999190620003b9565b92505081905550806000808473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000206000828254620001f09190620003b9565b925050819055508173ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef836040516200025791906200038b565b60405180910390a362000273600083836200027c60201b60201c565b5050565b505050565b505050565b8280546200028f9062000626565b90600052602060002090601f016020900481019282620002b35760008555620002ff565b82601f10620002ce57805160ff1916838001178555620002ff565b82800160010185558215620002ff579182015b82811115620002fe578251825591602001919060010190620002e1565b5b5090506200030e919062000312565b5090565b5b808211156200032d57600081600090555060010162000313565b5090565b600062000340601f83620003a8565b91506200034d82620006c7565b602082019050919050565b62000363816200060f565b82525050565b60006020820190508181036000830152620003848162000331565b9050919050565b6000602082019050620003a2600083018462000358565b92915050565b600082825260208201905092915050565b6000620003c6826200060f565b9150620003d3836200060f565b9250827fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff038211156200040b576200040a6200065c565b5b828201905092915050565b6000808291508390505b6001851115620004685780860481111562000440576200043f6200065c565b5b6001851615620004505780820291505b80810290506200046085620006ba565b945062000420565b94509492505050565b60006200047e826200060f565b91506200048b8362000619565b9250620004ba7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8484620004c2565b905092915050565b600082620004d45760019050620005a7565b81620004e45760009050620005a7565b8160018114620004fd576002811462000508576200053e565b6001915050620005a7565b60ff8411156200051d576200051c6200065c565b5b8360020a9150848211156200053757620005366200065c565b5b50620005a7565b5060208310610133831016604e8410600b8410161715620005785782820a9050838111156200057257620005716200065c565b5b620005a7565b62000587848484600162000416565b92509050818404811115620005a157620005a06200065c565b5b81810290505b9392505050565b6000620005bb826200060f565b9150620005c8836200060f565b9250817fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff04831182151516156200060457620006036200065c565b5b828202905092915050565b6000819050919050565b600060ff82169050919050565b600060028204905060018216806200063f57607f821691505b602082108114156200065657620006556200068b565b5b50919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b60008160011c9050919050565b7f45524332303a206d696e7420746f20746865207a65726f206164647265737300600082015250565b61126880620007006000396000f3fe608060405234801561001057600080fd5b50600436106100a95760003560e01c80633950935111610071578063395093511461016857806370a082311461019857806395d89b41146101c8578063a457c2d7146101e6578063a9059cbb14610216578063dd62ed3e14610246576100a9565b806306fdde03146100ae578063095ea7b3146100cc57806318160ddd146100fc57806323b872dd1461011a578063313ce5671461014a575b600080fd5b6100b6610276565b6040516100c39190610d29565b60405180910390f35b6100e660048036038101906100e19190610b73565b610308565b6040516100f39190610d0e565b60405180910390f35b61010461032b565b6040516101119190610e2b565b60405180910390f35b610134600480360381019061012f9190610b20565b610335565b6040516101419190610d0e565b60405180910390f35b610152610364565b60405161015f9190610e46565b60405180910390f35b610182600480360381019061017d9190610b73565b61036d565b60405161018f9190610d0e565b60405180910390f35b6101b260048036038101906101ad9190610ab3565b6103a4565b6040516101bf9190610e2b565b60405180910390f35b6101d06103ec565b6040516101dd9190610d29565b60405180910390f35b61020060048036038101906101fb9190610b73565b61047e565b60405161020d9190610d0e565b60405180910390f35b610230600480360381019061022b9190610b73565b6104f5565b60405161023d9190610d0e565b60405180910390f35b610260600480360381019061025b9190610ae0565b610518565b60405161026d9190610e2b565b60405180910390f35b60606003805461028590610f5b565b80601f01602080910402602001604051908101604052809291908181526020018280546102b190610f5b565b80156102fe5780601f106102d3576101008083540402835291602001916102fe565b820191906000526020600020905b8154815290600101906020018083116102e157829003601f168201915b5050505050905090565b60008061031361059f565b90506103208185856105a7565b600191505092915050565b6000600254905090565b60008061034061059f565b905061034d858285610772565b6103588585856107fe565b60019150509392505050565b60006012905090565b60008061037861059f565b905061039981858561038a8589610518565b6103949190610e7d565b6105a7565b600191505092915050565b60008060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020549050919050565b6060600480546103fb90610f5b565b80601f016020809104026020016040519081016040528092919081815260200182805461042790610f5b565b80156104745780601f1061044957610100808354040283529160200191610474565b820191906000526020600020905b81548152906001019060200180831161045757829003601f168201915b5050505050905090565b60008061048961059f565b905060006104978286610518565b9050838110156104dc576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016104d390610e0b565b60405180910390fd5b6104e982868684036105a7565b60019250505092915050565b60008061050061059f565b905061050d8185856107fe565b600191505092915050565b6000600160008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905092915050565b600033905090565b600073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff161415610617576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161060e90610deb565b60405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff161415610687576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161067e90610d6b565b60405180910390fd5b80600160008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508173ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925836040516107659190610e2b565b60405180910390a3505050565b600061077e8484610518565b90507fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff81146107f857818110156107ea576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016107e190610d8b565b60405180910390fd5b6107f784848484036105a7565b5b50505050565b600073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff16141561086e576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161086590610dcb565b60405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff1614156108de576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016108d590610d4b565b60405180910390fd5b6108e9838383610a7f565b60008060008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000205490508181101561096f576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161096690610dab565b60405180910390fd5b8181036000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002081905550816000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000206000828254610a029190610e7d565b925050819055508273ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef84604051610a669190610e2b565b60405180910390a3610a79848484610a84565b50505050565b505050565b505050565b600081359050610a9881611204565b92915050565b600081359050610aad8161121b565b92915050565b600060208284031215610ac957610ac8610feb565b5b6000610ad784828501610a89565b91505092915050565b60008060408385031215610af757610af6610feb565b5b6000610b0585828601610a89565b9250506020610b1685828601610a89565b9150509250929050565b600080600060608486031215610b3957610b38610feb565b5b6000610b4786828701610a89565b9350506020610b5886828701610a89565b9250506040610b6986828701610a9e565b9150509250925092565b60008060408385031215610b8a57610b89610feb565b5b6000610b9885828601610a89565b9250506020610ba985828601610a9e565b9150509250929050565b610bbc81610ee5565b82525050565b6000610bcd82610e61565b610bd78185610e6c565b9350610be7818560208601610f28565b610bf081610ff0565b840191505092915050565b6000610c08602383610e6c565b9150610c1382611001565b604082019050919050565b6000610c2b602283610e6c565b9150610c3682611050565b604082019050919050565b6000610c4e601d83610e6c565b9150610c598261109f565b602082019050919050565b6000610c71602683610e6c565b9150610c7c826110c8565b604082019050919050565b6000610c94602583610e6c565b9150610c9f82611117565b604082019050919050565b6000610cb7602483610e6c565b9150610cc282611166565b604082019050919050565b6000610cda602583610e6c565b9150610ce5826111b5565b604082019050919050565b610cf981610f11565b82525050565b610d0881610f1b565b82525050565b6000602082019050610d236000830184610bb3565b92915050565b60006020820190508181036000830152610d438184610bc2565b905092915050565b60006020820190508181036000830152610d6481610bfb565b9050919050565b60006020820190508181036000830152610d8481610c1e565b9050919050565b60006020820190508181036000830152610da481610c41565b9050919050565b60006020820190508181036000830152610dc481610c64565b9050919050565b60006020820190508181036000830152610de481610c87565b9050919050565b60006020820190508181036000830152610e0481610caa565b9050919050565b60006020820190508181036000830152610e2481610ccd565b9050919050565b6000602082019050610e406000830184610cf0565b92915050565b6000602082019050610e5b6000830184610cff565b92915050565b600081519050919050565b600082825260208201905092915050565b6000610e8882610f11565b9150610e9383610f11565b9250827fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff03821115610ec857610ec7610f8d565b5b828201905092915050565b6000610ede82610ef1565b9050919050565b60008115159050919050565b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b6000819050919050565b600060ff82169050919050565b60005b83811015610f46578082015181840152602081019050610f2b565b83811115610f55576000848401525b50505050565b60006002820490506001821680610f7357607f821691505b60208210811415610f8757610f86610fbc565b5b50919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b600080fd5b6000601f19601f8301169050919050565b7f45524332303a207472616e7366657220746f20746865207a65726f206164647260008201527f6573730000000000000000000000000000000000000000000000000000000000602082015250565b7f45524332303a20617070726f766520746f20746865207a65726f20616464726560008201527f7373000000000000000000000000000000000000000000000000000000000000602082015250565b7f45524332303a20696e73756666696369656e7420616c6c6f77616e6365000000600082015250565b7f45524332303a207472616e7366657220616d6f756e742065786365656473206260008201527f616c616e63650000000000000000000000000000000000000000000000000000602082015250565b7f45524332303a207472616e736665722066726f6d20746865207a65726f20616460008201527f6472657373000000000000000000000000000000000000000000000000000000602082015250565b7f45524332303a20617070726f76652066726f6d20746865207a65726f2061646460008201527f7265737300000000000000000000000000000000000000000000000000000000602082015250565b7f45524332303a2064656372656173656420616c6c6f77616e63652062656c6f7760008201527f207a65726f000000000000000000000000000000000000000000000000000000602082015250565b61120d81610ed3565b811461121857600080fd5b50565b61122481610f11565b811461122f57600080fd5b5056fea2646970667358221220fae032a04fee5bb118da74f0aef836759c674fbca3eacbd1e45878299fc845bb64736f6c63430008060033",
+	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name_\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"symbol_\",\"type\":\"string\"},{\"internalType\":\"uint8\",\"name\":\"decimals_\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"initialSupply\",\"type\":\"uint256\"},{\"internalType\":\"address[]\",\"name\":\"holders\",\"type\":\"address[]\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"subtractedValue\",\"type\":\"uint256\"}],\"name\":\"decreaseAllowance\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"addedValue\",\"type\":\"uint256\"}],\"name\":\"increaseAllowance\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x60a06040523480156200001157600080fd5b5060405162001ed538038062001ed583398181016040528101906200003791906200061f565b848481600390816200004a919062000945565b5080600490816200005c919062000945565b5050508260ff1660808160ff168152505060008151036200008f576200008933836200015160201b60201c565b62000146565b6000815183620000a0919062000a8a565b9050620000ed82600081518110620000bd57620000bc62000ac2565b5b6020026020010151835185620000d4919062000af1565b83620000e1919062000b29565b6200015160201b60201c565b6000600190505b825181101562000143576200012d83828151811062000118576200011762000ac2565b5b6020026020010151836200015160201b60201c565b80806200013a9062000b64565b915050620000f4565b50505b505050505062000c62565b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff1603620001c3576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401620001ba9062000c12565b60405180910390fd5b620001d760008383620002c960201b60201c565b8060026000828254620001eb919062000b29565b92505081905550806000808473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020600082825462000242919062000b29565b925050819055508173ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef83604051620002a9919062000c45565b60405180910390a3620002c560008383620002ce60201b60201c565b5050565b505050565b505050565b6000604051905090565b600080fd5b600080fd5b600080fd5b600080fd5b6000601f19601f8301169050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b6200033c82620002f1565b810181811067ffffffffffffffff821117156200035e576200035d62000302565b5b80604052505050565b600062000373620002d3565b905062000381828262000331565b919050565b600067ffffffffffffffff821115620003a457620003a362000302565b5b620003af82620002f1565b9050602081019050919050565b60005b83811015620003dc578082015181840152602081019050620003bf565b60008484015250505050565b6000620003ff620003f98462000386565b62000367565b9050828152602081018484840111156200041e576200041d620002ec565b5b6200042b848285620003bc565b509392505050565b600082601f8301126200044b576200044a620002e7565b5b81516200045d848260208601620003e8565b91505092915050565b600060ff82169050919050565b6200047e8162000466565b81146200048a57600080fd5b50565b6000815190506200049e8162000473565b92915050565b6000819050919050565b620004b981620004a4565b8114620004c557600080fd5b50565b600081519050620004d981620004ae565b92915050565b600067ffffffffffffffff821115620004fd57620004fc62000302565b5b602082029050602081019050919050565b600080fd5b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b6000620005408262000513565b9050919050565b620005528162000533565b81146200055e57600080fd5b50565b600081519050620005728162000547565b92915050565b60006200058f6200058984620004df565b62000367565b90508083825260208201905060208402830185811115620005b557620005b46200050e565b5b835b81811015620005e25780620005cd888262000561565b845260208401935050602081019050620005b7565b5050509392505050565b600082601f830112620006045762000603620002e7565b5b81516200061684826020860162000578565b91505092915050565b600080600080600060a086880312156200063e576200063d620002dd565b5b600086015167ffffffffffffffff8111156200065f576200065e620002e2565b5b6200066d8882890162000433565b955050602086015167ffffffffffffffff811115620006915762000690620002e2565b5b6200069f8882890162000433565b9450506040620006b2888289016200048d565b9350506060620006c588828901620004c8565b925050608086015167ffffffffffffffff811115620006e957620006e8620002e2565b5b620006f788828901620005ec565b9150509295509295909350565b600081519050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b600060028204905060018216806200075757607f821691505b6020821081036200076d576200076c6200070f565b5b50919050565b60008190508160005260206000209050919050565b60006020601f8301049050919050565b600082821b905092915050565b600060088302620007d77fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8262000798565b620007e3868362000798565b95508019841693508086168417925050509392505050565b6000819050919050565b600062000826620008206200081a84620004a4565b620007fb565b620004a4565b9050919050565b6000819050919050565b620008428362000805565b6200085a62000851826200082d565b848454620007a5565b825550505050565b600090565b6200087162000862565b6200087e81848462000837565b505050565b5b81811015620008a6576200089a60008262000867565b60018101905062000884565b5050565b601f821115620008f557620008bf8162000773565b620008ca8462000788565b81016020851015620008da578190505b620008f2620008e98562000788565b83018262000883565b50505b505050565b600082821c905092915050565b60006200091a60001984600802620008fa565b1980831691505092915050565b600062000935838362000907565b9150826002028217905092915050565b620009508262000704565b67ffffffffffffffff8111156200096c576200096b62000302565b5b6200097882546200073e565b62000985828285620008aa565b600060209050601f831160018114620009bd5760008415620009a8578287015190505b620009b4858262000927565b86555062000a24565b601f198416620009cd8662000773565b60005b82811015620009f757848901518255600182019150602085019450602081019050620009d0565b8683101562000a17578489015162000a13601f89168262000907565b8355505b6001600288020188555050505b505050505050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601260045260246000fd5b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b600062000a9782620004a4565b915062000aa483620004a4565b92508262000ab75762000ab662000a2c565b5b828204905092915050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052603260045260246000fd5b600062000afe82620004a4565b915062000b0b83620004a4565b92508262000b1e5762000b1d62000a2c565b5b828206905092915050565b600062000b3682620004a4565b915062000b4383620004a4565b925082820190508082111562000b5e5762000b5d62000a5b565b5b92915050565b600062000b7182620004a4565b91507fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff820362000ba65762000ba562000a5b565b5b600182019050919050565b600082825260208201905092915050565b7f45524332303a206d696e7420746f20746865207a65726f206164647265737300600082015250565b600062000bfa601f8362000bb1565b915062000c078262000bc2565b602082019050919050565b6000602082019050818103600083015262000c2d8162000beb565b9050919050565b62000c3f81620004a4565b82525050565b600060208201905062000c5c600083018462000c34565b92915050565b60805161125762000c7e600039600061036801526112576000f3fe608060405234801561001057600080fd5b50600436106100a95760003560e01c80633950935111610071578063395093511461016857806370a082311461019857806395d89b41146101c8578063a457c2d7146101e6578063a9059cbb14610216578063dd62ed3e14610246576100a9565b806306fdde03146100ae578063095ea7b3146100cc57806318160ddd146100fc57806323b872dd1461011a578063313ce5671461014a575b600080fd5b6100b6610276565b6040516100c39190610b34565b60405180910390f35b6100e660048036038101906100e19190610bef565b610308565b6040516100f39190610c4a565b60405180910390f35b61010461032b565b6040516101119190610c74565b60405180910390f35b610134600480360381019061012f9190610c8f565b610335565b6040516101419190610c4a565b60405180910390f35b610152610364565b60405161015f9190610cfe565b60405180910390f35b610182600480360381019061017d9190610bef565b61038c565b60405161018f9190610c4a565b60405180910390f35b6101b260048036038101906101ad9190610d19565b6103c3565b6040516101bf9190610c74565b60405180910390f35b6101d061040b565b6040516101dd9190610b34565b60405180910390f35b61020060048036038101906101fb9190610bef565b61049d565b60405161020d9190610c4a565b60405180910390f35b610230600480360381019061022b9190610bef565b610514565b60405161023d9190610c4a565b60405180910390f35b610260600480360381019061025b9190610d46565b610537565b60405161026d9190610c74565b60405180910390f35b60606003805461028590610db5565b80601f01602080910402602001604051908101604052809291908181526020018280546102b190610db5565b80156102fe5780601f106102d3576101008083540402835291602001916102fe565b820191906000526020600020905b8154815290600101906020018083116102e157829003601f168201915b5050505050905090565b6000806103136105be565b90506103208185856105c6565b600191505092915050565b6000600254905090565b6000806103406105be565b905061034d85828561078f565b61035885858561081b565b60019150509392505050565b60007f0000000000000000000000000000000000000000000000000000000000000000905090565b6000806103976105be565b90506103b88185856103a98589610537565b6103b39190610e15565b6105c6565b600191505092915050565b60008060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020549050919050565b60606004805461041a90610db5565b80601f016020809104026020016040519081016040528092919081815260200182805461044690610db5565b80156104935780601f1061046857610100808354040283529160200191610493565b820191906000526020600020905b81548152906001019060200180831161047657829003601f168201915b5050505050905090565b6000806104a86105be565b905060006104b68286610537565b9050838110156104fb576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016104f290610ebb565b60405180910390fd5b61050882868684036105c6565b60019250505092915050565b60008061051f6105be565b905061052c81858561081b565b600191505092915050565b6000600160008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905092915050565b600033905090565b600073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff1603610635576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161062c90610f4d565b60405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff16036106a4576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161069b90610fdf565b60405180910390fd5b80600160008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508173ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925836040516107829190610c74565b60405180910390a3505050565b600061079b8484610537565b90507fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff81146108155781811015610807576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016107fe9061104b565b60405180910390fd5b61081484848484036105c6565b5b50505050565b600073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff160361088a576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610881906110dd565b60405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff16036108f9576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016108f09061116f565b60405180910390fd5b610904838383610a9a565b60008060008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000205490508181101561098a576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161098190611201565b60405180910390fd5b8181036000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002081905550816000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000206000828254610a1d9190610e15565b925050819055508273ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef84604051610a819190610c74565b60405180910390a3610a94848484610a9f565b50505050565b505050565b505050565b600081519050919050565b600082825260208201905092915050565b60005b83811015610ade578082015181840152602081019050610ac3565b60008484015250505050565b6000601f19601f8301169050919050565b6000610b0682610aa4565b610b108185610aaf565b9350610b20818560208601610ac0565b610b2981610aea565b840191505092915050565b60006020820190508181036000830152610b4e8184610afb565b905092915050565b600080fd5b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b6000610b8682610b5b565b9050919050565b610b9681610b7b565b8114610ba157600080fd5b50565b600081359050610bb381610b8d565b92915050565b6000819050919050565b610bcc81610bb9565b8114610bd757600080fd5b50565b600081359050610be981610bc3565b92915050565b60008060408385031215610c0657610c05610b56565b5b6000610c1485828601610ba4565b9250506020610c2585828601610bda565b9150509250929050565b60008115159050919050565b610c4481610c2f565b82525050565b6000602082019050610c5f6000830184610c3b565b92915050565b610c6e81610bb9565b82525050565b6000602082019050610c896000830184610c65565b92915050565b600080600060608486031215610ca857610ca7610b56565b5b6000610cb686828701610ba4565b9350506020610cc786828701610ba4565b9250506040610cd886828701610bda565b9150509250925092565b600060ff82169050919050565b610cf881610ce2565b82525050565b6000602082019050610d136000830184610cef565b92915050565b600060208284031215610d2f57610d2e610b56565b5b6000610d3d84828501610ba4565b91505092915050565b60008060408385031215610d5d57610d5c610b56565b5b6000610d6b85828601610ba4565b9250506020610d7c85828601610ba4565b9150509250929050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b60006002820490506001821680610dcd57607f821691505b602082108103610de057610ddf610d86565b5b50919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b6000610e2082610bb9565b9150610e2b83610bb9565b9250828201905080821115610e4357610e42610de6565b5b92915050565b7f45524332303a2064656372656173656420616c6c6f77616e63652062656c6f7760008201527f207a65726f000000000000000000000000000000000000000000000000000000602082015250565b6000610ea5602583610aaf565b9150610eb082610e49565b604082019050919050565b60006020820190508181036000830152610ed481610e98565b9050919050565b7f45524332303a20617070726f76652066726f6d20746865207a65726f2061646460008201527f7265737300000000000000000000000000000000000000000000000000000000602082015250565b6000610f37602483610aaf565b9150610f4282610edb565b604082019050919050565b60006020820190508181036000830152610f6681610f2a565b9050919050565b7f45524332303a20617070726f766520746f20746865207a65726f20616464726560008201527f7373000000000000000000000000000000000000000000000000000000000000602082015250565b6000610fc9602283610aaf565b9150610fd482610f6d565b604082019050919050565b60006020820190508181036000830152610ff881610fbc565b9050919050565b7f45524332303a20696e73756666696369656e7420616c6c6f77616e6365000000600082015250565b6000611035601d83610aaf565b915061104082610fff565b602082019050919050565b6000602082019050818103600083015261106481611028565b9050919050565b7f45524332303a207472616e736665722066726f6d20746865207a65726f20616460008201527f6472657373000000000000000000000000000000000000000000000000000000602082015250565b60006110c7602583610aaf565b91506110d28261106b565b604082019050919050565b600060208201905081810360008301526110f6816110ba565b9050919050565b7f45524332303a207472616e7366657220746f20746865207a65726f206164647260008201527f6573730000000000000000000000000000000000000000000000000000000000602082015250565b6000611159602383610aaf565b9150611164826110fd565b604082019050919050565b600060208201905081810360008301526111888161114c565b9050919050565b7f45524332303a207472616e7366657220616d6f756e742065786365656473206260008201527f616c616e63650000000000000000000000000000000000000000000000000000602082015250565b60006111eb602683610aaf565b91506111f68261118f565b604082019050919050565b6000602082019050818103600083015261121a816111de565b905091905056fea264697066735822122043bdf38fa4902fbfc44b02ece552929110686acf46db3bc611e5a6f2e10ab88664736f6c63430008150033",
 }
 
 // TokenABI is the input ABI used to generate the binding from.
@@ -43,7 +43,7 @@ var TokenABI = TokenMetaData.ABI
 var TokenBin = TokenMetaData.Bin
 
 // DeployToken deploys a new Ethereum contract, binding an instance of Token to it.
-func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *Token, error) {
+func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend, name_ string, symbol_ string, decimals_ uint8, initialSupply *big.Int, holders []common.Address) (common.Address, *types.Transaction, *Token, error) {
 	parsed, err := TokenMetaData.GetAbi()
 	if err != nil {
 		return common.Address{}, nil, nil, err
@@ -52,7 +52,7 @@ func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend) (common.
 		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
 	}
 
-	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(TokenBin), backend)
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(TokenBin), backend, name_, symbol_, decimals_, initialSupply, holders)
 	if err != nil {
 		return common.Address{}, nil, nil, err
 	}
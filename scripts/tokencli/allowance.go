@@ -0,0 +1,89 @@
+/** allowance.go contains the allowance subcommand, which queries the amount of
+  Tokens a spender may transfer from an owner.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// allowanceResult describes the allowance of a spender over an owner's Tokens
+type allowanceResult struct {
+	Contract  string `json:"contract"`
+	Owner     string `json:"owner"`
+	Spender   string `json:"spender"`
+	Allowance string `json:"allowance"`
+	Readable  string `json:"readable"`
+}
+
+func (r allowanceResult) fields() []field {
+	return []field{
+		{"Contract", r.Contract},
+		{"Owner", r.Owner},
+		{"Spender", r.Spender},
+		{"Allowance", r.Readable},
+	}
+}
+
+func init() {
+	register(&command{
+		name:        "allowance",
+		usage:       "[-contract address|name] -owner address -spender address",
+		description: "Show the amount of Tokens a spender may transfer from an owner",
+		run:         runAllowance,
+	})
+}
+
+func runAllowance(args []string) error {
+	fs, opts := newFlagSet(commands["allowance"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	ownerFlag := fs.String("owner", "", "address of the token owner")
+	spenderFlag := fs.String("spender", "", "address of the spender")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	owner, err := parseAddress("owner", *ownerFlag)
+	if err != nil {
+		return err
+	}
+	spender, err := parseAddress("spender", *spenderFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	allowance, err := instance.Allowance(&bind.CallOpts{}, owner, spender)
+	if err != nil {
+		return fmt.Errorf("failed to get allowance: %w", err)
+	}
+
+	return opts.print(allowanceResult{
+		Contract:  contract.Hex(),
+		Owner:     owner.Hex(),
+		Spender:   spender.Hex(),
+		Allowance: allowance.String(),
+		Readable:  formatAmount(allowance, meta),
+	})
+}
@@ -0,0 +1,93 @@
+/** approve.go contains the approve subcommand, which sets the allowance of a
+  spender over the sender's Tokens.
+*/
+
+package main
+
+import (
+	"fmt"
+)
+
+// approveResult describes a confirmed Token approval
+type approveResult struct {
+	Spender string `json:"spender"`
+	Amount  string `json:"amount"`
+	txResult
+}
+
+func (r approveResult) fields() []field {
+	return append([]field{{"Spender", r.Spender}, {"Amount", r.Amount}}, r.txResult.fields()...)
+}
+
+func init() {
+	register(&command{
+		name:        "approve",
+		usage:       "[-contract address|name] -spender address -amount tokens [-from key]",
+		description: "Approve a spender to transfer Tokens from the sender",
+		run:         runApprove,
+	})
+}
+
+func runApprove(args []string) error {
+	fs, opts := newFlagSet(commands["approve"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	spenderFlag := fs.String("spender", "", "address of the spender")
+	amountFlag := fs.String("amount", "", "amount of tokens to approve, such as 10.5 or \"10.5 TOK\"")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	spender, err := parseAddress("spender", *spenderFlag)
+	if err != nil {
+		return err
+	}
+	if err := requireFlag("amount", *amountFlag); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	amount, err := parseAmount("amount", *amountFlag, meta)
+	if err != nil {
+		return err
+	}
+
+	auth, owner, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+
+	tx, err := instance.Approve(auth, spender, amount)
+	if err != nil {
+		return fmt.Errorf("failed to approve tokens: %w", err)
+	}
+
+	receipt, err := opts.waitForReceipt(tx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm approval: %w", err)
+	}
+
+	return opts.print(approveResult{
+		Spender:  spender.Hex(),
+		Amount:   formatAmount(amount, meta),
+		txResult: newTxResult(owner, receipt),
+	})
+}
@@ -0,0 +1,120 @@
+/** balance.go contains the balance subcommand, which queries the Token balance
+  of an account, and its native aevmos balance from the bank module.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// balanceResult describes the Token and native balances of an account
+type balanceResult struct {
+	Contract       string `json:"contract"`
+	Account        string `json:"account"`
+	Bech32         string `json:"bech32"`
+	Balance        string `json:"balance"`
+	Readable       string `json:"readable"`
+	Native         string `json:"native"`
+	NativeReadable string `json:"native_readable"`
+	// NativeSource is the bank module, or the JSON-RPC node without a reachable REST endpoint
+	NativeSource string `json:"native_source"`
+}
+
+func (r balanceResult) fields() []field {
+	return []field{
+		{"Contract", r.Contract},
+		{"Account", r.Account},
+		{"Bech32 account", r.Bech32},
+		{"Balance", r.Readable},
+		{"Native balance", r.NativeReadable},
+		{"Native balance from", r.NativeSource},
+	}
+}
+
+func init() {
+	register(&command{
+		name:        "balance",
+		usage:       "[-contract address|name] -account address",
+		description: "Show the Token and native aevmos balances of an account",
+		run:         runBalance,
+	})
+}
+
+func runBalance(args []string) error {
+	fs, opts := newFlagSet(commands["balance"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	accountFlag := fs.String("account", "", "0x hex or evmos1 bech32 address of the account to query")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	account, err := parseAddress("account", *accountFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	balance, err := instance.BalanceOf(&bind.CallOpts{}, account)
+	if err != nil {
+		return fmt.Errorf("failed to get token balance: %w", err)
+	}
+
+	// The EVM balance is the bank balance of aevmos, so it is read from the node when
+	// the profile has no REST endpoint, or the node does not serve its API
+	ctx := context.Background()
+	source := "bank"
+	native, err := util.GetBankBalance(ctx, opts.profile.REST, account, util.NativeDenom)
+	if errors.Is(err, util.ErrRESTUnreachable) {
+		fmt.Fprintf(os.Stderr, "Warning: %v, reading the %s balance with eth_getBalance\n", err, util.NativeDenom)
+	}
+	if errors.Is(err, util.ErrNoRESTEndpoint) || errors.Is(err, util.ErrRESTUnreachable) {
+		source = "eth_getBalance"
+		native, err = opts.client.BalanceAt(ctx, account, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s balance: %w", util.NativeDenom, err)
+	}
+
+	return opts.print(balanceResult{
+		Contract:       contract.Hex(),
+		Account:        account.Hex(),
+		Bech32:         util.AddressToBech32(account),
+		Balance:        balance.String(),
+		Readable:       formatAmount(balance, meta),
+		Native:         native.String(),
+		NativeReadable: formatNative(native),
+		NativeSource:   source,
+	})
+}
+
+// formatNative returns an aevmos amount in EVMOS, with the amount in aevmos
+func formatNative(amount *big.Int) string {
+	return fmt.Sprintf("%s (%s %s)", util.FormatTokenAmount(amount, util.NativeDecimals, "EVMOS"), amount, util.NativeDenom)
+}
@@ -0,0 +1,234 @@
+/** batch_transfer.go contains the batch-transfer subcommand, which transfers Tokens
+  from the sender to every recipient of a CSV file, recording its progress in a
+  checkpoint file so that an interrupted run can be resumed.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// batchRecipient describes the reconciled balance of a single batch recipient
+type batchRecipient struct {
+	Line     int    `json:"line"`
+	To       string `json:"to"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Match    bool   `json:"match"`
+}
+
+// batchResult describes a completed batch transfer
+type batchResult struct {
+	Contract   string           `json:"contract"`
+	From       string           `json:"from"`
+	Checkpoint string           `json:"checkpoint"`
+	Total      string           `json:"total"`
+	Succeeded  int              `json:"succeeded"`
+	Reverted   int              `json:"reverted"`
+	Mismatches int              `json:"mismatches"`
+	Recipients []batchRecipient `json:"recipients"`
+}
+
+func (r batchResult) fields() []field {
+	fields := []field{
+		{"Contract", r.Contract},
+		{"From", r.From},
+		{"Checkpoint", r.Checkpoint},
+		{"Total", r.Total},
+		{"Succeeded", r.Succeeded},
+		{"Reverted", r.Reverted},
+		{"Mismatches", r.Mismatches},
+	}
+	for _, recipient := range r.Recipients {
+		check := "ok"
+		if !recipient.Match {
+			check = "MISMATCH"
+		}
+		status := recipient.Status
+		if recipient.Reason != "" {
+			status = fmt.Sprintf("%s (%s)", status, recipient.Reason)
+		}
+		fields = append(fields, field{
+			fmt.Sprintf("Line %d %s", recipient.Line, recipient.To),
+			fmt.Sprintf("%s, expected %s, actual %s, %s", status, recipient.Expected, recipient.Actual, check),
+		})
+	}
+	return fields
+}
+
+func init() {
+	register(&command{
+		name:        "batch-transfer",
+		usage:       "[-contract address|name] -file transfers.csv [-checkpoint file] [-from key]",
+		description: "Transfer Tokens from the sender to every address,amount row of a CSV file",
+		run:         runBatchTransfer,
+	})
+}
+
+func runBatchTransfer(args []string) error {
+	fs, opts := newFlagSet(commands["batch-transfer"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	fileFlag := fs.String("file", "", "CSV file of address,amount rows, with token amounts such as 10.5")
+	checkpointFlag := fs.String("checkpoint", "", "checkpoint file recording the progress of the batch (default <file>.checkpoint.json)")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	if *fileFlag == "" {
+		return errors.New("-file is required")
+	}
+	checkpointFile := *checkpointFlag
+	if checkpointFile == "" {
+		checkpointFile = *fileFlag + ".checkpoint.json"
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	auth, sender, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+
+	// Validate every row before anything is sent
+	rows, input, err := util.ReadTransferCSV(*fileFlag, sender, meta.Decimals)
+	if err != nil {
+		return err
+	}
+
+	balanceOf := func(account common.Address) (*big.Int, error) {
+		return instance.BalanceOf(&bind.CallOpts{}, account)
+	}
+
+	ctx := context.Background()
+	checkpoint, err := util.LoadBatchCheckpoint(checkpointFile)
+	switch {
+	case err == nil:
+		if err := checkpoint.Matches(opts.profile.Name, contract, sender, input); err != nil {
+			return fmt.Errorf("cannot resume from %s: %w", checkpointFile, err)
+		}
+		fmt.Fprintf(os.Stderr, "Resuming from %s: %d of %d transfers succeeded\n", checkpointFile, checkpoint.Count(util.BatchSuccess), len(checkpoint.Rows))
+		if err := checkpoint.Resume(ctx, opts.client, checkpointFile); err != nil {
+			return err
+		}
+	case errors.Is(err, os.ErrNotExist):
+		checkpoint, err = util.NewBatchCheckpoint(opts.profile.Name, contract, sender, input, rows, balanceOf)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	// Check the sender can cover every transfer still to be sent
+	unsent := checkpoint.Unsent()
+	balance, err := balanceOf(sender)
+	if err != nil {
+		return fmt.Errorf("failed to get sender balance: %w", err)
+	}
+	if balance.Cmp(unsent) < 0 {
+		return fmt.Errorf("sender balance %s does not cover the batch total %s", formatAmount(balance, meta), formatAmount(unsent, meta))
+	}
+
+	if err := checkpoint.Save(checkpointFile); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	nonces := util.NewNonceManager(opts.client)
+	transfer := func(txOpts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+		return instance.Transfer(txOpts, to, amount)
+	}
+	if err := checkpoint.Send(ctx, nonces, auth, transfer, checkpointFile); err != nil {
+		return err
+	}
+
+	backend, closeBackend, err := util.GetReceiptBackend(opts.profile, opts.client, opts.wait)
+	if err != nil {
+		return fmt.Errorf("failed to get receipt backend: %w", err)
+	}
+	defer closeBackend()
+	if err := checkpoint.Confirm(ctx, backend, opts.wait, checkpointFile); err != nil {
+		return err
+	}
+	if err := checkpoint.Explain(ctx, opts.client, revertABIs(), checkpointFile); err != nil {
+		return err
+	}
+
+	reconciliation, err := checkpoint.Reconcile(balanceOf)
+	if err != nil {
+		return err
+	}
+
+	result := batchResult{
+		Contract:   contract.Hex(),
+		From:       sender.Hex(),
+		Checkpoint: checkpointFile,
+		Succeeded:  checkpoint.Count(util.BatchSuccess),
+		Reverted:   checkpoint.Count(util.BatchReverted),
+	}
+	total := new(big.Int)
+	var reasons []string
+	for i, r := range reconciliation {
+		if reason := checkpoint.Rows[i].Reason; reason != "" {
+			reasons = append(reasons, fmt.Sprintf("line %d: %s", r.Line, reason))
+		}
+		total.Add(total, checkpoint.Rows[i].Amount)
+		if !r.Match {
+			result.Mismatches++
+		}
+		result.Recipients = append(result.Recipients, batchRecipient{
+			Line:     r.Line,
+			To:       r.To.Hex(),
+			Status:   r.Status,
+			Reason:   checkpoint.Rows[i].Reason,
+			Expected: formatAmount(r.Expected, meta),
+			Actual:   formatAmount(r.Actual, meta),
+			Match:    r.Match,
+		})
+	}
+	result.Total = formatAmount(total, meta)
+
+	if err := opts.print(result); err != nil {
+		return err
+	}
+
+	if result.Reverted > 0 || result.Mismatches > 0 {
+		err := fmt.Errorf("%d transfers reverted and %d balances do not match, rerun to retry reverted transfers", result.Reverted, result.Mismatches)
+		if len(reasons) > 0 {
+			err = fmt.Errorf("%w (%s)", err, strings.Join(reasons, "; "))
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+/** contract.go contains the call and send subcommands, which call or send any method
+  of a contract by name, using an ABI compiled into contract/build. Arguments are
+  converted from the command line to the method's ABI types, and return values and
+  emitted events are decoded for display.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// methodCall is a method of a contract, with its parsed arguments
+type methodCall struct {
+	address common.Address
+	abi     *abi.ABI
+	method  *abi.Method
+	args    []interface{}
+}
+
+// callResult describes the return values of a method call
+type callResult struct {
+	Contract string            `json:"contract"`
+	Method   string            `json:"method"`
+	Outputs  []util.NamedValue `json:"outputs"`
+}
+
+func (r callResult) fields() []field {
+	fields := []field{{"Contract", r.Contract}, {"Method", r.Method}}
+	for i, output := range r.Outputs {
+		fields = append(fields, field{valueName("Output", i, output), displayValue(output.Value)})
+	}
+	return fields
+}
+
+// sendResult describes a confirmed method transaction, and the events it emitted
+type sendResult struct {
+	Contract string              `json:"contract"`
+	Method   string              `json:"method"`
+	Events   []util.DecodedEvent `json:"events"`
+	txResult
+}
+
+func (r sendResult) fields() []field {
+	fields := append([]field{{"Contract", r.Contract}, {"Method", r.Method}}, r.txResult.fields()...)
+	for _, event := range r.Events {
+		fields = append(fields, field{fmt.Sprintf("Event %d", event.LogIndex), event.Name})
+		for i, arg := range event.Args {
+			fields = append(fields, field{"  " + valueName("Arg", i, arg), displayValue(arg.Value)})
+		}
+	}
+	return fields
+}
+
+// valueName returns the name of a return value or event argument, or its position
+// if it has no name
+func valueName(kind string, i int, value util.NamedValue) string {
+	if value.Name == "" {
+		return fmt.Sprintf("%s %d (%s)", kind, i, value.Type)
+	}
+	return fmt.Sprintf("%s (%s)", value.Name, value.Type)
+}
+
+// displayValue returns a formatted value for a table, with arrays and tuples as JSON
+func displayValue(value interface{}) interface{} {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return value
+}
+
+func init() {
+	register(&command{
+		name:        "call",
+		usage:       "[-contract address|name] [-abi name|file.abi] method [args...]",
+		description: "Call any method of a contract by name, without sending a transaction",
+		run:         runCall,
+	})
+	register(&command{
+		name:        "send",
+		usage:       "[-contract address|name] [-abi name|file.abi] [-value amount] method [args...]",
+		description: "Send a transaction calling any method of a contract by name",
+		run:         runSend,
+	})
+}
+
+// registerContractFlags registers the flags selecting the contract and its ABI, and
+// returns a function resolving the method call from the positional arguments
+func (o *options) registerContractFlags(fs *flag.FlagSet) func(positional []string) (*methodCall, error) {
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the contract")
+	abiFlag := fs.String("abi", "", "contract name in the build directory, or path of an .abi file (default the -contract manifest name)")
+	buildDirFlag := fs.String("build-dir", util.DefaultBuildDir, "directory of the compiled contract ABI files")
+
+	return func(positional []string) (*methodCall, error) {
+		if len(positional) == 0 {
+			return nil, errors.New("a method name or signature is required")
+		}
+
+		abiName := *abiFlag
+		if abiName == "" {
+			if util.IsAddress(*contractFlag) {
+				return nil, errors.New("-abi is required when -contract is an address")
+			}
+			abiName = *contractFlag
+		}
+
+		contractABI, err := util.LoadABI(*buildDirFlag, abiName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ABI: %w", err)
+		}
+
+		method, err := util.FindMethod(contractABI, positional[0])
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := util.ParseArgs(method.Inputs, positional[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", method.Sig, err)
+		}
+
+		if err := o.connect(); err != nil {
+			return nil, err
+		}
+
+		address, err := o.resolveContract(*contractFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		return &methodCall{address: address, abi: contractABI, method: method, args: args}, nil
+	}
+}
+
+func runCall(args []string) error {
+	fs, opts := newFlagSet(commands["call"])
+	resolve := opts.registerContractFlags(fs)
+	positional, err := opts.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	call, err := resolve(positional)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := util.CallMethod(context.Background(), opts.client, call.address, call.abi, call.method, call.args)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", call.method.Sig, err)
+	}
+
+	return opts.print(callResult{
+		Contract: call.address.Hex(),
+		Method:   call.method.Sig,
+		Outputs:  outputs,
+	})
+}
+
+func runSend(args []string) error {
+	fs, opts := newFlagSet(commands["send"])
+	opts.registerTxFlags(fs)
+	resolve := opts.registerContractFlags(fs)
+	valueFlag := fs.String("value", "", "amount of the native token to send to a payable method, such as 0.5")
+	positional, err := opts.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	value := new(big.Int)
+	if *valueFlag != "" {
+		if value, err = util.ParseAmount(*valueFlag, util.NativeDecimals); err != nil {
+			return fmt.Errorf("invalid -value: %w", err)
+		}
+	}
+
+	call, err := resolve(positional)
+	if err != nil {
+		return err
+	}
+
+	if call.method.IsConstant() {
+		return fmt.Errorf("%s is a %s method, use call instead", call.method.Sig, call.method.StateMutability)
+	}
+	if value.Sign() > 0 && !call.method.IsPayable() {
+		return fmt.Errorf("%s is not payable, -value cannot be sent", call.method.Sig)
+	}
+
+	auth, sender, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+	auth.Value = value
+
+	backend := opts.backend()
+	contract := bind.NewBoundContract(call.address, *call.abi, backend, backend, backend)
+	tx, err := contract.Transact(auth, call.method.Name, call.args...)
+	if err != nil {
+		return fmt.Errorf("failed to send %s: %w", call.method.Sig, err)
+	}
+
+	receipt, err := opts.waitForReceipt(tx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm %s: %w", call.method.Sig, err)
+	}
+
+	events, err := util.DecodeLogs(call.abi, call.address, receipt.Logs)
+	if err != nil {
+		return err
+	}
+
+	return opts.print(sendResult{
+		Contract: call.address.Hex(),
+		Method:   call.method.Sig,
+		Events:   events,
+		txResult: newTxResult(sender, receipt),
+	})
+}
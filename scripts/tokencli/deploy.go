@@ -0,0 +1,502 @@
+/** deploy.go contains the deploy subcommand, which deploys the Token contract, or
+  any contract of contract/build, and records the deployment in the deployment
+  manifest. The name, symbol, decimals, initial supply and initial holders of Token
+  are given with flags, and validated before anything is sent. With -salt, the
+  contract is deployed through a CREATE2 factory, so that its address only depends
+  on the factory, the salt and the creation code. With -proxy, it is deployed as the
+  implementation of an upgradeable ERC-1967 proxy, TokenUpgradeable taking the place
+  of Token. The proxy artifact and the initializer are checked before anything is
+  sent. With -feesplit, the contract is registered with the x/feesplit module once
+  mined.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// deployResult describes a confirmed deployment, or a CREATE2 deployment skipped as
+// the contract already exists
+type deployResult struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Manifest string `json:"manifest"`
+	Factory  string `json:"factory,omitempty"`
+	Salt     string `json:"salt,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Proxy    string `json:"proxy,omitempty"`
+	// Implementation is the implementation contract behind a proxy
+	Implementation string `json:"implementation,omitempty"`
+	FeeSplitTx     string `json:"feesplit_tx,omitempty"`
+	// Token holds the constructor parameters of a Token deployment
+	Token *util.TokenParams `json:"token,omitempty"`
+	*txResult
+}
+
+func (r deployResult) fields() []field {
+	fields := []field{
+		{"Contract", r.Name},
+		{"Contract address", r.Address},
+		{"Manifest", r.Manifest},
+	}
+	if r.Token != nil {
+		holders := make([]string, len(r.Token.Holders))
+		for i, holder := range r.Token.Holders {
+			holders[i] = holder.Hex()
+		}
+		fields = append(fields,
+			field{"Token name", r.Token.Name},
+			field{"Symbol", r.Token.Symbol},
+			field{"Decimals", r.Token.Decimals},
+			field{"Initial supply", util.FormatTokenAmount(r.Token.InitialSupply, r.Token.Decimals, r.Token.Symbol)},
+			field{"Initial holders", strings.Join(holders, ", ")},
+		)
+	}
+	if r.Factory != "" {
+		fields = append(fields, field{"CREATE2 factory", r.Factory}, field{"Salt", r.Salt})
+	}
+	if r.Proxy != "" {
+		fields = append(fields, field{"Proxy", r.Proxy}, field{"Implementation", r.Implementation})
+	}
+	if r.Skipped {
+		return append(fields, field{"Status", "already deployed, skipped"})
+	}
+	fields = append(fields, r.txResult.fields()...)
+	if r.FeeSplitTx != "" {
+		fields = append(fields, field{"Fee split registration", r.FeeSplitTx})
+	}
+	return fields
+}
+
+func init() {
+	register(&command{
+		name:        "deploy",
+		usage:       "[-network name] [-name name] [-from key] [-token-name name] [-symbol symbol] [-decimals n] [-supply amount] [-holders addresses] [-artifact name] [-salt salt | -proxy kind] [-feesplit] [constructor args... | initializer args...]",
+		description: "Deploy the Token contract, or any compiled contract, and record it in the manifest",
+		run:         runDeploy,
+	})
+}
+
+func runDeploy(args []string) error {
+	fs, opts := newFlagSet(commands["deploy"])
+	opts.registerTxFlags(fs)
+	name := fs.String("name", "", "name to record the deployment under in the manifest (default the contract name)")
+	tokenParams := registerTokenFlags(fs)
+	artifactFlag := fs.String("artifact", "", "contract name in the build directory to deploy instead of Token, followed by its constructor arguments")
+	buildDirFlag := fs.String("build-dir", util.DefaultBuildDir, "directory of the compiled contract artifacts")
+	create2 := registerCreate2Flags(fs)
+	proxyFlag := fs.String("proxy", "", "deploy the contract as the implementation of a uups or transparent proxy, TokenUpgradeable initialised with the Token parameters, or the -artifact followed by its initializer method and arguments")
+	proxyAdminFlag := fs.String("proxy-admin", "", "admin of a transparent proxy (default the sender)")
+	feeSplitFlag := fs.Bool("feesplit", false, "register the contract with the x/feesplit module once it is mined, with the -from key")
+	withdrawerFlag := fs.String("feesplit-withdrawer", "", "address receiving the developer fees of -feesplit (default the deployer)")
+	positional, err := opts.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var params *util.TokenParams
+	if *artifactFlag == "" {
+		if len(positional) > 0 {
+			return fmt.Errorf("unexpected arguments: %v, the Token parameters are given with -token-name, -symbol, -decimals, -supply and -holders", positional)
+		}
+		if params, err = tokenParams.parse(); err != nil {
+			return err
+		}
+	} else if given := tokenParams.given(fs); len(given) > 0 {
+		return fmt.Errorf("-%s cannot be given with -artifact, give the constructor arguments of %s after the flags", strings.Join(given, ", -"), *artifactFlag)
+	}
+
+	// The implementation is the contract deployed, behind the proxy with -proxy
+	implementation := *artifactFlag
+	var proxyKind util.ProxyKind
+	var proxyArtifact util.Artifact
+	var proxyAdmin *common.Address
+	var initData []byte
+	if *proxyFlag != "" {
+		if proxyKind, err = util.ParseProxyKind(*proxyFlag); err != nil {
+			return fmt.Errorf("invalid -proxy: %w", err)
+		}
+		if *create2.salt != "" {
+			return errors.New("-salt and -proxy cannot be combined")
+		}
+		if proxyArtifact, err = readProxyArtifact(*buildDirFlag, proxyKind); err != nil {
+			return err
+		}
+		if *proxyAdminFlag != "" {
+			address, err := parseAddress("proxy-admin", *proxyAdminFlag)
+			if err != nil {
+				return err
+			}
+			proxyAdmin = &address
+		}
+
+		// The state set by the Token constructor would stay in the implementation, so
+		// TokenUpgradeable is initialised through the proxy once the owner is known
+		if *artifactFlag == "" {
+			implementation = util.TokenUpgradeableArtifact
+		} else if initData, err = initializerData(*buildDirFlag, *artifactFlag, positional); err != nil {
+			return err
+		}
+		positional = nil
+	}
+
+	if *name == "" {
+		*name = "Token"
+		if *artifactFlag != "" {
+			*name = *artifactFlag
+		}
+	}
+
+	var salt common.Hash
+	if *create2.salt != "" {
+		if salt, err = util.ParseSalt(*create2.salt); err != nil {
+			return fmt.Errorf("invalid -salt: %w", err)
+		}
+	}
+
+	var withdrawer *common.Address
+	if *feeSplitFlag {
+		// The address of a CREATE2 deployment is not derived from the deployer's nonce
+		if *create2.salt != "" {
+			return errors.New("-salt and -feesplit cannot be combined, as x/feesplit derives the contract address from the deployer's nonce")
+		}
+		if *withdrawerFlag != "" {
+			address, err := parseAddress("feesplit-withdrawer", *withdrawerFlag)
+			if err != nil {
+				return err
+			}
+			withdrawer = &address
+		}
+	}
+
+	// Load the manifest before deploying, so an invalid manifest is reported first
+	manifest, err := util.LoadManifest(opts.manifestFile)
+	if err != nil {
+		return fmt.Errorf("failed to load manifest: %w", err)
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	chainID, err := opts.client.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	auth, deployer, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+
+	// The supply is minted to the deployer by default. It is given as the holder, as
+	// the msg.sender of a CREATE2 constructor is the factory.
+	if params != nil && len(params.Holders) == 0 {
+		params.Holders = []common.Address{deployer}
+	}
+	admin := deployer
+	if proxyAdmin != nil {
+		admin = *proxyAdmin
+	}
+	if proxyKind != "" && params != nil {
+		if initData, err = tokenInitializerData(*buildDirFlag, proxyKind, params, deployer, admin); err != nil {
+			return err
+		}
+	}
+	metadata, creation, err := deployCode(implementation, *buildDirFlag, positional, params)
+	if err != nil {
+		return err
+	}
+
+	// Check that fee splits can be registered before deploying
+	var evmosd *util.Evmosd
+	if *feeSplitFlag {
+		if evmosd, err = opts.evmosd(); err != nil {
+			return err
+		}
+	}
+
+	result := deployResult{Name: *name, Manifest: opts.manifestFile, Token: params}
+	var deployment util.Deployment
+
+	if *create2.salt == "" {
+		// Deploy the contract as deployer
+		address, tx, _, err := bind.DeployContract(auth, abi.ABI{}, creation, opts.backend())
+		if err != nil {
+			return fmt.Errorf("failed to deploy contract: %w", err)
+		}
+
+		receipt, err := opts.waitForReceipt(tx)
+		if err != nil {
+			return fmt.Errorf("failed to confirm deployment: %w", err)
+		}
+
+		deployment = util.NewDeployment(*name, metadata, chainID, deployer, receipt)
+		result.Address = address.Hex()
+
+		if proxyKind != "" {
+			// The implementation is recorded under its own name, and the proxy under the
+			// deployment name, so that the other commands use the proxy
+			deployment.Contract = *name + "Implementation"
+			manifest.Record(opts.profile.Name, deployment)
+
+			// The next transaction takes the pending nonce, after the implementation
+			auth.Nonce = nil
+			if deployment, receipt, err = opts.deployProxy(auth, proxyArtifact, proxyKind, *name, address, admin, initData, chainID, deployer); err != nil {
+				return err
+			}
+			result.Address = deployment.Address.Hex()
+			result.Proxy = string(proxyKind)
+			result.Implementation = address.Hex()
+		}
+		deployment.Token = params
+
+		txResult := newTxResult(deployer, receipt)
+		result.txResult = &txResult
+	} else {
+		factory, err := opts.create2Factory(create2, manifest, auth, chainID, deployer)
+		if err != nil {
+			return err
+		}
+
+		address := util.PredictCreate2Address(factory, salt, creation)
+		result.Address = address.Hex()
+		result.Factory = factory.Hex()
+		result.Salt = salt.Hex()
+
+		code, err := opts.client.CodeAt(context.Background(), address, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get code at %s: %w", address.Hex(), err)
+		}
+
+		if len(code) > 0 {
+			result.Skipped = true
+			deployment = util.Deployment{
+				Contract:     *name,
+				Address:      address,
+				ChainID:      chainID.Int64(),
+				ABIHash:      crypto.Keccak256Hash([]byte(metadata.ABI)),
+				BytecodeHash: crypto.Keccak256Hash(common.FromHex(metadata.Bin)),
+				DeployedAt:   time.Now().UTC(),
+				Token:        params,
+			}
+
+			// Keep the entry of an earlier deployment to the same address
+			current, err := manifest.Lookup(opts.profile.Name, *name)
+			if err == nil && current.Address == address && current.BytecodeHash == deployment.BytecodeHash {
+				return opts.print(result)
+			}
+		} else {
+			tx, err := util.Create2Deploy(auth, opts.backend(), factory, salt, creation)
+			if err != nil {
+				return fmt.Errorf("failed to deploy contract through %s: %w", factory.Hex(), err)
+			}
+
+			receipt, err := opts.waitForReceipt(tx)
+			if err != nil {
+				return fmt.Errorf("failed to confirm deployment: %w", err)
+			}
+
+			deployment = util.NewDeployment(*name, metadata, chainID, deployer, receipt)
+			deployment.Address = address
+			deployment.Token = params
+			txResult := newTxResult(deployer, receipt)
+			result.txResult = &txResult
+		}
+	}
+
+	manifest.Record(opts.profile.Name, deployment)
+	if err := manifest.Save(opts.manifestFile); err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	if evmosd != nil {
+		if result.FeeSplitTx, err = opts.registerFeeSplit(evmosd, deployment, withdrawer); err != nil {
+			return fmt.Errorf("contract deployed to %s, but %w", deployment.Address.Hex(), err)
+		}
+	}
+
+	return opts.print(result)
+}
+
+// deployCode returns the metadata and creation code, including the encoded
+// constructor arguments, of the Token binding with the parameters, or of the named
+// build artifact
+func deployCode(artifactName, buildDir string, args []string, params *util.TokenParams) (*bind.MetaData, []byte, error) {
+	if artifactName == "" {
+		tokenABI, err := token.TokenMetaData.GetAbi()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid Token ABI: %w", err)
+		}
+		packed, err := tokenABI.Pack("", params.Name, params.Symbol, params.Decimals, params.InitialSupply, params.Holders)
+		if err != nil {
+			return nil, nil, fmt.Errorf("constructor: %w", err)
+		}
+		return token.TokenMetaData, append(common.FromHex(token.TokenMetaData.Bin), packed...), nil
+	}
+
+	artifact, err := util.ReadArtifact(buildDir, artifactName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	if artifact.Bin == "" {
+		return nil, nil, fmt.Errorf("%s has no bytecode, it may be an interface or abstract contract", artifactName)
+	}
+
+	contractABI, err := abi.JSON(bytes.NewReader(artifact.ABI))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ABI of %s: %w", artifactName, err)
+	}
+	values, err := util.ParseArgs(contractABI.Constructor.Inputs, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("constructor: %w", err)
+	}
+	packed, err := contractABI.Constructor.Inputs.Pack(values...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("constructor: %w", err)
+	}
+
+	metadata := &bind.MetaData{ABI: string(artifact.ABI), Bin: "0x" + artifact.Bin}
+	return metadata, append(common.FromHex(artifact.Bin), packed...), nil
+}
+
+// tokenFlags are the flags of the Token constructor parameters
+type tokenFlags struct {
+	name     *string
+	symbol   *string
+	decimals *uint
+	supply   *string
+	holders  *string
+}
+
+// tokenFlagNames are the names of the Token parameter flags
+var tokenFlagNames = []string{"token-name", "symbol", "decimals", "supply", "holders"}
+
+// registerTokenFlags registers the flags of the Token constructor parameters, which
+// default to the parameters of the original Token contract
+func registerTokenFlags(fs *flag.FlagSet) tokenFlags {
+	defaults := util.DefaultTokenParams
+	return tokenFlags{
+		name:     fs.String("token-name", defaults.Name, "name of the Token contract"),
+		symbol:   fs.String("symbol", defaults.Symbol, "symbol of the Token contract"),
+		decimals: fs.Uint("decimals", uint(defaults.Decimals), "decimals of the Token contract"),
+		supply:   fs.String("supply", util.FormatAmount(defaults.InitialSupply, defaults.Decimals), "initial supply of the Token contract in whole tokens, such as 1000.5"),
+		holders:  fs.String("holders", "", "comma separated addresses the Token supply is split evenly between (default the deployer)"),
+	}
+}
+
+// given returns the names of the Token parameter flags given on the command line
+func (f tokenFlags) given(fs *flag.FlagSet) []string {
+	var given []string
+	fs.Visit(func(fl *flag.Flag) {
+		for _, name := range tokenFlagNames {
+			if fl.Name == name {
+				given = append(given, name)
+			}
+		}
+	})
+	return given
+}
+
+// parse validates and returns the Token parameters of the flags
+func (f tokenFlags) parse() (*util.TokenParams, error) {
+	if *f.decimals > util.MaxTokenDecimals {
+		return nil, fmt.Errorf("invalid -decimals %d, at most %d are supported", *f.decimals, util.MaxTokenDecimals)
+	}
+	params := &util.TokenParams{Name: *f.name, Symbol: *f.symbol, Decimals: uint8(*f.decimals)}
+
+	supply, err := util.ParseAmount(*f.supply, params.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -supply: %w", err)
+	}
+	params.InitialSupply = supply
+
+	if params.Holders, err = parseAddressList("holders", *f.holders); err != nil {
+		return nil, err
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Token parameters: %w", err)
+	}
+	return params, nil
+}
+
+// create2Flags are the flags of CREATE2 deployments
+type create2Flags struct {
+	salt          *string
+	factory       *string
+	deployFactory *bool
+}
+
+// registerCreate2Flags registers the flags of CREATE2 deployments
+func registerCreate2Flags(fs *flag.FlagSet) create2Flags {
+	return create2Flags{
+		salt:          fs.String("salt", "", "deploy through the CREATE2 factory with this salt, given as 0x hex or as text that is hashed"),
+		factory:       fs.String("factory", "", "address of the CREATE2 factory (default the manifest "+util.Create2FactoryName+")"),
+		deployFactory: fs.Bool("deploy-factory", false, "deploy the CREATE2 factory if it is missing, which is done by default on the localnet"),
+	}
+}
+
+// create2Factory returns the address of the CREATE2 factory, given with -factory or
+// recorded in the manifest. A missing factory is deployed and recorded in the
+// manifest on the localnet, or on any network with -deploy-factory.
+func (o *options) create2Factory(flags create2Flags, manifest *util.Manifest, auth *bind.TransactOpts, chainID *big.Int, deployer common.Address) (common.Address, error) {
+	ctx := context.Background()
+
+	if *flags.factory != "" {
+		factory, err := parseAddress("factory", *flags.factory)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return factory, util.CheckCreate2Factory(ctx, o.client, factory)
+	}
+
+	// A factory recorded before the localnet was restarted has no code
+	if recorded, err := manifest.Lookup(o.profile.Name, util.Create2FactoryName); err == nil && recorded.ChainID == chainID.Int64() {
+		err := util.CheckCreate2Factory(ctx, o.client, recorded.Address)
+		if err == nil {
+			return recorded.Address, nil
+		}
+		if !errors.Is(err, util.ErrNoCode) {
+			return common.Address{}, err
+		}
+	}
+
+	if !*flags.deployFactory && chainID.Int64() != util.LocalNetwork.ChainID {
+		return common.Address{}, fmt.Errorf("no CREATE2 factory is recorded for network %q, give its address with -factory, or deploy one with -deploy-factory", o.profile.Name)
+	}
+
+	_, tx, err := util.DeployCreate2Factory(auth, o.backend())
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to deploy CREATE2 factory: %w", err)
+	}
+	receipt, err := o.waitForReceipt(tx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to confirm CREATE2 factory deployment: %w", err)
+	}
+	// Save the factory straight away, so it is reused even if the deployment fails
+	manifest.Record(o.profile.Name, util.NewDeployment(util.Create2FactoryName, util.Create2FactoryMetaData, chainID, deployer, receipt))
+	if err := manifest.Save(o.manifestFile); err != nil {
+		return common.Address{}, fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	// The next transaction takes the pending nonce, after the factory deployment
+	auth.Nonce = nil
+	return receipt.ContractAddress, nil
+}
@@ -0,0 +1,385 @@
+/** erc20_module.go contains the register-erc20, token-pairs, convert-erc20 and
+  convert-coin subcommands, which pair the deployed Token with a Cosmos coin through
+  the evmos x/erc20 module, and convert Tokens between the ERC20 contract and the
+  erc20/0x... coin. Cosmos transactions are signed and sent by the evmosd binary,
+  with the -from key of the evmosd keyring.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// pairBalances are the balances of an account on both sides of a token pair
+type pairBalances struct {
+	Account string `json:"account"`
+	ERC20   string `json:"erc20"`
+	Coin    string `json:"coin"`
+}
+
+// pairBalanceFields returns the table rows of the balances of each account
+func pairBalanceFields(label string, balances []pairBalances) []field {
+	var fields []field
+	for _, b := range balances {
+		fields = append(fields,
+			field{fmt.Sprintf("%s %s ERC20", label, b.Account), b.ERC20},
+			field{fmt.Sprintf("%s %s coin", label, b.Account), b.Coin},
+		)
+	}
+	return fields
+}
+
+// registerResult describes the registration of an ERC20 contract with the erc20 module
+type registerResult struct {
+	Contract   string         `json:"contract"`
+	ProposalID uint64         `json:"proposal_id,omitempty"`
+	SubmitTx   string         `json:"submit_tx,omitempty"`
+	VoteTx     string         `json:"vote_tx,omitempty"`
+	Status     string         `json:"status"`
+	Pair       util.TokenPair `json:"token_pair"`
+	Balances   []pairBalances `json:"balances"`
+}
+
+func (r registerResult) fields() []field {
+	fields := []field{{"Contract", r.Contract}}
+	if r.ProposalID != 0 {
+		fields = append(fields, field{"Proposal", r.ProposalID}, field{"Submit tx", r.SubmitTx})
+	}
+	if r.VoteTx != "" {
+		fields = append(fields, field{"Vote tx", r.VoteTx})
+	}
+	fields = append(fields,
+		field{"Status", r.Status},
+		field{"Coin", r.Pair.Denom},
+		field{"Conversion enabled", r.Pair.Enabled},
+	)
+	return append(fields, pairBalanceFields("Balance", r.Balances)...)
+}
+
+// tokenPairsResult lists the token pairs of the erc20 module
+type tokenPairsResult struct {
+	Pairs []util.TokenPair `json:"token_pairs"`
+}
+
+func (r tokenPairsResult) fields() []field {
+	fields := []field{{"Token pairs", len(r.Pairs)}}
+	for _, pair := range r.Pairs {
+		status := "enabled"
+		if !pair.Enabled {
+			status = "disabled"
+		}
+		fields = append(fields, field{pair.ERC20Address, fmt.Sprintf("%s (%s, %s)", pair.Denom, status, pair.ContractOwner)})
+	}
+	return fields
+}
+
+// convertResult describes a conversion between ERC20 tokens and paired coins, with the
+// balances of the sender and receiver before and after it
+type convertResult struct {
+	Contract string         `json:"contract"`
+	Denom    string         `json:"denom"`
+	Amount   string         `json:"amount"`
+	TxHash   string         `json:"tx_hash"`
+	Height   string         `json:"height"`
+	GasUsed  string         `json:"gas_used"`
+	Before   []pairBalances `json:"before"`
+	After    []pairBalances `json:"after"`
+}
+
+func (r convertResult) fields() []field {
+	fields := []field{
+		{"Contract", r.Contract},
+		{"Coin", r.Denom},
+		{"Amount", r.Amount},
+		{"Tx hash", r.TxHash},
+		{"Height", r.Height},
+		{"Gas used", r.GasUsed},
+	}
+	fields = append(fields, pairBalanceFields("Before", r.Before)...)
+	return append(fields, pairBalanceFields("After", r.After)...)
+}
+
+func init() {
+	register(&command{
+		name:        "register-erc20",
+		usage:       "[-contract address|name] [-from key] [-deposit coins] [-vote=false]",
+		description: "Register the Token with the erc20 module by governance proposal, and wait for its token pair",
+		run:         runRegisterERC20,
+	})
+	register(&command{
+		name:        "token-pairs",
+		usage:       "",
+		description: "List the token pairs of the erc20 module",
+		run:         runTokenPairs,
+	})
+	register(&command{
+		name:        "convert-erc20",
+		usage:       "[-contract address|name] -amount tokens [-receiver address] [-from key]",
+		description: "Convert Tokens of the sender into paired erc20/0x... coins",
+		run:         runConvertERC20,
+	})
+	register(&command{
+		name:        "convert-coin",
+		usage:       "[-contract address|name] -amount tokens [-receiver address] [-from key]",
+		description: "Convert paired erc20/0x... coins of the sender back into Tokens",
+		run:         runConvertCoin,
+	})
+}
+
+// erc20Module is the connection used by the erc20 module subcommands
+type erc20Module struct {
+	opts     *options
+	evmosd   *util.Evmosd
+	contract common.Address
+	instance *token.Token
+	meta     tokenMetadata
+	sender   common.Address
+}
+
+// connectERC20Module connects to the node and to the Token contract, and prepares
+// evmosd to send Cosmos transactions with the -from key
+func (o *options) connectERC20Module(contractFlag string) (*erc20Module, error) {
+	if err := o.connect(); err != nil {
+		return nil, err
+	}
+	if o.profile.REST == "" {
+		return nil, fmt.Errorf("network profile %q has no rest endpoint", o.profile.Name)
+	}
+
+	contract, err := o.resolveContract(contractFlag)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := o.token(contract)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	evmosd, err := o.evmosd()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := o.signer.NewSigner()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signer: %w", err)
+	}
+
+	return &erc20Module{
+		opts:     o,
+		evmosd:   evmosd,
+		contract: contract,
+		instance: instance,
+		meta:     meta,
+		sender:   signer.Address(),
+	}, nil
+}
+
+// balances returns the ERC20 and coin balances of each account
+func (m *erc20Module) balances(accounts ...common.Address) ([]pairBalances, error) {
+	var balances []pairBalances
+	for i, account := range accounts {
+		// The receiver is often the sender
+		if i > 0 && account == accounts[0] {
+			continue
+		}
+
+		erc20, err := m.instance.BalanceOf(&bind.CallOpts{}, account)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token balance: %w", err)
+		}
+		coin, err := util.GetBankBalance(context.Background(), m.opts.profile.REST, account, util.ERC20Denom(m.contract))
+		if err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, pairBalances{
+			Account: util.AddressToBech32(account),
+			ERC20:   formatAmount(erc20, m.meta),
+			Coin:    util.FormatTokenAmount(coin, m.meta.Decimals, util.ERC20Denom(m.contract)),
+		})
+	}
+	return balances, nil
+}
+
+func runRegisterERC20(args []string) error {
+	fs, opts := newFlagSet(commands["register-erc20"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	titleFlag := fs.String("title", "", "title of the proposal (default Register <symbol>)")
+	descriptionFlag := fs.String("description", "", "description of the proposal")
+	depositFlag := fs.String("deposit", util.DefaultProposalDeposit, "deposit of the proposal")
+	vote := fs.Bool("vote", true, "vote yes on the proposal with the -from key, which passes it on a single validator localnet")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	m, err := opts.connectERC20Module(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.wait.Timeout)
+	defer cancel()
+	rest := opts.profile.REST
+	result := registerResult{Contract: m.contract.Hex(), Status: "already registered"}
+
+	pair, err := util.GetTokenPair(ctx, rest, m.contract.Hex())
+	if errors.Is(err, util.ErrTokenPairNotFound) {
+		title := *titleFlag
+		if title == "" {
+			title = "Register " + m.meta.Symbol
+		}
+		description := *descriptionFlag
+		if description == "" {
+			description = fmt.Sprintf("Register the %s ERC20 contract %s with the erc20 module", m.meta.Name, m.contract.Hex())
+		}
+
+		id, res, err := m.evmosd.SubmitRegisterERC20Proposal(ctx, opts.signer.From, m.contract.Hex(), title, description, *depositFlag)
+		if err != nil {
+			return err
+		}
+		result.ProposalID = id
+		result.SubmitTx = res.TxHash
+
+		if *vote {
+			res, err := m.evmosd.Vote(ctx, opts.signer.From, id, "yes")
+			if err != nil {
+				return err
+			}
+			result.VoteTx = res.TxHash
+		}
+
+		proposal, err := util.WaitForProposal(ctx, rest, id, opts.wait.PollInterval)
+		if err != nil {
+			return err
+		}
+		result.Status = proposal.Status
+
+		if pair, err = util.WaitForTokenPair(ctx, rest, m.contract, opts.wait.PollInterval); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	result.Pair = *pair
+
+	if result.Balances, err = m.balances(m.sender); err != nil {
+		return err
+	}
+	return opts.print(result)
+}
+
+func runTokenPairs(args []string) error {
+	fs, opts := newFlagSet(commands["token-pairs"])
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	profile, err := util.LoadNetworkProfile(opts.networksFile, opts.network)
+	if err != nil {
+		return fmt.Errorf("failed to load network profile: %w", err)
+	}
+
+	pairs, err := util.GetTokenPairs(context.Background(), profile.REST)
+	if err != nil {
+		return err
+	}
+	return opts.print(tokenPairsResult{Pairs: pairs})
+}
+
+func runConvertERC20(args []string) error {
+	return runConvert("convert-erc20", args)
+}
+
+func runConvertCoin(args []string) error {
+	return runConvert("convert-coin", args)
+}
+
+// runConvert runs the convert-erc20 or convert-coin subcommand, which only differ by
+// the direction of the conversion
+func runConvert(name string, args []string) error {
+	fs, opts := newFlagSet(commands[name])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	amountFlag := fs.String("amount", "", "amount of tokens to convert, such as 10.5 or \"10.5 TOK\"")
+	receiverFlag := fs.String("receiver", "", "address receiving the converted tokens (default the sender)")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := requireFlag("amount", *amountFlag); err != nil {
+		return err
+	}
+
+	m, err := opts.connectERC20Module(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	receiver := m.sender
+	if *receiverFlag != "" {
+		if receiver, err = parseAddress("receiver", *receiverFlag); err != nil {
+			return err
+		}
+	}
+
+	amount, err := parseAmount("amount", *amountFlag, m.meta)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.wait.Timeout)
+	defer cancel()
+
+	pair, err := util.GetTokenPair(ctx, opts.profile.REST, m.contract.Hex())
+	if err != nil {
+		return fmt.Errorf("%w, register it with register-erc20 first", err)
+	}
+	if !pair.Enabled {
+		return fmt.Errorf("conversions of %s are disabled", pair.Denom)
+	}
+
+	before, err := m.balances(m.sender, receiver)
+	if err != nil {
+		return err
+	}
+
+	var res *util.TxResponse
+	if name == "convert-erc20" {
+		res, err = m.evmosd.ConvertERC20(ctx, opts.signer.From, m.contract, amount, receiver)
+	} else {
+		res, err = m.evmosd.ConvertCoin(ctx, opts.signer.From, pair.Denom, amount, receiver)
+	}
+	if err != nil {
+		return err
+	}
+
+	after, err := m.balances(m.sender, receiver)
+	if err != nil {
+		return err
+	}
+
+	return opts.print(convertResult{
+		Contract: m.contract.Hex(),
+		Denom:    pair.Denom,
+		Amount:   formatAmount(amount, m.meta),
+		TxHash:   res.TxHash,
+		Height:   res.Height,
+		GasUsed:  res.GasUsed,
+		Before:   before,
+		After:    after,
+	})
+}
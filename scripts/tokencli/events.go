@@ -0,0 +1,190 @@
+/** events.go contains the events subcommand, which writes the Transfer and Approval
+  events of the Token contract as JSON lines. Past blocks are backfilled in chunks,
+  then new blocks are followed over the network profile's websocket endpoint.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+func init() {
+	register(&command{
+		name:        "events",
+		usage:       "[-contract address|name] [-type all|transfer|approval] [-from-block n] [-to-block n] [-out file]",
+		description: "Write the Transfer and Approval events of a Token as JSON lines, following new blocks",
+		run:         runEvents,
+	})
+}
+
+func runEvents(args []string) error {
+	fs, opts := newFlagSet(commands["events"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	typeFlag := fs.String("type", "all", "events to write: all, transfer or approval")
+	fromFlag := fs.String("from", "", "comma separated senders of the Transfer events to write")
+	toFlag := fs.String("to", "", "comma separated recipients of the Transfer events to write")
+	ownerFlag := fs.String("owner", "", "comma separated owners of the Approval events to write")
+	spenderFlag := fs.String("spender", "", "comma separated spenders of the Approval events to write")
+	fromBlockFlag := fs.Int64("from-block", -1, "first block to write events of (default the contract's deployment block from the manifest, else 0)")
+	toBlockFlag := fs.Int64("to-block", -1, "last block to write events of (default follow new blocks over the websocket endpoint)")
+	chunkFlag := fs.Uint64("chunk-size", util.DefaultEventChunkSize, "number of blocks to query for logs at once")
+	confirmationsFlag := fs.Uint64("confirmations", 0, "number of blocks a block must be behind the head before its events are written")
+	outFlag := fs.String("out", "", "file to append events to (default stdout)")
+	checkpointFlag := fs.String("checkpoint", "", "checkpoint file recording the progress of the stream (default <out>.checkpoint.json if -out is set)")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	filter, err := parseEventFilter(*typeFlag, *fromFlag, *toFlag, *ownerFlag, *spenderFlag)
+	if err != nil {
+		return err
+	}
+	if *chunkFlag == 0 {
+		return errors.New("invalid -chunk-size 0, expected a positive number of blocks")
+	}
+	checkpointFile := *checkpointFlag
+	if checkpointFile == "" && *outFlag != "" {
+		checkpointFile = *outFlag + ".checkpoint.json"
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	fromBlock := uint64(0)
+	if *fromBlockFlag >= 0 {
+		fromBlock = uint64(*fromBlockFlag)
+	} else if !util.IsAddress(*contractFlag) {
+		// Start at the deployment block, as earlier blocks cannot have events
+		manifest, err := util.LoadManifest(opts.manifestFile)
+		if err != nil {
+			return err
+		}
+		if deployment, err := manifest.Lookup(opts.profile.Name, *contractFlag); err == nil {
+			fromBlock = deployment.BlockNumber
+		}
+	}
+
+	checkpoint, err := util.LoadEventCheckpoint(checkpointFile)
+	switch {
+	case checkpointFile == "":
+		checkpoint = util.NewEventCheckpoint(opts.profile.Name, contract, filter, fromBlock)
+	case err == nil:
+		if err := checkpoint.Matches(opts.profile.Name, contract, filter); err != nil {
+			return fmt.Errorf("cannot resume from %s: %w", checkpointFile, err)
+		}
+		fmt.Fprintf(os.Stderr, "Resuming from %s at block %d\n", checkpointFile, checkpoint.Next)
+	case errors.Is(err, os.ErrNotExist):
+		checkpoint = util.NewEventCheckpoint(opts.profile.Name, contract, filter, fromBlock)
+	default:
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	output := util.NewEventOutput(os.Stdout)
+	if *outFlag != "" {
+		if output, err = util.OpenEventOutput(*outFlag, checkpoint.Offset); err != nil {
+			return fmt.Errorf("failed to open output: %w", err)
+		}
+	}
+	defer output.Close()
+
+	stream, err := util.NewEventStream(opts.client, filter, checkpoint, checkpointFile, output)
+	if err != nil {
+		return err
+	}
+	stream.ChunkSize = *chunkFlag
+	stream.Confirmations = *confirmationsFlag
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *toBlockFlag >= 0 {
+		return stream.Sync(ctx, uint64(*toBlockFlag))
+	}
+
+	wsClient, err := util.GetWSClient(opts.profile)
+	if err != nil {
+		return fmt.Errorf("failed to get websocket client: %w", err)
+	}
+	defer wsClient.Close()
+
+	// Stopping with an interrupt is the expected way to end a live stream
+	if err := stream.Follow(ctx, wsClient); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// parseEventFilter returns the event filter of the -type flag and the comma separated
+// address flags. Sender and recipient filters only apply to Transfer events, and owner
+// and spender filters only apply to Approval events.
+func parseEventFilter(eventType, from, to, owner, spender string) (util.EventFilter, error) {
+	var filter util.EventFilter
+	switch eventType {
+	case "all":
+		filter.Transfers, filter.Approvals = true, true
+	case "transfer":
+		filter.Transfers = true
+	case "approval":
+		filter.Approvals = true
+	default:
+		return filter, fmt.Errorf("invalid -type %q, expected all, transfer or approval", eventType)
+	}
+
+	var err error
+	if filter.From, err = parseAddressList("from", from); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseAddressList("to", to); err != nil {
+		return filter, err
+	}
+	if filter.Owner, err = parseAddressList("owner", owner); err != nil {
+		return filter, err
+	}
+	if filter.Spender, err = parseAddressList("spender", spender); err != nil {
+		return filter, err
+	}
+
+	// An address filter for the other event type selects none of its events
+	if filter.Transfers && !filter.Approvals && (len(filter.Owner) > 0 || len(filter.Spender) > 0) {
+		return filter, errors.New("-owner and -spender only apply to approval events")
+	}
+	if filter.Approvals && !filter.Transfers && (len(filter.From) > 0 || len(filter.To) > 0) {
+		return filter, errors.New("-from and -to only apply to transfer events")
+	}
+
+	return filter, nil
+}
+
+// parseAddressList validates and returns the comma separated addresses given to
+// the named flag
+func parseAddressList(name, value string) ([]common.Address, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var addresses []common.Address
+	for _, item := range strings.Split(value, ",") {
+		address, err := parseAddress(name, strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
@@ -0,0 +1,111 @@
+/** feesplit.go contains the feesplit subcommand, which shows the x/feesplit
+  registration of a contract and the developer fees it has earned, and the
+  registration run by deploy -feesplit once the contract is mined.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// feeSplitResult describes the fee split registration of a contract, and its fees
+type feeSplitResult struct {
+	Contract   string        `json:"contract"`
+	Registered bool          `json:"registered"`
+	Deployer   string        `json:"deployer,omitempty"`
+	Withdrawer string        `json:"withdrawer,omitempty"`
+	Earned     string        `json:"earned"`
+	Readable   string        `json:"readable"`
+	Fees       []util.DevFee `json:"fees"`
+}
+
+func (r feeSplitResult) fields() []field {
+	fields := []field{{"Contract", r.Contract}}
+	if !r.Registered {
+		fields = append(fields, field{"Registered", "no"})
+	} else {
+		fields = append(fields, field{"Deployer", r.Deployer}, field{"Withdrawer", r.Withdrawer})
+	}
+	return append(fields,
+		field{"Developer fees", r.Readable},
+		field{"Paying transactions", len(r.Fees)},
+	)
+}
+
+func init() {
+	register(&command{
+		name:        "feesplit",
+		usage:       "[-contract address|name]",
+		description: "Show the fee split registration of a contract, and the developer fees it has earned",
+		run:         runFeeSplit,
+	})
+}
+
+func runFeeSplit(args []string) error {
+	fs, opts := newFlagSet(commands["feesplit"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the contract")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	result := feeSplitResult{Contract: contract.Hex()}
+
+	feeSplit, err := util.GetFeeSplit(ctx, opts.profile.REST, contract)
+	switch {
+	case err == nil:
+		result.Registered = true
+		result.Deployer = feeSplit.DeployerAddress
+		result.Withdrawer = feeSplit.WithdrawerAddress
+		if result.Withdrawer == "" {
+			result.Withdrawer = feeSplit.DeployerAddress
+		}
+	case !errors.Is(err, util.ErrFeeSplitNotFound):
+		return err
+	}
+
+	// A contract that was registered and cancelled keeps its earlier fees
+	fees, total, err := util.GetDevFees(ctx, opts.profile.REST, contract)
+	if err != nil {
+		return err
+	}
+	result.Fees = fees
+	result.Earned = total.String()
+	result.Readable = formatNative(total)
+
+	return opts.print(result)
+}
+
+// registerFeeSplit registers the contract deployed by the transaction for fee splits,
+// with the nonce it was deployed with, and returns the registration transaction hash
+func (o *options) registerFeeSplit(evmosd *util.Evmosd, deployment util.Deployment, withdrawer *common.Address) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), o.wait.Timeout)
+	defer cancel()
+
+	tx, _, err := o.client.TransactionByHash(ctx, deployment.TxHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get deployment transaction %s: %w", deployment.TxHash.Hex(), err)
+	}
+
+	res, err := evmosd.RegisterFeeSplit(ctx, o.signer.From, deployment.Address, []uint64{tx.Nonce()}, withdrawer)
+	if err != nil {
+		return "", err
+	}
+	return res.TxHash, nil
+}
@@ -0,0 +1,266 @@
+/** flags.go contains the flags and validation helpers shared by the tokencli
+  subcommands.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// options holds the flags shared by every subcommand, and the connection
+// made from them
+type options struct {
+	networksFile string
+	network      string
+	manifestFile string
+	output       string
+
+	// Transaction flags, registered by registerTxFlags
+	signer util.SignerOptions
+	fees   util.FeeOptions
+	wait   util.WaitOptions
+
+	profile *util.NetworkProfile
+	client  *ethclient.Client
+}
+
+// newFlagSet returns the flag set of a subcommand, with the shared network
+// and output flags registered
+func newFlagSet(cmd *command) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tokencli %s %s\n\n%s\n\nFlags:\n", cmd.name, cmd.usage, cmd.description)
+		fs.PrintDefaults()
+	}
+
+	opts := &options{signer: util.DefaultSignerOptions, fees: util.DefaultFeeOptions, wait: util.DefaultWaitOptions}
+	fs.StringVar(&opts.networksFile, "networks", "", "path to the networks config file")
+	fs.StringVar(&opts.network, "network", "", "name of the network profile to use")
+	fs.StringVar(&opts.manifestFile, "manifest", util.DefaultManifestFile, "path to the deployment manifest")
+	fs.StringVar(&opts.output, "output", "table", "output format: json or table")
+
+	return fs, opts
+}
+
+// registerTxFlags registers the flags used by subcommands that send transactions
+func (o *options) registerTxFlags(fs *flag.FlagSet) {
+	o.signer.RegisterFlags(fs)
+	o.fees.RegisterFlags(fs)
+	o.wait.RegisterFlags(fs)
+}
+
+// parse parses the subcommand arguments, and validates the shared flags
+func (o *options) parse(fs *flag.FlagSet, args []string) error {
+	positional, err := o.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) > 0 {
+		return fmt.Errorf("unexpected arguments: %v", positional)
+	}
+
+	return nil
+}
+
+// parseArgs parses the subcommand arguments, validates the shared flags, and returns
+// the positional arguments following the flags
+func (o *options) parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if o.output != "json" && o.output != "table" {
+		return nil, fmt.Errorf("invalid -output %q, expected json or table", o.output)
+	}
+
+	return fs.Args(), nil
+}
+
+// connect loads the network profile and connects to its node
+func (o *options) connect() error {
+	profile, err := util.LoadNetworkProfile(o.networksFile, o.network)
+	if err != nil {
+		return fmt.Errorf("failed to load network profile: %w", err)
+	}
+
+	client, err := util.GetClient(profile)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %w", err)
+	}
+
+	o.profile = profile
+	o.client = client
+	return nil
+}
+
+// resolveContract returns the address of a contract given as a hex address, or as a
+// contract name from the deployment manifest of the connected network
+func (o *options) resolveContract(value string) (common.Address, error) {
+	if value == "" {
+		return common.Address{}, errors.New("-contract is required")
+	}
+
+	address, err := util.ResolveContract(o.manifestFile, o.profile, value)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to resolve contract %q: %w", value, err)
+	}
+
+	if util.IsAddress(value) {
+		return address, nil
+	}
+
+	// Check that the manifest entry is not left over from an earlier node
+	code, err := o.client.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(code) == 0 {
+		return common.Address{}, fmt.Errorf("no contract code at %v for %q, the manifest may be stale", address.Hex(), value)
+	}
+
+	return address, nil
+}
+
+// backend returns the contract backend of the connected node, which estimates the
+// gas limit of each transaction with the -gas-multiplier safety margin
+func (o *options) backend() bind.ContractBackend {
+	return util.NewGasEstimator(o.client, o.fees.GasMultiplier)
+}
+
+// token returns an instance of the Token contract at the given address
+func (o *options) token(address common.Address) (*token.Token, error) {
+	instance, err := token.NewToken(address, o.backend())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract instance: %w", err)
+	}
+	return instance, nil
+}
+
+// transactor loads the sender's signer, and returns the transaction options and
+// address of the sender
+func (o *options) transactor() (*bind.TransactOpts, common.Address, error) {
+	signer, err := o.signer.NewSigner()
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to load signer: %w", err)
+	}
+
+	auth, err := util.GetAuth(o.client, signer, o.fees)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get auth: %w", err)
+	}
+	if err := o.profile.ApplyGasDefaults(auth, o.fees); err != nil {
+		return nil, common.Address{}, err
+	}
+
+	return auth, signer.Address(), nil
+}
+
+// evmosd returns the evmosd wrapper sending Cosmos transactions to the connected
+// network, signed with the -from key of the keyring, at the node's gas price
+func (o *options) evmosd() (*util.Evmosd, error) {
+	if o.signer.Backend != util.SignerKeyring {
+		return nil, fmt.Errorf("cosmos transactions are signed by evmosd with a key of its keyring, -signer %s is not supported", o.signer.Backend)
+	}
+
+	evmosd, err := util.NewEvmosd(o.profile, o.signer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cosmos transactions pay the same base fee as EVM transactions
+	gasPrice, err := o.client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	evmosd.GasPrices = util.CosmosGasPrices(gasPrice)
+	return evmosd, nil
+}
+
+// waitForReceipt waits for the transaction to be confirmed, and returns its receipt.
+// A reverted transaction is replayed to return its revert reason with the error.
+func (o *options) waitForReceipt(tx *types.Transaction) (*types.Receipt, error) {
+	backend, closeBackend, err := util.GetReceiptBackend(o.profile, o.client, o.wait)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt backend: %w", err)
+	}
+	defer closeBackend()
+
+	receipt, err := util.WaitForReceipt(context.Background(), backend, tx.Hash(), o.wait)
+	if errors.Is(err, util.ErrTxReverted) {
+		ctx, cancel := context.WithTimeout(context.Background(), o.wait.Timeout)
+		defer cancel()
+		return receipt, util.ExplainRevertedTx(ctx, o.client, tx, receipt, err, revertABIs())
+	}
+	return receipt, err
+}
+
+var (
+	revertABIsOnce sync.Once
+	buildABIs      []*abi.ABI
+)
+
+// revertABIs returns the ABIs that custom errors are decoded with: the Token ABI, and
+// every ABI compiled into the default build directory, when run from the repository
+func revertABIs() []*abi.ABI {
+	revertABIsOnce.Do(func() {
+		if tokenABI, err := token.TokenMetaData.GetAbi(); err == nil {
+			buildABIs = append(buildABIs, tokenABI)
+		}
+		if abis, err := util.LoadBuildABIs(util.DefaultBuildDir); err == nil {
+			buildABIs = append(buildABIs, abis...)
+		}
+	})
+	return buildABIs
+}
+
+// parseAddress validates and returns the hex or bech32 address given to the named flag
+func parseAddress(name, value string) (common.Address, error) {
+	if value == "" {
+		return common.Address{}, fmt.Errorf("-%s is required", name)
+	}
+	address, err := util.ParseAddress(value)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid -%s: %w", name, err)
+	}
+	return address, nil
+}
+
+// requireFlag returns an error if the named flag was not given a value
+func requireFlag(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("-%s is required", name)
+	}
+	return nil
+}
+
+// parseAmount validates and returns the positive token amount given to the named flag,
+// such as 10.5 or "10.5 TOK", in base units
+func parseAmount(name, value string, meta tokenMetadata) (*big.Int, error) {
+	if err := requireFlag(name, value); err != nil {
+		return nil, err
+	}
+	amount, err := util.ParseTokenAmount(value, meta.Decimals, meta.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -%s: %w", name, err)
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid -%s %q, expected a positive amount", name, value)
+	}
+	return amount, nil
+}
@@ -0,0 +1,75 @@
+/** info.go contains the info subcommand, which shows the name, symbol, decimals
+  and total supply of the Token contract.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// infoResult describes a Token contract
+type infoResult struct {
+	Contract string `json:"contract"`
+	tokenMetadata
+	TotalSupply string `json:"total_supply"`
+}
+
+func (r infoResult) fields() []field {
+	return []field{
+		{"Contract", r.Contract},
+		{"Name", r.Name},
+		{"Symbol", r.Symbol},
+		{"Decimals", r.Decimals},
+		{"Total supply", r.TotalSupply},
+	}
+}
+
+func init() {
+	register(&command{
+		name:        "info",
+		usage:       "[-contract address|name]",
+		description: "Show the name, symbol, decimals and total supply of the Token contract",
+		run:         runInfo,
+	})
+}
+
+func runInfo(args []string) error {
+	fs, opts := newFlagSet(commands["info"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	supply, err := instance.TotalSupply(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to get total supply: %w", err)
+	}
+
+	return opts.print(infoResult{
+		Contract:      contract.Hex(),
+		tokenMetadata: meta,
+		TotalSupply:   formatAmount(supply, meta),
+	})
+}
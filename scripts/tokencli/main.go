@@ -0,0 +1,83 @@
+/** tokencli is a single command line tool for deploying and interacting with
+    the Token contract on an evmos node.
+    It utilises the Go-ethereum contract binding script:
+	scripts/token/token.go
+    and the helper functions in scripts/utils.
+    Usage:
+	tokencli <command> [flags]
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// command defines a tokencli subcommand
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+// commands contains every tokencli subcommand by name
+var commands = map[string]*command{}
+
+// register adds a subcommand to tokencli
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		// Calls and gas estimates of reverting transactions return the revert data
+		log.Fatalf("Failed to run %s: %v", cmd.name, util.ExplainRevert(err, revertABIs()))
+	}
+}
+
+// usage prints the list of subcommands
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: tokencli <command> [flags]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'tokencli <command> -h' for the flags of a command.\n")
+}
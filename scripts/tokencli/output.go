@@ -0,0 +1,111 @@
+/** output.go contains the results printed by the tokencli subcommands, in
+  either json or table format.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// field is a single row of a table result
+type field struct {
+	name  string
+	value interface{}
+}
+
+// result is implemented by every subcommand result
+type result interface {
+	fields() []field
+}
+
+// print writes the result to stdout in the selected output format
+func (o *options) print(r result) error {
+	if o.output == "json" {
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, f := range r.fields() {
+		fmt.Fprintf(w, "%s:\t%v\n", f.name, f.value)
+	}
+	return w.Flush()
+}
+
+// txResult describes a confirmed transaction
+type txResult struct {
+	TxHash  string `json:"tx_hash"`
+	From    string `json:"from"`
+	Status  string `json:"status"`
+	Block   uint64 `json:"block"`
+	GasUsed uint64 `json:"gas_used"`
+}
+
+// newTxResult returns the result of a transaction from its receipt
+func newTxResult(from common.Address, receipt *types.Receipt) txResult {
+	return txResult{
+		TxHash:  receipt.TxHash.Hex(),
+		From:    from.Hex(),
+		Status:  util.ReceiptStatus(receipt),
+		Block:   receipt.BlockNumber.Uint64(),
+		GasUsed: receipt.GasUsed,
+	}
+}
+
+func (r txResult) fields() []field {
+	return []field{
+		{"Transaction", r.TxHash},
+		{"From", r.From},
+		{"Status", r.Status},
+		{"Block", r.Block},
+		{"Gas used", r.GasUsed},
+	}
+}
+
+// tokenMetadata holds the descriptive values of a Token contract
+type tokenMetadata struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// getTokenMetadata queries the name, symbol and decimals of a Token contract
+func getTokenMetadata(instance *token.Token) (tokenMetadata, error) {
+	name, err := instance.Name(&bind.CallOpts{})
+	if err != nil {
+		return tokenMetadata{}, fmt.Errorf("failed to get contract name: %w", err)
+	}
+
+	symbol, err := instance.Symbol(&bind.CallOpts{})
+	if err != nil {
+		return tokenMetadata{}, fmt.Errorf("failed to get contract symbol: %w", err)
+	}
+
+	decimals, err := instance.Decimals(&bind.CallOpts{})
+	if err != nil {
+		return tokenMetadata{}, fmt.Errorf("failed to get contract decimals: %w", err)
+	}
+
+	return tokenMetadata{Name: name, Symbol: symbol, Decimals: decimals}, nil
+}
+
+// formatAmount returns a base unit amount as an exact token amount with its symbol
+func formatAmount(amount *big.Int, meta tokenMetadata) string {
+	return util.FormatTokenAmount(amount, meta.Decimals, meta.Symbol)
+}
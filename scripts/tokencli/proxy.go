@@ -0,0 +1,334 @@
+/** proxy.go contains the proxy, upgrade and change-admin subcommands, which read the
+  implementation and admin of an ERC-1967 proxy, upgrade it to a new implementation
+  after checking that the storage layouts are compatible, and transfer its admin.
+  It also deploys the proxies of deploy -proxy.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// proxyResult describes the implementation and admin of a proxy
+type proxyResult struct {
+	Proxy string `json:"proxy"`
+	util.ProxyInfo
+}
+
+func (r proxyResult) fields() []field {
+	admin := r.Admin.Hex()
+	if r.Admin == (common.Address{}) {
+		admin = "none, upgraded through the implementation"
+	}
+	return []field{
+		{"Proxy", r.Proxy},
+		{"Implementation", r.Implementation.Hex()},
+		{"Admin", admin},
+	}
+}
+
+// upgradeResult describes a confirmed proxy upgrade
+type upgradeResult struct {
+	Proxy          string `json:"proxy"`
+	Implementation string `json:"implementation"`
+	Initializer    string `json:"initializer,omitempty"`
+	txResult
+}
+
+func (r upgradeResult) fields() []field {
+	fields := []field{{"Proxy", r.Proxy}, {"Implementation", r.Implementation}}
+	if r.Initializer != "" {
+		fields = append(fields, field{"Initializer", r.Initializer})
+	}
+	return append(fields, r.txResult.fields()...)
+}
+
+// changeAdminResult describes a confirmed change of a proxy's admin
+type changeAdminResult struct {
+	Proxy string `json:"proxy"`
+	Admin string `json:"admin"`
+	txResult
+}
+
+func (r changeAdminResult) fields() []field {
+	return append([]field{{"Proxy", r.Proxy}, {"Admin", r.Admin}}, r.txResult.fields()...)
+}
+
+func init() {
+	register(&command{
+		name:        "proxy",
+		usage:       "[-contract address|name]",
+		description: "Show the implementation and admin of an ERC-1967 proxy",
+		run:         runProxy,
+	})
+	register(&command{
+		name:        "upgrade",
+		usage:       "[-contract address|name] -implementation address|name -old-layout name -new-layout name [initializer args...]",
+		description: "Upgrade a proxy to a new implementation, after checking their storage layouts",
+		run:         runUpgrade,
+	})
+	register(&command{
+		name:        "change-admin",
+		usage:       "[-contract address|name] -admin address",
+		description: "Transfer the admin of a transparent proxy",
+		run:         runChangeAdmin,
+	})
+}
+
+func runProxy(args []string) error {
+	fs, opts := newFlagSet(commands["proxy"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the proxy")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	proxy, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	info, err := util.ReadProxy(context.Background(), opts.client, proxy)
+	if err != nil {
+		return err
+	}
+
+	return opts.print(proxyResult{Proxy: proxy.Hex(), ProxyInfo: *info})
+}
+
+func runUpgrade(args []string) error {
+	fs, opts := newFlagSet(commands["upgrade"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the proxy")
+	implementationFlag := fs.String("implementation", "", "address, or deployment manifest name, of the new implementation")
+	buildDirFlag := fs.String("build-dir", util.DefaultBuildDir, "directory of the compiled contract artifacts and storage layouts")
+	oldLayoutFlag := fs.String("old-layout", "", "contract name in the build directory, or path of a .json storage layout, of the current implementation")
+	newLayoutFlag := fs.String("new-layout", "", "contract name in the build directory, or path of a .json storage layout, of the new implementation, followed by its initializer method and arguments")
+	skipLayoutCheck := fs.Bool("skip-layout-check", false, "upgrade without comparing the storage layouts")
+	positional, err := opts.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err := requireFlag("implementation", *implementationFlag); err != nil {
+		return err
+	}
+
+	if !*skipLayoutCheck {
+		if *oldLayoutFlag == "" || *newLayoutFlag == "" {
+			return errors.New("-old-layout and -new-layout are required to check the storage layouts, or use -skip-layout-check")
+		}
+		oldLayout, err := util.LoadStorageLayout(*buildDirFlag, *oldLayoutFlag)
+		if err != nil {
+			return err
+		}
+		newLayout, err := util.LoadStorageLayout(*buildDirFlag, *newLayoutFlag)
+		if err != nil {
+			return err
+		}
+		if err := util.CheckStorageUpgrade(oldLayout, newLayout); err != nil {
+			return fmt.Errorf("%w\nuse -skip-layout-check to upgrade anyway", err)
+		}
+	}
+
+	// The initializer is a method of the new implementation
+	var data []byte
+	var initializer string
+	if len(positional) > 0 {
+		if *newLayoutFlag == "" {
+			return errors.New("-new-layout is required to find the initializer method of the new implementation")
+		}
+		if data, err = initializerData(*buildDirFlag, *newLayoutFlag, positional); err != nil {
+			return err
+		}
+		initializer = positional[0]
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	proxy, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+	implementation, err := opts.resolveContract(*implementationFlag)
+	if err != nil {
+		return err
+	}
+	if _, err := util.ReadProxy(context.Background(), opts.client, proxy); err != nil {
+		return err
+	}
+
+	auth, sender, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+
+	tx, err := util.UpgradeProxy(auth, opts.backend(), proxy, implementation, data)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade %s: %w", proxy.Hex(), err)
+	}
+
+	receipt, err := opts.waitForReceipt(tx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm upgrade: %w", err)
+	}
+
+	return opts.print(upgradeResult{
+		Proxy:          proxy.Hex(),
+		Implementation: implementation.Hex(),
+		Initializer:    initializer,
+		txResult:       newTxResult(sender, receipt),
+	})
+}
+
+func runChangeAdmin(args []string) error {
+	fs, opts := newFlagSet(commands["change-admin"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the proxy")
+	adminFlag := fs.String("admin", "", "address of the new admin")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	admin, err := parseAddress("admin", *adminFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	proxy, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	info, err := util.ReadProxy(context.Background(), opts.client, proxy)
+	if err != nil {
+		return err
+	}
+	if info.Admin == (common.Address{}) {
+		return fmt.Errorf("%s has no admin, UUPS proxies are upgraded through their implementation", proxy.Hex())
+	}
+
+	auth, sender, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+	if sender != info.Admin {
+		return fmt.Errorf("only the admin %s can change the admin of %s", info.Admin.Hex(), proxy.Hex())
+	}
+
+	tx, err := util.ChangeProxyAdmin(auth, opts.backend(), proxy, admin)
+	if err != nil {
+		return fmt.Errorf("failed to change admin of %s: %w", proxy.Hex(), err)
+	}
+
+	receipt, err := opts.waitForReceipt(tx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm admin change: %w", err)
+	}
+
+	return opts.print(changeAdminResult{
+		Proxy:    proxy.Hex(),
+		Admin:    admin.Hex(),
+		txResult: newTxResult(sender, receipt),
+	})
+}
+
+// initializerData returns the call data of an initializer method, given as the first
+// positional argument followed by its arguments, of a contract of the build directory.
+// There is no call without positional arguments.
+func initializerData(buildDir, contract string, positional []string) ([]byte, error) {
+	if len(positional) == 0 {
+		return nil, nil
+	}
+
+	contractABI, err := util.LoadABI(buildDir, contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ABI: %w", err)
+	}
+	method, err := util.FindMethod(contractABI, positional[0])
+	if err != nil {
+		return nil, err
+	}
+	args, err := util.ParseArgs(method.Inputs, positional[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method.Sig, err)
+	}
+	return contractABI.Pack(method.Name, args...)
+}
+
+// tokenInitializerData returns the call data of the initialize method of
+// TokenUpgradeable, with the Token parameters and the owner allowed to upgrade a UUPS
+// proxy. A transparent proxy is initialised without an owner, so that only its admin
+// can upgrade it, and as its admin cannot call the token through it, the admin
+// cannot be one of the initial holders.
+func tokenInitializerData(buildDir string, kind util.ProxyKind, params *util.TokenParams, owner, admin common.Address) ([]byte, error) {
+	if kind == util.ProxyTransparent {
+		for _, holder := range params.Holders {
+			if holder == admin {
+				return nil, fmt.Errorf("the proxy admin %s is an initial holder, but the admin of a transparent proxy cannot call the token, give another -proxy-admin or -holders", admin.Hex())
+			}
+		}
+		owner = common.Address{}
+	}
+
+	contractABI, err := util.LoadABI(buildDir, util.TokenUpgradeableArtifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ABI: %w", err)
+	}
+	data, err := contractABI.Pack("initialize", params.Name, params.Symbol, params.Decimals, params.InitialSupply, params.Holders, owner)
+	if err != nil {
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+	return data, nil
+}
+
+// readProxyArtifact reads the OpenZeppelin artifact of a kind of proxy from the build
+// directory, and checks that it can be deployed
+func readProxyArtifact(buildDir string, kind util.ProxyKind) (util.Artifact, error) {
+	artifact, err := util.ReadArtifact(buildDir, util.ProxyArtifacts[kind])
+	if err != nil {
+		return util.Artifact{}, fmt.Errorf("failed to read the %s proxy, compile the OpenZeppelin proxies into %s with go run ./scripts/bindgen: %w", kind, buildDir, err)
+	}
+	if artifact.Bin == "" {
+		return util.Artifact{}, fmt.Errorf("%s in %s has no bytecode", artifact.Name, buildDir)
+	}
+	return artifact, nil
+}
+
+// deployProxy deploys a proxy of the given kind in front of the implementation, from
+// its OpenZeppelin artifact, and returns its deployment
+func (o *options) deployProxy(auth *bind.TransactOpts, artifact util.Artifact, kind util.ProxyKind, name string, implementation, admin common.Address, initData []byte, chainID *big.Int, deployer common.Address) (util.Deployment, *types.Receipt, error) {
+	address, tx, err := util.DeployProxy(auth, o.backend(), kind, artifact, implementation, admin, initData)
+	if err != nil {
+		return util.Deployment{}, nil, err
+	}
+
+	receipt, err := o.waitForReceipt(tx)
+	if err != nil {
+		return util.Deployment{}, nil, fmt.Errorf("failed to confirm proxy deployment: %w", err)
+	}
+
+	metadata := &bind.MetaData{ABI: string(artifact.ABI), Bin: "0x" + artifact.Bin}
+	deployment := util.NewDeployment(name, metadata, chainID, deployer, receipt)
+	deployment.Address = address
+	return deployment, receipt, nil
+}
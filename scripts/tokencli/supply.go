@@ -0,0 +1,72 @@
+/** supply.go contains the supply subcommand, which queries the total supply of
+  the Token contract.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// supplyResult describes the total supply of a Token contract
+type supplyResult struct {
+	Contract    string `json:"contract"`
+	TotalSupply string `json:"total_supply"`
+	Readable    string `json:"readable"`
+}
+
+func (r supplyResult) fields() []field {
+	return []field{
+		{"Contract", r.Contract},
+		{"Total supply", r.Readable},
+	}
+}
+
+func init() {
+	register(&command{
+		name:        "supply",
+		usage:       "[-contract address|name]",
+		description: "Show the total supply of the Token contract",
+		run:         runSupply,
+	})
+}
+
+func runSupply(args []string) error {
+	fs, opts := newFlagSet(commands["supply"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	supply, err := instance.TotalSupply(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to get total supply: %w", err)
+	}
+
+	return opts.print(supplyResult{
+		Contract:    contract.Hex(),
+		TotalSupply: supply.String(),
+		Readable:    formatAmount(supply, meta),
+	})
+}
@@ -0,0 +1,93 @@
+/** transfer.go contains the transfer subcommand, which transfers Tokens from
+  the sender to a recipient.
+*/
+
+package main
+
+import (
+	"fmt"
+)
+
+// transferResult describes a confirmed Token transfer
+type transferResult struct {
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+	txResult
+}
+
+func (r transferResult) fields() []field {
+	return append([]field{{"To", r.To}, {"Amount", r.Amount}}, r.txResult.fields()...)
+}
+
+func init() {
+	register(&command{
+		name:        "transfer",
+		usage:       "[-contract address|name] -to address -amount tokens [-from key]",
+		description: "Transfer Tokens from the sender to a recipient",
+		run:         runTransfer,
+	})
+}
+
+func runTransfer(args []string) error {
+	fs, opts := newFlagSet(commands["transfer"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	toFlag := fs.String("to", "", "address of the recipient")
+	amountFlag := fs.String("amount", "", "amount of tokens to transfer, such as 10.5 or \"10.5 TOK\"")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	to, err := parseAddress("to", *toFlag)
+	if err != nil {
+		return err
+	}
+	if err := requireFlag("amount", *amountFlag); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	amount, err := parseAmount("amount", *amountFlag, meta)
+	if err != nil {
+		return err
+	}
+
+	auth, sender, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+
+	tx, err := instance.Transfer(auth, to, amount)
+	if err != nil {
+		return fmt.Errorf("failed to transfer tokens: %w", err)
+	}
+
+	receipt, err := opts.waitForReceipt(tx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm transfer: %w", err)
+	}
+
+	return opts.print(transferResult{
+		To:       to.Hex(),
+		Amount:   formatAmount(amount, meta),
+		txResult: newTxResult(sender, receipt),
+	})
+}
@@ -0,0 +1,100 @@
+/** transfer_from.go contains the transfer-from subcommand, which transfers an
+  owner's Tokens using the sender's allowance.
+*/
+
+package main
+
+import (
+	"fmt"
+)
+
+// transferFromResult describes a confirmed Token transfer from an owner
+type transferFromResult struct {
+	Owner  string `json:"owner"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+	txResult
+}
+
+func (r transferFromResult) fields() []field {
+	return append([]field{{"Owner", r.Owner}, {"To", r.To}, {"Amount", r.Amount}}, r.txResult.fields()...)
+}
+
+func init() {
+	register(&command{
+		name:        "transfer-from",
+		usage:       "[-contract address|name] -owner address -to address -amount tokens [-from key]",
+		description: "Transfer Tokens from an owner to a recipient, using the sender's allowance",
+		run:         runTransferFrom,
+	})
+}
+
+func runTransferFrom(args []string) error {
+	fs, opts := newFlagSet(commands["transfer-from"])
+	opts.registerTxFlags(fs)
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the Token contract")
+	ownerFlag := fs.String("owner", "", "address of the token owner")
+	toFlag := fs.String("to", "", "address of the recipient")
+	amountFlag := fs.String("amount", "", "amount of tokens to transfer, such as 10.5 or \"10.5 TOK\"")
+	if err := opts.parse(fs, args); err != nil {
+		return err
+	}
+
+	from, err := parseAddress("owner", *ownerFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseAddress("to", *toFlag)
+	if err != nil {
+		return err
+	}
+	if err := requireFlag("amount", *amountFlag); err != nil {
+		return err
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	contract, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	instance, err := opts.token(contract)
+	if err != nil {
+		return err
+	}
+
+	meta, err := getTokenMetadata(instance)
+	if err != nil {
+		return err
+	}
+
+	amount, err := parseAmount("amount", *amountFlag, meta)
+	if err != nil {
+		return err
+	}
+
+	auth, spender, err := opts.transactor()
+	if err != nil {
+		return err
+	}
+
+	tx, err := instance.TransferFrom(auth, from, to, amount)
+	if err != nil {
+		return fmt.Errorf("failed to transfer tokens: %w", err)
+	}
+
+	receipt, err := opts.waitForReceipt(tx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm transfer: %w", err)
+	}
+
+	return opts.print(transferFromResult{
+		Owner:    from.Hex(),
+		To:       to.Hex(),
+		Amount:   formatAmount(amount, meta),
+		txResult: newTxResult(spender, receipt),
+	})
+}
@@ -0,0 +1,180 @@
+/** verify.go contains the verify subcommand, which checks that the code deployed at
+  a contract's address was compiled from a build artifact in contract/build.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// verifyResult describes the comparison of a contract's code with its artifact
+type verifyResult struct {
+	Contract string `json:"contract"`
+	Address  string `json:"address"`
+	Artifact string `json:"artifact"`
+	util.VerifyResult
+}
+
+func (r verifyResult) fields() []field {
+	fields := []field{
+		{"Contract", r.Contract},
+		{"Contract address", r.Address},
+		{"Artifact", r.Artifact},
+		{"Result", r.Status},
+		{"Expected code", fmt.Sprintf("%d bytes, %s", r.ExpectedSize, r.ExpectedHash.Hex())},
+		{"Deployed code", fmt.Sprintf("%d bytes, %s", r.ActualSize, r.ActualHash.Hex())},
+	}
+	if r.Status == util.VerifyMetadataOnly {
+		fields = append(fields, field{"Expected metadata", r.ExpectedMetadata}, field{"Deployed metadata", r.ActualMetadata})
+	}
+	if r.Status == util.VerifyMismatch {
+		fields = append(fields, field{"First difference", fmt.Sprintf("byte %d", r.FirstDifference)})
+	}
+	return fields
+}
+
+func init() {
+	register(&command{
+		name:        "verify",
+		usage:       "[-contract address|name] [-artifact name] [-deployer address] [constructor args...]",
+		description: "Check that the code of a deployed contract was compiled from its build artifact",
+		run:         runVerify,
+	})
+}
+
+func runVerify(args []string) error {
+	fs, opts := newFlagSet(commands["verify"])
+	contractFlag := fs.String("contract", "Token", "address, or deployment manifest name, of the contract")
+	artifactFlag := fs.String("artifact", "", "contract name in the build directory (default the -contract manifest name)")
+	buildDirFlag := fs.String("build-dir", util.DefaultBuildDir, "directory of the compiled contract artifacts")
+	deployerFlag := fs.String("deployer", "", "address the contract was deployed from (default the manifest deployer, or 0x1 for an address)")
+	positional, err := opts.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	name := *artifactFlag
+	if name == "" {
+		if util.IsAddress(*contractFlag) {
+			return errors.New("-artifact is required when -contract is an address")
+		}
+		name = *contractFlag
+	}
+
+	artifact, err := util.ReadArtifact(*buildDirFlag, name)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact: %w", err)
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(artifact.ABI))
+	if err != nil {
+		return fmt.Errorf("invalid ABI of %s: %w", name, err)
+	}
+
+	// Immutable references are only known from the build info written by bindgen
+	info, err := util.LoadBuildInfo(filepath.Join(*buildDirFlag, util.DefaultBuildInfoFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to load build info: %w", err)
+	}
+	if info != nil {
+		artifact.ImmutableReferences = info.Contracts[name].ImmutableReferences
+	}
+
+	if err := opts.connect(); err != nil {
+		return err
+	}
+
+	address, err := opts.resolveContract(*contractFlag)
+	if err != nil {
+		return err
+	}
+
+	var deployment *util.Deployment
+	if !util.IsAddress(*contractFlag) {
+		manifest, err := util.LoadManifest(opts.manifestFile)
+		if err != nil {
+			return fmt.Errorf("failed to load manifest: %w", err)
+		}
+		if deployment, err = manifest.Lookup(opts.profile.Name, *contractFlag); err != nil {
+			return err
+		}
+	}
+
+	// Any deployer derives the same code unless it is stored in an immutable, but the
+	// zero address cannot receive the tokens minted by the Token constructor
+	deployer := common.HexToAddress("0x1")
+	switch {
+	case *deployerFlag != "":
+		if deployer, err = parseAddress("deployer", *deployerFlag); err != nil {
+			return err
+		}
+	case deployment != nil:
+		deployer = deployment.Deployer
+	}
+
+	constructorArgs, err := opts.constructorArgs(contractABI, artifact, deployment, positional)
+	if err != nil {
+		return err
+	}
+
+	result, err := util.VerifyCode(context.Background(), opts.client, address, artifact, constructorArgs, deployer)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.print(verifyResult{
+		Contract:     *contractFlag,
+		Address:      address.Hex(),
+		Artifact:     name,
+		VerifyResult: *result,
+	}); err != nil {
+		return err
+	}
+
+	if result.Status == util.VerifyMismatch {
+		return fmt.Errorf("code at %s was not compiled from %s", address.Hex(), name)
+	}
+	return nil
+}
+
+// constructorArgs returns the ABI encoded constructor arguments, given on the command
+// line, or else read from the deployment transaction recorded in the manifest
+func (o *options) constructorArgs(contractABI abi.ABI, artifact util.Artifact, deployment *util.Deployment, positional []string) ([]byte, error) {
+	inputs := contractABI.Constructor.Inputs
+
+	if len(positional) > 0 || len(inputs) == 0 {
+		values, err := util.ParseArgs(inputs, positional)
+		if err != nil {
+			return nil, fmt.Errorf("constructor: %w", err)
+		}
+		return inputs.Pack(values...)
+	}
+
+	if deployment == nil {
+		return nil, errors.New("the constructor arguments are required when -contract is an address")
+	}
+	tx, _, err := o.client.TransactionByHash(context.Background(), deployment.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment transaction %s, pass the constructor arguments instead: %w", deployment.TxHash.Hex(), err)
+	}
+	input := tx.Data()
+	if tx.To() != nil && len(input) >= common.HashLength {
+		// A CREATE2 deployment calls the factory with the salt before the creation code
+		input = input[common.HashLength:]
+	}
+	args, err := util.ConstructorArgs(common.FromHex(artifact.Bin), input)
+	if err != nil {
+		return nil, fmt.Errorf("%w, pass the constructor arguments instead", err)
+	}
+	return args, nil
+}
@@ -0,0 +1,483 @@
+/** abi.go contains helpers to call and send any method of a contract by name, from
+  the ABI files compiled into contract/build. Command line strings are converted to
+  the Go values expected by the ABI, and return values and event arguments are
+  converted back to values that can be displayed, or encoded as JSON.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultBuildDir is the directory of the compiled contract ABI and bytecode files
+const DefaultBuildDir = "contract/build"
+
+// bigIntType is the Go type of ABI integers that do not fit a native integer
+var bigIntType = reflect.TypeOf(new(big.Int))
+
+// LoadABI reads and returns the ABI of a contract, given either as the path of an
+// .abi file, or as the name of a contract compiled into buildDir
+func LoadABI(buildDir, contract string) (*abi.ABI, error) {
+	path := contract
+	if !strings.HasSuffix(contract, ".abi") {
+		if buildDir == "" {
+			buildDir = DefaultBuildDir
+		}
+		path = filepath.Join(buildDir, contract+".abi")
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && path != contract {
+		return nil, fmt.Errorf("no ABI for contract %q in %s: %w", contract, buildDir, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	contractABI, err := abi.JSON(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI %s: %w", path, err)
+	}
+	return &contractABI, nil
+}
+
+// FindMethod returns the method of the ABI with the given name, such as "transfer",
+// or signature, such as "transfer(address,uint256)". Overloaded methods must be
+// given by signature.
+func FindMethod(contractABI *abi.ABI, name string) (*abi.Method, error) {
+	var matches []abi.Method
+	for _, method := range contractABI.Methods {
+		if method.Sig == name {
+			return &method, nil
+		}
+		if method.RawName == name {
+			matches = append(matches, method)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+	case 1:
+		return &matches[0], nil
+	default:
+		sigs := make([]string, len(matches))
+		for i, method := range matches {
+			sigs[i] = method.Sig
+		}
+		sort.Strings(sigs)
+		return nil, fmt.Errorf("method %q is overloaded, give one of its signatures: %s", name, strings.Join(sigs, ", "))
+	}
+
+	// go-ethereum names overloads transfer0, transfer1 and so on, which are not ambiguous
+	if method, ok := contractABI.Methods[name]; ok {
+		return &method, nil
+	}
+	return nil, fmt.Errorf("no method %q in the ABI", name)
+}
+
+// ParseArgs converts the command line values of a method's arguments to the Go values
+// expected by the ABI. Array and tuple values are given as JSON, such as [1,2] or
+// {"to":"0x...","amount":"10"}.
+func ParseArgs(args abi.Arguments, values []string) ([]interface{}, error) {
+	if len(values) != len(args) {
+		return nil, fmt.Errorf("expected %d arguments %s, got %d", len(args), describeArgs(args), len(values))
+	}
+
+	parsed := make([]interface{}, len(args))
+	for i, arg := range args {
+		value, err := ParseArg(arg.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d %s: %w", i+1, describeArg(arg), err)
+		}
+		parsed[i] = value
+	}
+	return parsed, nil
+}
+
+// ParseArg converts a command line value to the Go value of the given ABI type
+func ParseArg(t abi.Type, value string) (interface{}, error) {
+	var v interface{} = value
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+		if err := decoder.Decode(&v); err != nil {
+			return nil, fmt.Errorf("expected a JSON value for %s: %w", t.String(), err)
+		}
+	}
+
+	converted, err := convertArg(t, v)
+	if err != nil {
+		return nil, err
+	}
+	return converted.Interface(), nil
+}
+
+// convertArg converts a string, or decoded JSON value, to the Go value of the ABI type
+func convertArg(t abi.Type, v interface{}) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return convertInt(t, v)
+
+	case abi.BoolTy:
+		switch b := v.(type) {
+		case bool:
+			return reflect.ValueOf(b), nil
+		case string:
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid bool %q", b)
+			}
+			return reflect.ValueOf(parsed), nil
+		}
+
+	case abi.StringTy:
+		if s, ok := v.(string); ok {
+			return reflect.ValueOf(s), nil
+		}
+
+	case abi.AddressTy:
+		if s, ok := v.(string); ok {
+			address, err := ParseAddress(s)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(address), nil
+		}
+
+	case abi.BytesTy, abi.FixedBytesTy:
+		s, ok := v.(string)
+		if !ok {
+			break
+		}
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bytes %q, expected 0x prefixed hex: %w", s, err)
+		}
+		if t.T == abi.BytesTy {
+			return reflect.ValueOf(b), nil
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("invalid %s %q, expected %d bytes", t.String(), s, t.Size)
+		}
+		array := reflect.New(t.GetType()).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		var list reflect.Value
+		if t.T == abi.SliceTy {
+			list = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		} else {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d items for %s, got %d", t.Size, t.String(), len(items))
+			}
+			list = reflect.New(t.GetType()).Elem()
+		}
+		for i, item := range items {
+			elem, err := convertArg(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %w", i, err)
+			}
+			list.Index(i).Set(elem)
+		}
+		return list, nil
+
+	case abi.TupleTy:
+		return convertTuple(t, v)
+
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t.String())
+	}
+
+	return reflect.Value{}, fmt.Errorf("invalid %s value %v", t.String(), v)
+}
+
+// convertInt converts a decimal or 0x prefixed hex integer to the Go value of the
+// ABI integer type, checking that it is in the type's range
+func convertInt(t abi.Type, v interface{}) (reflect.Value, error) {
+	var s string
+	switch n := v.(type) {
+	case string:
+		s = n
+	case json.Number:
+		s = n.String()
+	default:
+		return reflect.Value{}, fmt.Errorf("invalid %s value %v", t.String(), v)
+	}
+
+	digits, negative := strings.TrimSpace(s), false
+	if strings.HasPrefix(digits, "-") {
+		digits, negative = digits[1:], true
+	}
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits, base = digits[2:], 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || digits == "" {
+		return reflect.Value{}, fmt.Errorf("invalid integer %q", s)
+	}
+	if negative {
+		n.Neg(n)
+	}
+
+	// Check the value fits the type, e.g. uint8 is [0, 255] and int8 is [-128, 127]
+	size := uint(t.Size)
+	var min, max *big.Int
+	if t.T == abi.UintTy {
+		min = new(big.Int)
+		max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), size), big.NewInt(1))
+	} else {
+		max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), size-1), big.NewInt(1))
+		min = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), size-1))
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return reflect.Value{}, fmt.Errorf("%s is out of range for %s", s, t.String())
+	}
+
+	typ := t.GetType()
+	switch {
+	case typ == bigIntType:
+		return reflect.ValueOf(n), nil
+	case t.T == abi.UintTy:
+		return reflect.ValueOf(n.Uint64()).Convert(typ), nil
+	default:
+		return reflect.ValueOf(n.Int64()).Convert(typ), nil
+	}
+}
+
+// convertTuple converts a JSON array of the tuple's components in order, or a JSON
+// object of the components by name, to the Go struct of the ABI tuple type
+func convertTuple(t abi.Type, v interface{}) (reflect.Value, error) {
+	tuple := reflect.New(t.GetType()).Elem()
+
+	switch components := v.(type) {
+	case []interface{}:
+		if len(components) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected %d components for %s, got %d", len(t.TupleElems), t.String(), len(components))
+		}
+		for i, elem := range t.TupleElems {
+			field, err := convertArg(*elem, components[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("component %s: %w", t.TupleRawNames[i], err)
+			}
+			tuple.Field(i).Set(field)
+		}
+
+	case map[string]interface{}:
+		if len(components) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected components %s for %s", strings.Join(t.TupleRawNames, ", "), t.String())
+		}
+		for i, elem := range t.TupleElems {
+			component, ok := components[t.TupleRawNames[i]]
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("missing component %s", t.TupleRawNames[i])
+			}
+			field, err := convertArg(*elem, component)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("component %s: %w", t.TupleRawNames[i], err)
+			}
+			tuple.Field(i).Set(field)
+		}
+
+	default:
+		return reflect.Value{}, fmt.Errorf("invalid %s value %v, expected a JSON array or object", t.String(), v)
+	}
+
+	return tuple, nil
+}
+
+// describeArgs returns the arguments as "(address to, uint256 amount)"
+func describeArgs(args abi.Arguments) string {
+	described := make([]string, len(args))
+	for i, arg := range args {
+		described[i] = describeArg(arg)
+	}
+	return "(" + strings.Join(described, ", ") + ")"
+}
+
+// describeArg returns the argument as "uint256 amount", or its type if it has no name
+func describeArg(arg abi.Argument) string {
+	if arg.Name == "" {
+		return arg.Type.String()
+	}
+	return arg.Type.String() + " " + arg.Name
+}
+
+// NamedValue is a decoded return value or event argument
+type NamedValue struct {
+	Name  string      `json:"name,omitempty"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// FormatValues returns the decoded values of the arguments, for display
+func FormatValues(args abi.Arguments, values []interface{}) []NamedValue {
+	named := make([]NamedValue, len(values))
+	for i, value := range values {
+		if i >= len(args) {
+			named[i] = NamedValue{Value: FormatValue(abi.Type{}, value)}
+			continue
+		}
+		named[i] = NamedValue{Name: args[i].Name, Type: args[i].Type.String(), Value: FormatValue(args[i].Type, value)}
+	}
+	return named
+}
+
+// FormatValue converts a value of the ABI type, as decoded by the ABI, to a value that
+// can be displayed, or encoded as JSON: integers are decimal strings, addresses, bytes
+// and fixed size bytes are hex, and arrays and tuples are formatted element by element.
+// The type tells bytesN from uint8[N], which both decode to a [N]byte array.
+func FormatValue(t abi.Type, value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		if t.T == abi.FixedBytesTy {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		elem := abi.Type{}
+		if t.Elem != nil {
+			elem = *t.Elem
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = FormatValue(elem, rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			name := rv.Type().Field(i).Tag.Get("json")
+			if name == "" {
+				name = rv.Type().Field(i).Name
+			}
+			elem := abi.Type{}
+			if i < len(t.TupleElems) {
+				elem = *t.TupleElems[i]
+			}
+			fields[name] = FormatValue(elem, rv.Field(i).Interface())
+		}
+		return fields
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	}
+	return value
+}
+
+// CallMethod calls a method of the contract at address without sending a transaction,
+// and returns its decoded return values
+func CallMethod(ctx context.Context, backend bind.ContractCaller, address common.Address, contractABI *abi.ABI, method *abi.Method, args []interface{}) ([]NamedValue, error) {
+	contract := bind.NewBoundContract(address, *contractABI, backend, nil, nil)
+
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, method.Name, args...); err != nil {
+		return nil, err
+	}
+	return FormatValues(method.Outputs, out), nil
+}
+
+// DecodedEvent is an event of a contract, decoded with the contract's ABI
+type DecodedEvent struct {
+	Name     string         `json:"name"`
+	Address  common.Address `json:"address"`
+	LogIndex uint           `json:"log_index"`
+	Args     []NamedValue   `json:"args"`
+}
+
+// DecodeLogs returns the decoded events of the logs emitted by the contract at address.
+// Logs of other contracts, or of events that are not in the ABI, are skipped.
+func DecodeLogs(contractABI *abi.ABI, address common.Address, logs []*types.Log) ([]DecodedEvent, error) {
+	var events []DecodedEvent
+	for _, log := range logs {
+		if log.Address != address || len(log.Topics) == 0 {
+			continue
+		}
+		if _, err := contractABI.EventByID(log.Topics[0]); err != nil {
+			continue
+		}
+
+		event, err := DecodeLog(contractABI, *log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+// DecodeLog returns the decoded event of the log. Indexed arguments of dynamic types,
+// such as strings, are only available as their keccak256 hash.
+func DecodeLog(contractABI *abi.ABI, log types.Log) (*DecodedEvent, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.New("log has no topics")
+	}
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if len(log.Data) > 0 {
+		if err := event.Inputs.UnpackIntoMap(values, log.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s data: %w", event.Name, err)
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("failed to decode %s topics: %w", event.Name, err)
+	}
+
+	decoded := &DecodedEvent{Name: event.Name, Address: log.Address, LogIndex: log.Index}
+	for _, input := range event.Inputs {
+		decoded.Args = append(decoded.Args, NamedValue{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: FormatValue(input.Type, values[input.Name]),
+		})
+	}
+	return decoded, nil
+}
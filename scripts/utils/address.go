@@ -0,0 +1,191 @@
+/** address.go contains the conversion of account addresses between the 0x hex format
+  of the EVM and the evmos1 bech32 format of the Cosmos SDK, shown by evmosd keys.
+  Both formats encode the same 20 bytes, so either is accepted for an address.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bech32Prefix is the human readable part of evmos account addresses
+const Bech32Prefix = "evmos"
+
+// bech32Charset is the alphabet of the bech32 data part, from BIP-173
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// ErrInvalidBech32 is returned for strings that are not valid bech32
+var ErrInvalidBech32 = errors.New("invalid bech32")
+
+// AddressToBech32 returns the evmos1 bech32 format of an address
+func AddressToBech32(address common.Address) string {
+	data, err := convertBits(address.Bytes(), 8, 5, true)
+	if err != nil {
+		// Converting to 5 bit groups with padding cannot fail
+		panic(err)
+	}
+	return bech32Encode(Bech32Prefix, data)
+}
+
+// Bech32ToAddress returns the address of an evmos1 bech32 account address
+func Bech32ToAddress(value string) (common.Address, error) {
+	hrp, data, err := bech32Decode(value)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if hrp != Bech32Prefix {
+		return common.Address{}, fmt.Errorf("%w: prefix %q, expected %q", ErrInvalidBech32, hrp, Bech32Prefix)
+	}
+
+	decoded, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(decoded) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("%w: %d bytes, expected an address of %d bytes", ErrInvalidBech32, len(decoded), common.AddressLength)
+	}
+	return common.BytesToAddress(decoded), nil
+}
+
+// IsAddress returns whether the value is a 0x hex or evmos1 bech32 address
+func IsAddress(value string) bool {
+	_, err := ParseAddress(value)
+	return err == nil
+}
+
+// ParseAddress returns the address given as 0x hex, or in the evmos1 bech32 format
+func ParseAddress(value string) (common.Address, error) {
+	if common.IsHexAddress(value) {
+		return common.HexToAddress(value), nil
+	}
+	if strings.HasPrefix(strings.ToLower(value), Bech32Prefix+"1") {
+		return Bech32ToAddress(value)
+	}
+	return common.Address{}, fmt.Errorf("invalid address %q, expected 0x hex or %s1 bech32", value, Bech32Prefix)
+}
+
+// bech32Encode returns the bech32 string of the 5 bit data groups, with its checksum
+func bech32Encode(hrp string, data []byte) string {
+	values := append(append([]byte{}, data...), bech32Checksum(hrp, data)...)
+
+	var b strings.Builder
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range values {
+		b.WriteByte(bech32Charset[v])
+	}
+	return b.String()
+}
+
+// bech32Decode returns the human readable part and the 5 bit data groups of a bech32
+// string, after checking its checksum
+func bech32Decode(value string) (string, []byte, error) {
+	if strings.ToLower(value) != value && strings.ToUpper(value) != value {
+		return "", nil, fmt.Errorf("%w: mixed case", ErrInvalidBech32)
+	}
+	value = strings.ToLower(value)
+
+	// The separator is the last 1, and the checksum takes the last 6 characters
+	separator := strings.LastIndexByte(value, '1')
+	if separator < 1 || separator+7 > len(value) {
+		return "", nil, fmt.Errorf("%w: missing separator or checksum", ErrInvalidBech32)
+	}
+
+	hrp := value[:separator]
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			return "", nil, fmt.Errorf("%w: invalid prefix character %q", ErrInvalidBech32, c)
+		}
+	}
+
+	data := make([]byte, 0, len(value)-separator-1)
+	for _, c := range value[separator+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return "", nil, fmt.Errorf("%w: invalid character %q", ErrInvalidBech32, c)
+		}
+		data = append(data, byte(v))
+	}
+
+	if bech32Polymod(append(bech32ExpandHRP(hrp), data...)) != 1 {
+		return "", nil, fmt.Errorf("%w: invalid checksum", ErrInvalidBech32)
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// bech32Checksum returns the 6 checksum groups of the data
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32ExpandHRP(hrp), data...)
+	mod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return checksum
+}
+
+// bech32ExpandHRP returns the human readable part as checksummed by bech32
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// bech32Polymod is the BCH checksum function of bech32
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range generator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits regroups data from groups of fromBits to groups of toBits. Without
+// padding, the leftover bits must be zero padding of less than a group.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+
+	var converted []byte
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("%w: invalid data value %d", ErrInvalidBech32, v)
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			converted = append(converted, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			converted = append(converted, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidBech32)
+	}
+	return converted, nil
+}
@@ -0,0 +1,114 @@
+/** amount.go contains exact conversions between token amounts in base units, and
+  human readable decimal strings such as "10.5 TOK", using the token's decimals.
+  No floating point math is used, so no precision is lost for large balances.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrTooManyDecimals is returned when an amount has more fractional digits than
+// the token's decimals
+var ErrTooManyDecimals = errors.New("too many decimal places")
+
+// FormatAmount returns the base unit amount as an exact decimal string with the given
+// number of decimals, without trailing fractional zeros, e.g. 10500000000000000000
+// with 18 decimals is "10.5"
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	digits := new(big.Int).Abs(amount).String()
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+
+	d := int(decimals)
+	if d == 0 {
+		return sign + digits
+	}
+
+	// Pad with leading zeros, so that there is at least one whole digit
+	if len(digits) <= d {
+		digits = strings.Repeat("0", d-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-d]
+	fraction := strings.TrimRight(digits[len(digits)-d:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + fraction
+}
+
+// FormatTokenAmount returns the base unit amount as an exact decimal string followed
+// by the token symbol, e.g. "10.5 TOK"
+func FormatTokenAmount(amount *big.Int, decimals uint8, symbol string) string {
+	if symbol == "" {
+		return FormatAmount(amount, decimals)
+	}
+	return FormatAmount(amount, decimals) + " " + symbol
+}
+
+// ParseAmount parses an exact, non-negative decimal string such as "10.5" into base
+// units with the given number of decimals. ErrTooManyDecimals is returned if the
+// string has more fractional digits than the decimals.
+func ParseAmount(value string, decimals uint8) (*big.Int, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return nil, errors.New("empty amount")
+	}
+
+	whole, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("invalid amount %q, expected a decimal number such as 10.5", value)
+	}
+
+	// Trailing zeros never exceed the token's precision
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("%w: %q has %d fractional digits, the token supports %d", ErrTooManyDecimals, value, len(fraction), decimals)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+
+	return amount, nil
+}
+
+// ParseTokenAmount parses a decimal string such as "10.5" or "10.5 TOK" into base units.
+// If a symbol is given, it must match the token symbol.
+func ParseTokenAmount(value string, decimals uint8, symbol string) (*big.Int, error) {
+	s := strings.TrimSpace(value)
+	if i := strings.IndexFunc(s, func(r rune) bool { return r == ' ' || r == '\t' }); i >= 0 {
+		given := strings.TrimSpace(s[i:])
+		if !strings.EqualFold(given, symbol) {
+			return nil, fmt.Errorf("invalid amount %q, the token symbol is %s", value, symbol)
+		}
+		s = s[:i]
+	}
+
+	return ParseAmount(s, decimals)
+}
+
+// isDigits reports whether the string only contains decimal digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
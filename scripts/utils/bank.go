@@ -0,0 +1,51 @@
+/** bank.go contains the query of an account's native token balance from the Cosmos
+  SDK bank module, through the REST API of an evmos node. The EVM balance of an
+  account is its bank balance of the EVM denomination, aevmos.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// NativeDenom is the base denomination of the evmos native token, 10^-18 EVMOS
+	NativeDenom = "aevmos"
+
+	// NativeDecimals is the number of decimals of the evmos native token
+	NativeDecimals = 18
+)
+
+// bankBalanceResponse is the response of the bank module's balance by denom query
+type bankBalanceResponse struct {
+	Balance struct {
+		Denom  string `json:"denom"`
+		Amount string `json:"amount"`
+	} `json:"balance"`
+}
+
+// GetBankBalance returns the bank balance of the account in the denomination, from the
+// REST API at restURL, such as http://localhost:1317
+func GetBankBalance(ctx context.Context, restURL string, account common.Address, denom string) (*big.Int, error) {
+	var balance bankBalanceResponse
+	path := fmt.Sprintf("/cosmos/bank/v1beta1/balances/%s/by_denom", AddressToBech32(account))
+	if err := restGet(ctx, restURL, path, url.Values{"denom": {denom}}, &balance); err != nil {
+		return nil, fmt.Errorf("failed to query %s bank balance: %w", denom, err)
+	}
+
+	// An account without the denomination has an empty amount
+	if balance.Balance.Amount == "" {
+		return new(big.Int), nil
+	}
+	amount, ok := new(big.Int).SetString(balance.Balance.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid bank balance amount %q", balance.Balance.Amount)
+	}
+	return amount, nil
+}
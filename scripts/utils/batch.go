@@ -0,0 +1,432 @@
+/** batch.go contains the batch token transfers read from a CSV file of
+  address,amount rows, and the checkpoint file that records the transaction of
+  each row, so that an interrupted batch can be resumed without sending any
+  transfer twice.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// CheckpointVersion is the version of the checkpoint format written by this package
+const CheckpointVersion = 1
+
+// Statuses of a batch row
+const (
+	// BatchUnsent rows have no transaction yet
+	BatchUnsent = "unsent"
+	// BatchSent rows have a transaction that is not yet confirmed
+	BatchSent = "sent"
+	// BatchSuccess rows have a confirmed, successful transaction
+	BatchSuccess = "success"
+	// BatchReverted rows have a confirmed, reverted transaction, and are sent again on resume
+	BatchReverted = "reverted"
+)
+
+// ErrCheckpointMismatch is returned when a checkpoint file belongs to a different batch
+var ErrCheckpointMismatch = errors.New("checkpoint does not match the batch")
+
+// TransferRow is a single validated row of a batch transfer CSV file
+type TransferRow struct {
+	Line   int
+	To     common.Address
+	Amount *big.Int
+}
+
+// BatchBackend defines the node methods used to send and confirm a batch
+type BatchBackend interface {
+	NonceBackend
+	ReceiptBackend
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+}
+
+// RevertBackend defines the node methods used to replay reverted batch transfers
+type RevertBackend interface {
+	bind.ContractCaller
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+}
+
+// TransferFunc builds and signs a token transfer with the given transaction options
+type TransferFunc func(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error)
+
+// BalanceFunc returns the token balance of an account
+type BalanceFunc func(account common.Address) (*big.Int, error)
+
+// ReadTransferCSV reads and validates every row of a CSV file of address,amount rows,
+// with decimal token amounts such as 10.5, and returns the rows with amounts in base
+// units together with a hash of the file.
+// An optional header row is skipped. All invalid rows are reported together, so that
+// nothing is sent from a partly valid file.
+func ReadTransferCSV(path string, sender common.Address, decimals uint8) ([]TransferRow, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var (
+		rows     []TransferRow
+		problems []string
+		seen     = make(map[common.Address]int)
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid CSV file %s: %w", path, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(rows) == 0 && len(problems) == 0 && isTransferHeader(record) {
+			continue
+		}
+
+		if len(record) != 2 {
+			problems = append(problems, fmt.Sprintf("line %d: expected address,amount, got %d fields", line, len(record)))
+			continue
+		}
+
+		to, err := ParseAddress(strings.TrimSpace(record[0]))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+
+		amount, err := ParseAmount(record[1], decimals)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		if amount.Sign() <= 0 {
+			problems = append(problems, fmt.Sprintf("line %d: amount must be positive", line))
+			continue
+		}
+
+		switch {
+		case to == common.Address{}:
+			problems = append(problems, fmt.Sprintf("line %d: recipient is the zero address", line))
+			continue
+		case to == sender:
+			problems = append(problems, fmt.Sprintf("line %d: recipient is the sender", line))
+			continue
+		}
+		if first, ok := seen[to]; ok {
+			problems = append(problems, fmt.Sprintf("line %d: duplicate recipient %s, first on line %d", line, to.Hex(), first))
+			continue
+		}
+		seen[to] = line
+
+		rows = append(rows, TransferRow{Line: line, To: to, Amount: amount})
+	}
+
+	if len(problems) > 0 {
+		return nil, "", fmt.Errorf("invalid CSV file %s:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+	if len(rows) == 0 {
+		return nil, "", fmt.Errorf("CSV file %s has no transfers", path)
+	}
+
+	return rows, hex.EncodeToString(sum[:]), nil
+}
+
+// isTransferHeader reports whether a CSV record is the address,amount header row
+func isTransferHeader(record []string) bool {
+	return len(record) == 2 &&
+		strings.EqualFold(strings.TrimSpace(record[0]), "address") &&
+		strings.EqualFold(strings.TrimSpace(record[1]), "amount")
+}
+
+// CheckpointRow records the transaction and status of a single batch row
+type CheckpointRow struct {
+	Line         int            `json:"line"`
+	To           common.Address `json:"to"`
+	Amount       *big.Int       `json:"amount"`
+	StartBalance *big.Int       `json:"start_balance"`
+	TxHash       *common.Hash   `json:"tx_hash,omitempty"`
+	Status       string         `json:"status"`
+	// Reason is the decoded revert reason of a reverted transfer
+	Reason string `json:"reason,omitempty"`
+}
+
+// BatchCheckpoint records the progress of a batch transfer
+type BatchCheckpoint struct {
+	Version  int             `json:"version"`
+	Network  string          `json:"network"`
+	Contract common.Address  `json:"contract"`
+	Sender   common.Address  `json:"sender"`
+	Input    string          `json:"input_sha256"`
+	Rows     []CheckpointRow `json:"rows"`
+}
+
+// NewBatchCheckpoint returns the checkpoint of a new batch, recording the starting
+// balance of each recipient for the final reconciliation
+func NewBatchCheckpoint(network string, contract, sender common.Address, input string, rows []TransferRow, balanceOf BalanceFunc) (*BatchCheckpoint, error) {
+	checkpoint := &BatchCheckpoint{
+		Version:  CheckpointVersion,
+		Network:  network,
+		Contract: contract,
+		Sender:   sender,
+		Input:    input,
+		Rows:     make([]CheckpointRow, len(rows)),
+	}
+
+	for i, row := range rows {
+		balance, err := balanceOf(row.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balance of %s: %w", row.To.Hex(), err)
+		}
+		checkpoint.Rows[i] = CheckpointRow{
+			Line:         row.Line,
+			To:           row.To,
+			Amount:       row.Amount,
+			StartBalance: balance,
+			Status:       BatchUnsent,
+		}
+	}
+
+	return checkpoint, nil
+}
+
+// LoadBatchCheckpoint reads and returns the checkpoint file at the given path.
+// A missing file is returned as an error wrapping os.ErrNotExist.
+func LoadBatchCheckpoint(path string) (*BatchCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := new(BatchCheckpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+
+	if checkpoint.Version > CheckpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has version %d, newer than the supported version %d", path, checkpoint.Version, CheckpointVersion)
+	}
+
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to the given path
+func (c *BatchCheckpoint) Save(path string) error {
+	return writeJSONFile(path, c)
+}
+
+// Matches returns ErrCheckpointMismatch if the checkpoint was written for a different
+// network, contract, sender or CSV file
+func (c *BatchCheckpoint) Matches(network string, contract, sender common.Address, input string) error {
+	switch {
+	case c.Network != network:
+		return fmt.Errorf("%w: checkpoint is for network %q, not %q", ErrCheckpointMismatch, c.Network, network)
+	case c.Contract != contract:
+		return fmt.Errorf("%w: checkpoint is for contract %s, not %s", ErrCheckpointMismatch, c.Contract.Hex(), contract.Hex())
+	case c.Sender != sender:
+		return fmt.Errorf("%w: checkpoint is for sender %s, not %s", ErrCheckpointMismatch, c.Sender.Hex(), sender.Hex())
+	case c.Input != input:
+		return fmt.Errorf("%w: the CSV file has changed since the checkpoint was written", ErrCheckpointMismatch)
+	}
+	return nil
+}
+
+// Unsent returns the total amount of the rows that still need a transaction
+func (c *BatchCheckpoint) Unsent() *big.Int {
+	total := new(big.Int)
+	for _, row := range c.Rows {
+		if row.Status == BatchUnsent || row.Status == BatchReverted {
+			total.Add(total, row.Amount)
+		}
+	}
+	return total
+}
+
+// Count returns the number of rows with the given status
+func (c *BatchCheckpoint) Count(status string) int {
+	n := 0
+	for _, row := range c.Rows {
+		if row.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Resume checks the transactions of rows left as sent by an interrupted run.
+// Mined transactions are given their final status, transactions still known to the
+// node are left to be confirmed, and transactions the node has dropped are marked
+// as unsent, so that they are sent again.
+func (c *BatchCheckpoint) Resume(ctx context.Context, backend BatchBackend, path string) error {
+	for i := range c.Rows {
+		row := &c.Rows[i]
+		if row.Status != BatchSent {
+			continue
+		}
+
+		receipt, err := backend.TransactionReceipt(ctx, *row.TxHash)
+		if err == nil && receipt != nil {
+			row.Status = ReceiptStatus(receipt)
+			continue
+		}
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("failed to get receipt of line %d: %w", row.Line, err)
+		}
+
+		_, _, err = backend.TransactionByHash(ctx, *row.TxHash)
+		switch {
+		case err == nil:
+			// Still pending, confirmed by Confirm
+		case errors.Is(err, ethereum.NotFound):
+			row.Status = BatchUnsent
+			row.TxHash = nil
+		default:
+			return fmt.Errorf("failed to get transaction of line %d: %w", row.Line, err)
+		}
+	}
+
+	return c.Save(path)
+}
+
+// Send sends a transfer for every unsent or reverted row, with nonces from the nonce
+// manager, so that no transfer waits for the one before it. The checkpoint is saved
+// after each transfer is sent.
+func (c *BatchCheckpoint) Send(ctx context.Context, nonces *NonceManager, auth *bind.TransactOpts, transfer TransferFunc, path string) error {
+	for i := range c.Rows {
+		row := &c.Rows[i]
+		if row.Status != BatchUnsent && row.Status != BatchReverted {
+			continue
+		}
+
+		tx, err := nonces.Send(ctx, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return transfer(opts, row.To, row.Amount)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send transfer of line %d: %w", row.Line, err)
+		}
+
+		hash := tx.Hash()
+		row.TxHash = &hash
+		row.Status = BatchSent
+		row.Reason = ""
+		if err := c.Save(path); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Confirm waits for the receipt of every sent row, and saves its status
+func (c *BatchCheckpoint) Confirm(ctx context.Context, backend ReceiptBackend, opts WaitOptions, path string) error {
+	for i := range c.Rows {
+		row := &c.Rows[i]
+		if row.Status != BatchSent {
+			continue
+		}
+
+		receipt, err := WaitForReceipt(ctx, backend, *row.TxHash, opts)
+		if err != nil && !errors.Is(err, ErrTxReverted) {
+			return fmt.Errorf("failed to confirm transfer of line %d: %w", row.Line, err)
+		}
+
+		row.Status = ReceiptStatus(receipt)
+		if err := c.Save(path); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Explain replays the transaction of every reverted row without a reason, and saves
+// its decoded revert reason, with custom errors decoded from the ABIs
+func (c *BatchCheckpoint) Explain(ctx context.Context, backend RevertBackend, abis []*abi.ABI, path string) error {
+	for i := range c.Rows {
+		row := &c.Rows[i]
+		if row.Status != BatchReverted || row.Reason != "" {
+			continue
+		}
+
+		tx, _, err := backend.TransactionByHash(ctx, *row.TxHash)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction of line %d: %w", row.Line, err)
+		}
+		receipt, err := backend.TransactionReceipt(ctx, *row.TxHash)
+		if err != nil {
+			return fmt.Errorf("failed to get receipt of line %d: %w", row.Line, err)
+		}
+
+		var revertErr *RevertError
+		if errors.As(ExplainRevertedTx(ctx, backend, tx, receipt, ErrTxReverted, abis), &revertErr) {
+			row.Reason = revertErr.Reason
+		}
+		if err := c.Save(path); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Reconciliation compares the expected and actual balance of a batch recipient
+type Reconciliation struct {
+	Line     int            `json:"line"`
+	To       common.Address `json:"to"`
+	Status   string         `json:"status"`
+	Expected *big.Int       `json:"expected"`
+	Actual   *big.Int       `json:"actual"`
+	Match    bool           `json:"match"`
+}
+
+// Reconcile compares the current balance of each recipient with its starting balance,
+// plus its amount if the row's transfer succeeded
+func (c *BatchCheckpoint) Reconcile(balanceOf BalanceFunc) ([]Reconciliation, error) {
+	results := make([]Reconciliation, len(c.Rows))
+	for i, row := range c.Rows {
+		expected := new(big.Int).Set(row.StartBalance)
+		if row.Status == BatchSuccess {
+			expected.Add(expected, row.Amount)
+		}
+
+		actual, err := balanceOf(row.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balance of %s: %w", row.To.Hex(), err)
+		}
+
+		results[i] = Reconciliation{
+			Line:     row.Line,
+			To:       row.To,
+			Status:   row.Status,
+			Expected: expected,
+			Actual:   actual,
+			Match:    expected.Cmp(actual) == 0,
+		}
+	}
+
+	return results, nil
+}
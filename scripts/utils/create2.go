@@ -0,0 +1,109 @@
+/** create2.go contains the helpers for deterministic CREATE2 deployments. Contracts
+  are deployed through a minimal factory, whose address together with a salt and the
+  creation code fixes the address of the deployed contract, whatever the nonce of
+  the deployer.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// Create2FactoryName is the deployment manifest name of the CREATE2 factory
+	Create2FactoryName = "Create2Factory"
+
+	// create2FactoryRuntime is the runtime code of the deterministic deployment proxy
+	// (github.com/Arachnid/deterministic-deployment-proxy). It is called with a 32 byte
+	// salt followed by the creation code, deploys it with CREATE2, and returns the
+	// 20 byte address of the new contract, reverting if the deployment fails.
+	create2FactoryRuntime = "7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3"
+)
+
+// Create2FactoryMetaData is the creation code of the CREATE2 factory, which has no ABI
+// as it is called with raw calldata
+var Create2FactoryMetaData = &bind.MetaData{
+	ABI: "[]",
+	Bin: "0x604580600e600039806000f350fe" + create2FactoryRuntime,
+}
+
+// ErrNotCreate2Factory is returned when the code at an address is not the CREATE2 factory
+var ErrNotCreate2Factory = errors.New("not a CREATE2 factory")
+
+// ParseSalt returns the CREATE2 salt of a value given as 0x hex of at most 32 bytes,
+// which is left padded with zeros, or else as text, whose keccak256 hash is the salt
+func ParseSalt(value string) (common.Hash, error) {
+	if value == "" {
+		return common.Hash{}, errors.New("salt is empty")
+	}
+
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		salt, err := hexToBytes(value)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("invalid salt %q: %w", value, err)
+		}
+		if len(salt) > common.HashLength {
+			return common.Hash{}, fmt.Errorf("invalid salt %q, longer than 32 bytes", value)
+		}
+		return common.BytesToHash(salt), nil
+	}
+
+	return crypto.Keccak256Hash([]byte(value)), nil
+}
+
+// PredictCreate2Address returns the address of the contract that the factory deploys
+// from the creation code, including any constructor arguments, with the salt
+func PredictCreate2Address(factory common.Address, salt common.Hash, creation []byte) common.Address {
+	return crypto.CreateAddress2(factory, salt, crypto.Keccak256(creation))
+}
+
+// DeployCreate2Factory deploys the CREATE2 factory, returning its address and the
+// deployment transaction
+func DeployCreate2Factory(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+	address, tx, _, err := bind.DeployContract(auth, abi.ABI{}, common.FromHex(Create2FactoryMetaData.Bin), backend)
+	return address, tx, err
+}
+
+// CheckCreate2Factory checks that the code at the address is the CREATE2 factory
+func CheckCreate2Factory(ctx context.Context, backend CodeReader, address common.Address) error {
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get code at %s: %w", address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w %s", ErrNoCode, address.Hex())
+	}
+	if !bytes.Equal(code, common.FromHex(create2FactoryRuntime)) {
+		return fmt.Errorf("%w: %s", ErrNotCreate2Factory, address.Hex())
+	}
+	return nil
+}
+
+// Create2Deploy sends a transaction deploying the creation code, including any
+// constructor arguments, through the factory with the salt. The new contract is at
+// PredictCreate2Address, as the receipt has no contract address.
+func Create2Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, factory common.Address, salt common.Hash, creation []byte) (*types.Transaction, error) {
+	contract := bind.NewBoundContract(factory, abi.ABI{}, backend, backend, backend)
+	return contract.RawTransact(auth, append(salt.Bytes(), creation...))
+}
+
+// hexToBytes decodes 0x prefixed hex, which may have an odd number of digits
+func hexToBytes(value string) ([]byte, error) {
+	digits := value[2:]
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	return hex.DecodeString(digits)
+}
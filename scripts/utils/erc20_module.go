@@ -0,0 +1,124 @@
+/** erc20_module.go contains the flow of the evmos x/erc20 module, which pairs an ERC20
+  contract with a Cosmos coin, so that tokens can be converted between the two. An
+  ERC20 contract is paired by a register-erc20 governance proposal, with the coin
+  erc20/<contract address>, and converted with MsgConvertERC20 and MsgConvertCoin.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// grpcNotFound is the gRPC status code of queries for missing items
+const grpcNotFound = 5
+
+// ErrTokenPairNotFound is returned when a token is not paired by the erc20 module
+var ErrTokenPairNotFound = errors.New("token pair not found")
+
+// TokenPair is a pairing of an ERC20 contract with a Cosmos coin
+type TokenPair struct {
+	ERC20Address  string `json:"erc20_address"`
+	Denom         string `json:"denom"`
+	Enabled       bool   `json:"enabled"`
+	ContractOwner string `json:"contract_owner"`
+}
+
+// ERC20Denom returns the denomination of the Cosmos coin paired with a registered
+// ERC20 contract
+func ERC20Denom(contract common.Address) string {
+	return "erc20/" + contract.Hex()
+}
+
+// GetTokenPair returns the token pair of an ERC20 address or Cosmos denomination from
+// the REST API at restURL
+func GetTokenPair(ctx context.Context, restURL, token string) (*TokenPair, error) {
+	var res struct {
+		TokenPair TokenPair `json:"token_pair"`
+	}
+	err := restGet(ctx, restURL, "/evmos/erc20/v1/token_pairs/"+url.PathEscape(token), nil, &res)
+	var restErr *RESTError
+	if errors.As(err, &restErr) && (restErr.Code == grpcNotFound || restErr.StatusCode == http.StatusNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrTokenPairNotFound, token)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token pair of %s: %w", token, err)
+	}
+	return &res.TokenPair, nil
+}
+
+// GetTokenPairs returns every token pair of the erc20 module from the REST API at
+// restURL
+func GetTokenPairs(ctx context.Context, restURL string) ([]TokenPair, error) {
+	var pairs []TokenPair
+	query := url.Values{}
+
+	for {
+		var res struct {
+			TokenPairs []TokenPair `json:"token_pairs"`
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+		if err := restGet(ctx, restURL, "/evmos/erc20/v1/token_pairs", query, &res); err != nil {
+			return nil, fmt.Errorf("failed to query token pairs: %w", err)
+		}
+
+		pairs = append(pairs, res.TokenPairs...)
+		if res.Pagination.NextKey == "" {
+			return pairs, nil
+		}
+		query.Set("pagination.key", res.Pagination.NextKey)
+	}
+}
+
+// WaitForTokenPair polls the token pair of the ERC20 contract every interval, until
+// it is registered
+func WaitForTokenPair(ctx context.Context, restURL string, contract common.Address, interval time.Duration) (*TokenPair, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pair, err := GetTokenPair(ctx, restURL, contract.Hex())
+		if err == nil {
+			return pair, nil
+		}
+		if !errors.Is(err, ErrTokenPairNotFound) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", err, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ConvertERC20 converts an amount of the paired ERC20 tokens of the from key into
+// Cosmos coins of the receiver, with MsgConvertERC20
+func (e *Evmosd) ConvertERC20(ctx context.Context, from string, contract common.Address, amount *big.Int, receiver common.Address) (*TxResponse, error) {
+	res, err := e.Tx(ctx, from, "erc20", "convert-erc20", contract.Hex(), amount.String(), AddressToBech32(receiver))
+	if err != nil {
+		return res, fmt.Errorf("failed to convert ERC20 to coins: %w", err)
+	}
+	return res, nil
+}
+
+// ConvertCoin converts an amount of the paired Cosmos coins of the from key into ERC20
+// tokens of the receiver, with MsgConvertCoin
+func (e *Evmosd) ConvertCoin(ctx context.Context, from, denom string, amount *big.Int, receiver common.Address) (*TxResponse, error) {
+	res, err := e.Tx(ctx, from, "erc20", "convert-coin", amount.String()+denom, receiver.Hex())
+	if err != nil {
+		return res, fmt.Errorf("failed to convert coins to ERC20: %w", err)
+	}
+	return res, nil
+}
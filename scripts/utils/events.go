@@ -0,0 +1,634 @@
+/** events.go contains the Token event stream, which backfills Transfer and Approval
+  events over a block range in chunks, then follows new blocks as they are announced
+  by a websocket subscription. Decoded events are written as JSON lines, and progress
+  is recorded in a checkpoint file, together with recent block hashes used to detect
+  and rewind chain reorganisations.
+*/
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+)
+
+const (
+	// EventCheckpointVersion is the version of the event checkpoint format written by this package
+	EventCheckpointVersion = 1
+
+	// DefaultEventChunkSize is the number of blocks queried for logs at once
+	DefaultEventChunkSize = 1000
+
+	// maxRecentBlocks is the number of processed block hashes kept to rewind reorgs
+	maxRecentBlocks = 128
+
+	// maxFetchRetries is the number of times a chunk is fetched again if the chain
+	// changes while it is being fetched
+	maxFetchRetries = 3
+)
+
+// Event types written by an EventStream
+const (
+	EventTransfer = "Transfer"
+	EventApproval = "Approval"
+	// EventReorg is written to outputs that cannot be rewound, when events from
+	// the given block onwards were removed by a reorg
+	EventReorg = "Reorg"
+)
+
+// ErrReorgTooDeep is returned when a reorg replaced every block recorded in the checkpoint
+var ErrReorgTooDeep = errors.New("reorg is deeper than the recent blocks in the checkpoint")
+
+// EventFilter selects the Token events written by an EventStream. Empty address lists
+// match any address.
+type EventFilter struct {
+	Transfers bool
+	Approvals bool
+	From      []common.Address
+	To        []common.Address
+	Owner     []common.Address
+	Spender   []common.Address
+}
+
+// String returns a canonical description of the filter, which is recorded in the checkpoint
+func (f EventFilter) String() string {
+	var parts []string
+	if f.Transfers {
+		parts = append(parts, "transfer(from="+joinAddresses(f.From)+",to="+joinAddresses(f.To)+")")
+	}
+	if f.Approvals {
+		parts = append(parts, "approval(owner="+joinAddresses(f.Owner)+",spender="+joinAddresses(f.Spender)+")")
+	}
+	return strings.Join(parts, ";")
+}
+
+// joinAddresses returns the sorted hex addresses joined by "|", or "*" for no addresses
+func joinAddresses(addresses []common.Address) string {
+	if len(addresses) == 0 {
+		return "*"
+	}
+	hexes := make([]string, len(addresses))
+	for i, address := range addresses {
+		hexes[i] = address.Hex()
+	}
+	sort.Strings(hexes)
+	return strings.Join(hexes, "|")
+}
+
+// TokenEvent is a decoded Transfer or Approval event, written as a single JSON line
+type TokenEvent struct {
+	Type      string          `json:"type"`
+	Block     uint64          `json:"block"`
+	BlockHash *common.Hash    `json:"block_hash,omitempty"`
+	TxHash    *common.Hash    `json:"tx_hash,omitempty"`
+	LogIndex  uint            `json:"log_index"`
+	From      *common.Address `json:"from,omitempty"`
+	To        *common.Address `json:"to,omitempty"`
+	Owner     *common.Address `json:"owner,omitempty"`
+	Spender   *common.Address `json:"spender,omitempty"`
+	Value     string          `json:"value,omitempty"`
+}
+
+// newTokenEvent returns the event of the given type for a log
+func newTokenEvent(eventType string, log types.Log, value *big.Int) TokenEvent {
+	return TokenEvent{
+		Type:      eventType,
+		Block:     log.BlockNumber,
+		BlockHash: &log.BlockHash,
+		TxHash:    &log.TxHash,
+		LogIndex:  log.Index,
+		Value:     value.String(),
+	}
+}
+
+// BlockRecord is a processed block, with the output offset after its events
+type BlockRecord struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	Offset int64       `json:"offset"`
+}
+
+// EventCheckpoint records the progress of an EventStream
+type EventCheckpoint struct {
+	Version  int            `json:"version"`
+	Network  string         `json:"network"`
+	Contract common.Address `json:"contract"`
+	Filter   string         `json:"filter"`
+	Next     uint64         `json:"next_block"`
+	Offset   int64          `json:"offset"`
+	Recent   []BlockRecord  `json:"recent_blocks"`
+}
+
+// NewEventCheckpoint returns the checkpoint of a new event stream, starting at the given block
+func NewEventCheckpoint(network string, contract common.Address, filter EventFilter, fromBlock uint64) *EventCheckpoint {
+	return &EventCheckpoint{
+		Version:  EventCheckpointVersion,
+		Network:  network,
+		Contract: contract,
+		Filter:   filter.String(),
+		Next:     fromBlock,
+	}
+}
+
+// LoadEventCheckpoint reads and returns the event checkpoint file at the given path.
+// A missing file is returned as an error wrapping os.ErrNotExist.
+func LoadEventCheckpoint(path string) (*EventCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := new(EventCheckpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+
+	if checkpoint.Version > EventCheckpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has version %d, newer than the supported version %d", path, checkpoint.Version, EventCheckpointVersion)
+	}
+
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to the given path. An empty path does nothing.
+func (c *EventCheckpoint) Save(path string) error {
+	if path == "" {
+		return nil
+	}
+	return writeJSONFile(path, c)
+}
+
+// Matches returns ErrCheckpointMismatch if the checkpoint was written for a different
+// network, contract or filter
+func (c *EventCheckpoint) Matches(network string, contract common.Address, filter EventFilter) error {
+	switch {
+	case c.Network != network:
+		return fmt.Errorf("%w: checkpoint is for network %q, not %q", ErrCheckpointMismatch, c.Network, network)
+	case c.Contract != contract:
+		return fmt.Errorf("%w: checkpoint is for contract %s, not %s", ErrCheckpointMismatch, c.Contract.Hex(), contract.Hex())
+	case c.Filter != filter.String():
+		return fmt.Errorf("%w: checkpoint is for filter %s, not %s", ErrCheckpointMismatch, c.Filter, filter.String())
+	}
+	return nil
+}
+
+// EventOutput writes events as JSON lines, and counts the bytes written so that
+// file outputs can be rewound to a checkpoint
+type EventOutput struct {
+	file   *os.File
+	w      *bufio.Writer
+	offset int64
+}
+
+// NewEventOutput returns an output writing to the given writer, such as stdout.
+// Such outputs cannot be rewound, so reorgs are written as Reorg events.
+func NewEventOutput(w io.Writer) *EventOutput {
+	return &EventOutput{w: bufio.NewWriter(w)}
+}
+
+// OpenEventOutput opens the file at path for writing events at the given offset.
+// Anything after the offset was written after the last saved checkpoint, and is
+// removed so that no event is written twice.
+func OpenEventOutput(path string, offset int64) (*EventOutput, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() < offset {
+		file.Close()
+		return nil, fmt.Errorf("output %s is shorter than its checkpoint offset %d", path, offset)
+	}
+
+	output := &EventOutput{file: file, w: bufio.NewWriter(file)}
+	if err := output.truncate(offset); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return output, nil
+}
+
+// write writes a single event as a JSON line
+func (o *EventOutput) write(event TokenEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	n, err := o.w.Write(append(data, '\n'))
+	o.offset += int64(n)
+	return err
+}
+
+// writeBatch writes the events as JSON lines and flushes them, and returns the offset
+// of the output after each event. The events are encoded before anything is written,
+// so the offset only advances once the whole batch is written.
+func (o *EventOutput) writeBatch(events []TokenEvent) ([]int64, error) {
+	var buf bytes.Buffer
+	offsets := make([]int64, len(events))
+	for i, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(append(data, '\n'))
+		offsets[i] = o.offset + int64(buf.Len())
+	}
+
+	if _, err := o.w.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := o.Flush(); err != nil {
+		return nil, err
+	}
+	o.offset += int64(buf.Len())
+	return offsets, nil
+}
+
+// truncate removes everything after the offset of a file output
+func (o *EventOutput) truncate(offset int64) error {
+	if err := o.file.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := o.file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	o.offset = offset
+	return nil
+}
+
+// rewind removes the events written after the offset, from the given block onwards
+func (o *EventOutput) rewind(offset int64, block uint64) error {
+	if err := o.w.Flush(); err != nil {
+		return err
+	}
+	if o.file == nil {
+		return o.write(TokenEvent{Type: EventReorg, Block: block})
+	}
+	return o.truncate(offset)
+}
+
+// Flush writes any buffered events, and syncs file outputs to disk
+func (o *EventOutput) Flush() error {
+	if err := o.w.Flush(); err != nil {
+		return err
+	}
+	if o.file != nil {
+		return o.file.Sync()
+	}
+	return nil
+}
+
+// Close flushes and closes the output
+func (o *EventOutput) Close() error {
+	err := o.Flush()
+	if o.file != nil {
+		if closeErr := o.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// EventBackend defines the node methods used to backfill events
+type EventBackend interface {
+	bind.ContractFilterer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// HeadSubscriber defines the node method used to follow new blocks
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// EventStream writes the Token events matching a filter to an output, recording its
+// progress in a checkpoint
+type EventStream struct {
+	backend        EventBackend
+	filterer       *token.TokenFilterer
+	filter         EventFilter
+	checkpoint     *EventCheckpoint
+	checkpointPath string
+	output         *EventOutput
+
+	// ChunkSize is the number of blocks queried for logs at once
+	ChunkSize uint64
+	// Confirmations is the number of blocks a block must be behind the head to be processed
+	Confirmations uint64
+}
+
+// NewEventStream returns an event stream of the Token contract at the checkpoint's
+// address. The checkpoint is saved to checkpointPath after every chunk, unless the
+// path is empty.
+func NewEventStream(backend EventBackend, filter EventFilter, checkpoint *EventCheckpoint, checkpointPath string, output *EventOutput) (*EventStream, error) {
+	if !filter.Transfers && !filter.Approvals {
+		return nil, errors.New("the filter selects no events")
+	}
+
+	// Outputs that are not files, such as stdout, continue from the offset of the
+	// checkpoint, so that the offsets it records keep counting what was written
+	if output.file == nil {
+		output.offset = checkpoint.Offset
+	}
+
+	filterer, err := token.NewTokenFilterer(checkpoint.Contract, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventStream{
+		backend:        backend,
+		filterer:       filterer,
+		filter:         filter,
+		checkpoint:     checkpoint,
+		checkpointPath: checkpointPath,
+		output:         output,
+		ChunkSize:      DefaultEventChunkSize,
+	}, nil
+}
+
+// headerByNumber returns the header of the given block, or of the head if number is nil.
+// A missing block is returned as ethereum.NotFound, as some backends return no header
+// and no error.
+func (s *EventStream) headerByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := s.backend.HeaderByNumber(ctx, number)
+	if err == nil && header == nil {
+		return nil, ethereum.NotFound
+	}
+	return header, err
+}
+
+// Sync writes the events of every block from the checkpoint up to and including the
+// given block, in chunks, after rewinding any reorg of the blocks already processed
+func (s *EventStream) Sync(ctx context.Context, to uint64) error {
+	if err := s.checkReorg(ctx); err != nil {
+		return err
+	}
+
+	chunk := s.ChunkSize
+	if chunk == 0 {
+		chunk = DefaultEventChunkSize
+	}
+
+	for s.checkpoint.Next <= to {
+		end := s.checkpoint.Next + chunk - 1
+		if end > to {
+			end = to
+		}
+		if err := s.processRange(ctx, s.checkpoint.Next, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncToHead writes the events of every block up to the current head, less the
+// required confirmations
+func (s *EventStream) SyncToHead(ctx context.Context) error {
+	head, err := s.headerByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	target := head.Number.Uint64()
+	if target < s.Confirmations {
+		return nil
+	}
+	return s.Sync(ctx, target-s.Confirmations)
+}
+
+// Follow writes the events of new blocks as they are announced by the subscriber,
+// until the context is cancelled or the subscription fails
+func (s *EventStream) Follow(ctx context.Context, heads HeadSubscriber) error {
+	ch := make(chan *types.Header, 16)
+	sub, err := heads.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	// Catch up after subscribing, so that no block is missed in between
+	if err := s.SyncToHead(ctx); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case head := <-ch:
+			number := head.Number.Uint64()
+			if number < s.Confirmations {
+				continue
+			}
+			if err := s.Sync(ctx, number-s.Confirmations); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// processRange writes the events of the blocks from and to, and records the chunk in
+// the checkpoint
+func (s *EventStream) processRange(ctx context.Context, from, to uint64) error {
+	// Record the block before the first chunk, so that a reorg of the first chunk
+	// can be rewound
+	if len(s.checkpoint.Recent) == 0 && from > 0 {
+		parent, err := s.headerByNumber(ctx, new(big.Int).SetUint64(from-1))
+		if err != nil {
+			return err
+		}
+		s.checkpoint.Recent = append(s.checkpoint.Recent, BlockRecord{Number: from - 1, Hash: parent.Hash(), Offset: s.checkpoint.Offset})
+	}
+
+	var (
+		events []TokenEvent
+		hash   common.Hash
+	)
+	for attempt := 0; ; attempt++ {
+		header, err := s.headerByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return err
+		}
+
+		events, err = s.fetch(ctx, from, to)
+		if err != nil {
+			return err
+		}
+
+		// Check that the chain did not change while the logs were fetched
+		check, err := s.headerByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return err
+		}
+		if check.Hash() == header.Hash() {
+			hash = header.Hash()
+			break
+		}
+		if attempt == maxFetchRetries {
+			return fmt.Errorf("block %d kept changing while its logs were fetched", to)
+		}
+	}
+
+	// The chunk is written and flushed before the checkpoint advances, so a failed
+	// write leaves the checkpoint at the start of the chunk. A crash before the
+	// checkpoint is saved leaves extra output that OpenEventOutput removes on restart.
+	offsets, err := s.output.writeBatch(events)
+	if err != nil {
+		return err
+	}
+
+	// Record every block with events, as well as the end of the chunk, so that a
+	// reorg within the chunk only rewinds the blocks it replaced
+	var records []BlockRecord
+	for i, event := range events {
+		if event.Block != to && (i == len(events)-1 || events[i+1].Block != event.Block) {
+			records = append(records, BlockRecord{Number: event.Block, Hash: *event.BlockHash, Offset: offsets[i]})
+		}
+	}
+	records = append(records, BlockRecord{Number: to, Hash: hash, Offset: s.output.offset})
+
+	s.checkpoint.Next = to + 1
+	s.checkpoint.Offset = s.output.offset
+	s.checkpoint.Recent = append(s.checkpoint.Recent, records...)
+	if len(s.checkpoint.Recent) > maxRecentBlocks {
+		s.checkpoint.Recent = s.checkpoint.Recent[len(s.checkpoint.Recent)-maxRecentBlocks:]
+	}
+
+	return s.checkpoint.Save(s.checkpointPath)
+}
+
+// fetch returns the decoded events of the blocks from and to, in log order
+func (s *EventStream) fetch(ctx context.Context, from, to uint64) ([]TokenEvent, error) {
+	opts := &bind.FilterOpts{Start: from, End: &to, Context: ctx}
+	var events []TokenEvent
+
+	if s.filter.Transfers {
+		transfers, err := s.fetchTransfers(opts)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, transfers...)
+	}
+
+	if s.filter.Approvals {
+		approvals, err := s.fetchApprovals(opts)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, approvals...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Block != events[j].Block {
+			return events[i].Block < events[j].Block
+		}
+		return events[i].LogIndex < events[j].LogIndex
+	})
+
+	return events, nil
+}
+
+// fetchTransfers returns the decoded Transfer events matching the filter
+func (s *EventStream) fetchTransfers(opts *bind.FilterOpts) ([]TokenEvent, error) {
+	it, err := s.filterer.FilterTransfer(opts, s.filter.From, s.filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter transfers: %w", err)
+	}
+	defer it.Close()
+
+	var events []TokenEvent
+	for it.Next() {
+		event := newTokenEvent(EventTransfer, it.Event.Raw, it.Event.Value)
+		event.From, event.To = &it.Event.From, &it.Event.To
+		events = append(events, event)
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to decode transfers: %w", err)
+	}
+	return events, nil
+}
+
+// fetchApprovals returns the decoded Approval events matching the filter
+func (s *EventStream) fetchApprovals(opts *bind.FilterOpts) ([]TokenEvent, error) {
+	it, err := s.filterer.FilterApproval(opts, s.filter.Owner, s.filter.Spender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter approvals: %w", err)
+	}
+	defer it.Close()
+
+	var events []TokenEvent
+	for it.Next() {
+		event := newTokenEvent(EventApproval, it.Event.Raw, it.Event.Value)
+		event.Owner, event.Spender = &it.Event.Owner, &it.Event.Spender
+		events = append(events, event)
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	}
+	return events, nil
+}
+
+// checkReorg compares the hash of the last processed block with the chain. If it was
+// replaced, the stream is rewound to the newest recorded block still on the chain.
+func (s *EventStream) checkReorg(ctx context.Context) error {
+	recent := s.checkpoint.Recent
+	for i := len(recent) - 1; i >= 0; i-- {
+		header, err := s.headerByNumber(ctx, new(big.Int).SetUint64(recent[i].Number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if err == nil && header.Hash() == recent[i].Hash {
+			if i == len(recent)-1 {
+				return nil
+			}
+			return s.rewind(recent[i], i)
+		}
+	}
+
+	if len(recent) == 0 {
+		return nil
+	}
+	return ErrReorgTooDeep
+}
+
+// rewind resets the stream to just after the given recorded block
+func (s *EventStream) rewind(record BlockRecord, index int) error {
+	if err := s.output.rewind(record.Offset, record.Number+1); err != nil {
+		return err
+	}
+	if err := s.output.Flush(); err != nil {
+		return err
+	}
+
+	s.checkpoint.Next = record.Number + 1
+	s.checkpoint.Offset = s.output.offset
+	s.checkpoint.Recent = s.checkpoint.Recent[:index+1]
+	// Keep offsets of stdout outputs, which grow past the reorg event, monotonic
+	s.checkpoint.Recent[index].Offset = s.output.offset
+
+	return s.checkpoint.Save(s.checkpointPath)
+}
@@ -0,0 +1,164 @@
+/** evmosd.go contains a wrapper around the evmosd binary, which signs and broadcasts
+  the Cosmos SDK transactions of the evmos modules, such as governance proposals and
+  ERC20 conversions, with a key of the evmosd keyring, as evmos/init.sh does. The
+  results are decoded from the JSON output of evmosd.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultEvmosdBinary is the evmosd binary run when no other is given
+const DefaultEvmosdBinary = "evmosd"
+
+// DefaultCosmosGasAdjustment is the safety margin applied by evmosd to the gas
+// estimate of each Cosmos transaction
+const DefaultCosmosGasAdjustment = 1.5
+
+// ErrCosmosTxFailed is returned when a Cosmos transaction is rejected or fails
+var ErrCosmosTxFailed = errors.New("cosmos transaction failed")
+
+// CommandRunner runs a command, and returns its standard output
+type CommandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// ExecRunner runs the command as a child process. Its standard error is returned in
+// the error if it fails.
+func ExecRunner(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = os.Stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// Evmosd sends Cosmos transactions with the evmosd binary
+type Evmosd struct {
+	Binary         string
+	Home           string
+	KeyringBackend string
+	Node           string
+	ChainID        string
+	// GasPrices is the price of each unit of gas, such as 1000000000aevmos
+	GasPrices string
+	Run       CommandRunner
+}
+
+// NewEvmosd returns the evmosd wrapper sending transactions to the node of the
+// network profile, signed with the keyring of the signer options
+func NewEvmosd(profile *NetworkProfile, signer SignerOptions) (*Evmosd, error) {
+	if profile.Tendermint == "" {
+		return nil, fmt.Errorf("network profile %q has no tendermint endpoint", profile.Name)
+	}
+	if profile.CosmosChainID == "" {
+		return nil, fmt.Errorf("network profile %q has no cosmos_chain_id", profile.Name)
+	}
+
+	return &Evmosd{
+		Binary:         DefaultEvmosdBinary,
+		Home:           signer.KeyringHome,
+		KeyringBackend: signer.KeyringBackend,
+		Node:           profile.Tendermint,
+		ChainID:        profile.CosmosChainID,
+		Run:            ExecRunner,
+	}, nil
+}
+
+// TxEvent is an event emitted by a Cosmos transaction
+type TxEvent struct {
+	Type       string `json:"type"`
+	Attributes []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"attributes"`
+}
+
+// TxResponse is the result of a broadcast Cosmos transaction
+type TxResponse struct {
+	Height    string `json:"height"`
+	TxHash    string `json:"txhash"`
+	Codespace string `json:"codespace"`
+	Code      uint32 `json:"code"`
+	RawLog    string `json:"raw_log"`
+	GasUsed   string `json:"gas_used"`
+	Logs      []struct {
+		Events []TxEvent `json:"events"`
+	} `json:"logs"`
+}
+
+// Attribute returns the first value of the attribute of the events of the given type
+func (r *TxResponse) Attribute(eventType, key string) (string, bool) {
+	for _, log := range r.Logs {
+		for _, event := range log.Events {
+			if event.Type != eventType {
+				continue
+			}
+			for _, attribute := range event.Attributes {
+				if attribute.Key == key {
+					return attribute.Value, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// Tx signs the evmosd tx subcommand with the from key, broadcasts it, and waits for it
+// to be included in a block
+func (e *Evmosd) Tx(ctx context.Context, from string, args ...string) (*TxResponse, error) {
+	args = append(append([]string{"tx"}, args...),
+		"--from", from,
+		"--keyring-backend", e.KeyringBackend,
+		"--chain-id", e.ChainID,
+		"--node", e.Node,
+		"--gas", "auto",
+		"--gas-adjustment", strconv.FormatFloat(DefaultCosmosGasAdjustment, 'f', -1, 64),
+		"--broadcast-mode", "block",
+		"--output", "json",
+		"--yes",
+	)
+	if e.GasPrices != "" {
+		args = append(args, "--gas-prices", e.GasPrices)
+	}
+	if e.Home != "" {
+		args = append(args, "--home", e.Home)
+	}
+
+	out, err := e.Run(ctx, e.Binary, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(TxResponse)
+	if err := json.Unmarshal(out, res); err != nil {
+		return nil, fmt.Errorf("invalid evmosd output: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if res.Code != 0 {
+		return res, fmt.Errorf("%w: %s code %d: %s", ErrCosmosTxFailed, res.Codespace, res.Code, res.RawLog)
+	}
+	return res, nil
+}
+
+// CosmosGasPrices returns the gas prices flag of evmosd for the EVM gas price, so that
+// Cosmos transactions pay the same base fee as EVM transactions
+func CosmosGasPrices(gasPrice *big.Int) string {
+	return gasPrice.String() + NativeDenom
+}
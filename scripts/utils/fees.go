@@ -0,0 +1,188 @@
+/** fees.go contains the fee and gas limit settings used by GetAuth. Transactions
+  are built as EIP-1559 dynamic fee transactions from the latest base fee where the
+  node supports it, falling back to legacy gas price transactions, and their gas
+  limit is estimated per call with a safety multiplier.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Fee modes selectable with FeeOptions
+const (
+	// FeeModeAuto uses dynamic fees if the latest block has a base fee, and legacy fees otherwise
+	FeeModeAuto = "auto"
+	// FeeModeDynamic always uses dynamic fees
+	FeeModeDynamic = "dynamic"
+	// FeeModeLegacy always uses a legacy gas price
+	FeeModeLegacy = "legacy"
+)
+
+// DefaultGasMultiplier is the safety margin applied to gas estimates
+const DefaultGasMultiplier = 1.2
+
+// ErrNoBaseFee is returned when dynamic fees are required, but the node's latest
+// block has no base fee
+var ErrNoBaseFee = errors.New("latest block has no base fee, the node does not support EIP-1559")
+
+// AuthBackend defines the node queries needed to build transaction options
+type AuthBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// FeeOptions defines how the fees of a transaction are chosen. Nil caps are unlimited.
+type FeeOptions struct {
+	Mode          string
+	MaxFeeCap     *big.Int
+	MaxTipCap     *big.Int
+	GasMultiplier float64
+}
+
+// DefaultFeeOptions uses dynamic fees where supported, without caps
+var DefaultFeeOptions = FeeOptions{
+	Mode:          FeeModeAuto,
+	GasMultiplier: DefaultGasMultiplier,
+}
+
+// RegisterFlags registers the fee options as flags of the given flag set,
+// using the current values as defaults
+func (o *FeeOptions) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Mode, "fee-mode", o.Mode, "transaction fee mode: auto, dynamic or legacy")
+	fs.Var(&bigIntFlag{&o.MaxFeeCap}, "max-fee", "cap on the max fee per gas, or legacy gas price, in wei")
+	fs.Var(&bigIntFlag{&o.MaxTipCap}, "max-tip", "cap on the max priority fee per gas, in wei")
+	fs.Float64Var(&o.GasMultiplier, "gas-multiplier", o.GasMultiplier, "safety multiplier applied to gas estimates")
+}
+
+// applyFees sets either the dynamic fee caps, or the legacy gas price, of the
+// transaction options
+func (o FeeOptions) applyFees(ctx context.Context, backend AuthBackend, auth *bind.TransactOpts) error {
+	switch o.Mode {
+	case FeeModeAuto, FeeModeDynamic, FeeModeLegacy:
+	default:
+		return fmt.Errorf("invalid fee mode %q, expected auto, dynamic or legacy", o.Mode)
+	}
+
+	if o.GasMultiplier < 1 {
+		return fmt.Errorf("invalid gas multiplier %v, expected at least 1", o.GasMultiplier)
+	}
+
+	if o.Mode != FeeModeLegacy {
+		header, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if header.BaseFee != nil {
+			return o.applyDynamicFees(ctx, backend, auth, header.BaseFee)
+		}
+		if o.Mode == FeeModeDynamic {
+			return ErrNoBaseFee
+		}
+	}
+
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	if o.MaxFeeCap != nil && gasPrice.Cmp(o.MaxFeeCap) > 0 {
+		return fmt.Errorf("suggested gas price %v exceeds the max fee %v", gasPrice, o.MaxFeeCap)
+	}
+
+	auth.GasPrice = gasPrice
+	auth.GasFeeCap = nil
+	auth.GasTipCap = nil
+	return nil
+}
+
+// applyDynamicFees sets the dynamic fee caps of the transaction options. The fee cap
+// allows the base fee to double before the transaction is priced out.
+func (o FeeOptions) applyDynamicFees(ctx context.Context, backend AuthBackend, auth *bind.TransactOpts, baseFee *big.Int) error {
+	if o.MaxFeeCap != nil && o.MaxFeeCap.Cmp(baseFee) < 0 {
+		return fmt.Errorf("max fee %v is below the current base fee %v", o.MaxFeeCap, baseFee)
+	}
+
+	tipCap, err := backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return err
+	}
+	if o.MaxTipCap != nil && tipCap.Cmp(o.MaxTipCap) > 0 {
+		tipCap = new(big.Int).Set(o.MaxTipCap)
+	}
+
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+	if o.MaxFeeCap != nil && feeCap.Cmp(o.MaxFeeCap) > 0 {
+		feeCap = new(big.Int).Set(o.MaxFeeCap)
+	}
+
+	// The tip can never exceed the fee cap
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+
+	auth.GasPrice = nil
+	auth.GasFeeCap = feeCap
+	auth.GasTipCap = tipCap
+	return nil
+}
+
+// gasEstimator is a contract backend that applies a safety multiplier to gas estimates
+type gasEstimator struct {
+	bind.ContractBackend
+	multiplier float64
+}
+
+// NewGasEstimator returns a contract backend that multiplies the gas estimates of the
+// given backend. Contracts bound to it estimate the gas limit of each transaction
+// made with options that leave GasLimit at 0, as GetAuth does.
+func NewGasEstimator(backend bind.ContractBackend, multiplier float64) bind.ContractBackend {
+	if multiplier <= 1 {
+		return backend
+	}
+	return &gasEstimator{ContractBackend: backend, multiplier: multiplier}
+}
+
+// EstimateGas returns the backend's gas estimate of the call, with the multiplier applied
+func (e *gasEstimator) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	gas, err := e.ContractBackend.EstimateGas(ctx, call)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(math.Ceil(float64(gas) * e.multiplier)), nil
+}
+
+// bigIntFlag is a flag.Value for an optional big integer, left nil when unset
+type bigIntFlag struct {
+	value **big.Int
+}
+
+func (f *bigIntFlag) String() string {
+	if f.value == nil || *f.value == nil {
+		return ""
+	}
+	return (*f.value).String()
+}
+
+func (f *bigIntFlag) Set(s string) error {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok || v.Sign() < 0 {
+		return fmt.Errorf("invalid amount %q, expected a non-negative integer", s)
+	}
+	*f.value = v
+	return nil
+}
@@ -0,0 +1,169 @@
+/** feesplit.go contains the registration of contracts with the evmos x/feesplit module,
+  which pays a share of the transaction fees of every call to a registered contract
+  to its deployer, or to a withdrawer address. Only the deployer can register a
+  contract, by giving the nonces that derive its address. The fees are paid in the
+  distribute_dev_fee_split events of each call.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EventTypeDistributeDevFeeSplit is the event paying the developer fees of a call
+const EventTypeDistributeDevFeeSplit = "distribute_dev_fee_split"
+
+// devFeesPageSize is the number of transactions queried per page of developer fees
+const devFeesPageSize = 100
+
+// ErrFeeSplitNotFound is returned when a contract is not registered for fee splits
+var ErrFeeSplitNotFound = errors.New("fee split not found")
+
+// FeeSplit is the registration of a contract with the feesplit module. The deployer
+// and withdrawer are bech32 addresses, and the withdrawer is empty when the deployer
+// receives the fees.
+type FeeSplit struct {
+	ContractAddress   string `json:"contract_address"`
+	DeployerAddress   string `json:"deployer_address"`
+	WithdrawerAddress string `json:"withdrawer_address"`
+}
+
+// DevFee is the developer fee paid by a call to a registered contract
+type DevFee struct {
+	TxHash     string   `json:"tx_hash"`
+	Height     string   `json:"height"`
+	Sender     string   `json:"sender"`
+	Withdrawer string   `json:"withdrawer"`
+	Amount     *big.Int `json:"amount"`
+}
+
+// RegisterFeeSplit registers the contract deployed by the from key for fee splits.
+// The nonces are those of the deployer's deployment transaction, followed by those of
+// any factories that created the contract. The deployer receives the fees when the
+// withdrawer is nil.
+func (e *Evmosd) RegisterFeeSplit(ctx context.Context, from string, contract common.Address, nonces []uint64, withdrawer *common.Address) (*TxResponse, error) {
+	values := make([]string, len(nonces))
+	for i, nonce := range nonces {
+		values[i] = strconv.FormatUint(nonce, 10)
+	}
+
+	args := []string{"feesplit", "register", contract.Hex(), strings.Join(values, ",")}
+	if withdrawer != nil {
+		args = append(args, AddressToBech32(*withdrawer))
+	}
+
+	res, err := e.Tx(ctx, from, args...)
+	if err != nil {
+		return res, fmt.Errorf("failed to register fee split of %s: %w", contract.Hex(), err)
+	}
+	return res, nil
+}
+
+// GetFeeSplit returns the fee split registration of the contract from the REST API at
+// restURL. The route of a single contract is shadowed by the route of a withdrawer's
+// fee splits, which has the same pattern, so every fee split is listed.
+func GetFeeSplit(ctx context.Context, restURL string, contract common.Address) (*FeeSplit, error) {
+	query := url.Values{}
+	for {
+		var res struct {
+			FeeSplits  []FeeSplit `json:"fee_splits"`
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+		if err := restGet(ctx, restURL, "/evmos/feesplit/v1/feesplits", query, &res); err != nil {
+			return nil, fmt.Errorf("failed to query fee splits: %w", err)
+		}
+
+		for _, feeSplit := range res.FeeSplits {
+			if common.IsHexAddress(feeSplit.ContractAddress) && common.HexToAddress(feeSplit.ContractAddress) == contract {
+				return &feeSplit, nil
+			}
+		}
+		if res.Pagination.NextKey == "" {
+			return nil, fmt.Errorf("%w: %s", ErrFeeSplitNotFound, contract.Hex())
+		}
+		query.Set("pagination.key", res.Pagination.NextKey)
+	}
+}
+
+// GetDevFees returns the developer fees paid by calls to the contract, from the
+// distribute_dev_fee_split events of the transactions found by the REST API at
+// restURL, and their total
+func GetDevFees(ctx context.Context, restURL string, contract common.Address) ([]DevFee, *big.Int, error) {
+	query := url.Values{
+		"events":           {fmt.Sprintf("%s.contract='%s'", EventTypeDistributeDevFeeSplit, contract.Hex())},
+		"pagination.limit": {strconv.Itoa(devFeesPageSize)},
+		"order_by":         {"ORDER_BY_ASC"},
+	}
+
+	var fees []DevFee
+	total := new(big.Int)
+	for offset := 0; ; offset += devFeesPageSize {
+		query.Set("pagination.offset", strconv.Itoa(offset))
+		var res struct {
+			TxResponses []TxResponse `json:"tx_responses"`
+			Pagination  struct {
+				Total string `json:"total"`
+			} `json:"pagination"`
+		}
+		if err := restGet(ctx, restURL, "/cosmos/tx/v1beta1/txs", query, &res); err != nil {
+			return nil, nil, fmt.Errorf("failed to query developer fees of %s: %w", contract.Hex(), err)
+		}
+
+		for _, tx := range res.TxResponses {
+			for _, log := range tx.Logs {
+				for _, event := range log.Events {
+					if event.Type != EventTypeDistributeDevFeeSplit {
+						continue
+					}
+					fee, paidBy, err := devFeeOf(tx, event)
+					if err != nil {
+						return nil, nil, err
+					}
+					// A transaction may call several registered contracts
+					if paidBy == contract {
+						fees = append(fees, fee)
+						total.Add(total, fee.Amount)
+					}
+				}
+			}
+		}
+
+		count, _ := strconv.Atoi(res.Pagination.Total)
+		if len(res.TxResponses) < devFeesPageSize || offset+devFeesPageSize >= count {
+			return fees, total, nil
+		}
+	}
+}
+
+// devFeeOf returns the developer fee paid by a distribute_dev_fee_split event, and the
+// contract whose call paid it
+func devFeeOf(tx TxResponse, event TxEvent) (DevFee, common.Address, error) {
+	fee := DevFee{TxHash: tx.TxHash, Height: tx.Height, Amount: new(big.Int)}
+	var contract common.Address
+	for _, attribute := range event.Attributes {
+		switch attribute.Key {
+		case "sender":
+			fee.Sender = attribute.Value
+		case "contract":
+			contract = common.HexToAddress(attribute.Value)
+		case "withdrawer_address":
+			fee.Withdrawer = attribute.Value
+		case "amount":
+			if _, ok := fee.Amount.SetString(attribute.Value, 10); !ok {
+				return fee, contract, fmt.Errorf("invalid developer fee %q in transaction %s", attribute.Value, tx.TxHash)
+			}
+		}
+	}
+	return fee, contract, nil
+}
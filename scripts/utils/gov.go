@@ -0,0 +1,103 @@
+/** gov.go contains the governance proposals of the evmos modules: submitting them and
+  voting with evmosd, and following their status through the REST API until they
+  pass or are rejected.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Proposal statuses of the gov module
+const (
+	ProposalStatusDeposit  = "PROPOSAL_STATUS_DEPOSIT_PERIOD"
+	ProposalStatusVoting   = "PROPOSAL_STATUS_VOTING_PERIOD"
+	ProposalStatusPassed   = "PROPOSAL_STATUS_PASSED"
+	ProposalStatusRejected = "PROPOSAL_STATUS_REJECTED"
+	ProposalStatusFailed   = "PROPOSAL_STATUS_FAILED"
+)
+
+// DefaultProposalDeposit is the minimum deposit of a proposal on the localnet started
+// by evmos/init.sh
+const DefaultProposalDeposit = "10000000" + NativeDenom
+
+// Proposal is a governance proposal
+type Proposal struct {
+	ID            string    `json:"proposal_id"`
+	Status        string    `json:"status"`
+	VotingEndTime time.Time `json:"voting_end_time"`
+}
+
+// SubmitRegisterERC20Proposal submits the proposal registering the ERC20 contract
+// with the erc20 module, with the deposit, and returns the proposal ID
+func (e *Evmosd) SubmitRegisterERC20Proposal(ctx context.Context, from, contract, title, description, deposit string) (uint64, *TxResponse, error) {
+	res, err := e.Tx(ctx, from, "gov", "submit-proposal", "register-erc20", contract,
+		"--title", title,
+		"--description", description,
+		"--deposit", deposit,
+	)
+	if err != nil {
+		return 0, res, fmt.Errorf("failed to submit register-erc20 proposal: %w", err)
+	}
+
+	value, ok := res.Attribute("submit_proposal", "proposal_id")
+	if !ok {
+		return 0, res, fmt.Errorf("no proposal ID in the events of transaction %s", res.TxHash)
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, res, fmt.Errorf("invalid proposal ID %q: %w", value, err)
+	}
+	return id, res, nil
+}
+
+// Vote votes on the proposal with the option, such as yes, with the from key
+func (e *Evmosd) Vote(ctx context.Context, from string, id uint64, option string) (*TxResponse, error) {
+	res, err := e.Tx(ctx, from, "gov", "vote", strconv.FormatUint(id, 10), option)
+	if err != nil {
+		return res, fmt.Errorf("failed to vote on proposal %d: %w", id, err)
+	}
+	return res, nil
+}
+
+// GetProposal returns the proposal from the REST API at restURL
+func GetProposal(ctx context.Context, restURL string, id uint64) (*Proposal, error) {
+	var res struct {
+		Proposal Proposal `json:"proposal"`
+	}
+	if err := restGet(ctx, restURL, fmt.Sprintf("/cosmos/gov/v1beta1/proposals/%d", id), nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to query proposal %d: %w", id, err)
+	}
+	return &res.Proposal, nil
+}
+
+// WaitForProposal polls the proposal every interval until its voting period ends, and
+// returns an error unless it passed
+func WaitForProposal(ctx context.Context, restURL string, id uint64, interval time.Duration) (*Proposal, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		proposal, err := GetProposal(ctx, restURL, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch proposal.Status {
+		case ProposalStatusPassed:
+			return proposal, nil
+		case ProposalStatusRejected, ProposalStatusFailed:
+			return proposal, fmt.Errorf("proposal %d ended with status %s", id, proposal.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return proposal, fmt.Errorf("proposal %d is still in %s: %w", id, proposal.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
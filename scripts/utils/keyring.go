@@ -0,0 +1,240 @@
+/** keyring.go contains a read-only adapter for the evmosd "test" and "file" keyring
+  directories. Each key is stored as a JWE file, encrypted with PBES2-HS256+A128KW and
+  A256GCM, holding the amino encoded key info, which in turn holds the private key.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Keyring backends supported by NewKeyringSigner
+const (
+	KeyringBackendTest = "test"
+	KeyringBackendFile = "file"
+)
+
+// testKeyringPassword is the fixed password of the evmosd "test" keyring backend
+const testKeyringPassword = "test"
+
+// keyringItem is the JSON payload of a keyring JWE file
+type keyringItem struct {
+	Key  string
+	Data []byte
+}
+
+// jweHeader is the protected header of a PBES2 keyring JWE file
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	P2c int    `json:"p2c"`
+	P2s string `json:"p2s"`
+}
+
+// NewKeyringSigner returns a Signer for the named key of the evmosd keyring in the
+// given home directory. The "test" backend uses its fixed password, while the "file"
+// backend uses the passphrase returned by the passphrase function.
+// The keyring is only read, never written.
+func NewKeyringSigner(home, backend, name string, passphrase PassphraseFunc) (Signer, error) {
+	var password string
+	switch backend {
+	case KeyringBackendTest:
+		password = testKeyringPassword
+	case KeyringBackendFile:
+		pass, err := passphrase("keyring " + filepath.Join(home, "keyring-file"))
+		if err != nil {
+			return nil, err
+		}
+		password = pass
+	default:
+		return nil, fmt.Errorf("unsupported keyring backend %q, expected test or file", backend)
+	}
+
+	dir := filepath.Join(home, "keyring-"+backend)
+	token, err := os.ReadFile(filepath.Join(dir, strings.ReplaceAll(name, "/", "%2F")+".info"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("key %q not found in keyring %s", name, dir)
+		}
+		return nil, err
+	}
+
+	payload, err := decryptJWE(strings.TrimSpace(string(token)), password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key %q: %w", name, err)
+	}
+
+	var item keyringItem
+	if err := json.Unmarshal(payload, &item); err != nil {
+		return nil, fmt.Errorf("invalid keyring item for key %q: %w", name, err)
+	}
+
+	keyBytes, err := privKeyFromInfo(item.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key info for key %q: %w", name, err)
+	}
+
+	privateKey, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	signer := NewKeySigner(privateKey)
+
+	// The keyring indexes each key by its address, which for eth_secp256k1 keys is
+	// the same as the Ethereum address
+	addressFile := filepath.Join(dir, hex.EncodeToString(signer.Address().Bytes())+".address")
+	if _, err := os.Stat(addressFile); err != nil {
+		return nil, fmt.Errorf("key %q is not an eth_secp256k1 key with address %s", name, signer.Address().Hex())
+	}
+
+	return signer, nil
+}
+
+// decryptJWE decrypts a compact PBES2-HS256+A128KW / A256GCM JWE token with a password
+func decryptJWE(token, password string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, errors.New("invalid JWE token")
+	}
+
+	decoded := make([][]byte, len(parts))
+	for i, part := range parts {
+		b, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWE token part %d: %w", i, err)
+		}
+		decoded[i] = b
+	}
+
+	var header jweHeader
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid JWE header: %w", err)
+	}
+	if header.Alg != "PBES2-HS256+A128KW" || header.Enc != "A256GCM" {
+		return nil, fmt.Errorf("unsupported JWE algorithm %s / %s", header.Alg, header.Enc)
+	}
+
+	salt, err := base64.RawURLEncoding.DecodeString(header.P2s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWE salt: %w", err)
+	}
+
+	// Derive the key encryption key from the password
+	saltInput := append(append([]byte(header.Alg), 0), salt...)
+	kek := pbkdf2.Key([]byte(password), saltInput, header.P2c, 16, sha256.New)
+
+	cek, err := aesKeyUnwrap(kek, decoded[1])
+	if err != nil {
+		return nil, errors.New("incorrect password")
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(decoded[2]))
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := append(decoded[3], decoded[4]...)
+	return gcm.Open(nil, decoded[2], ciphertext, []byte(parts[0]))
+}
+
+// aesKeyUnwrap unwraps a key with the RFC 3394 AES key wrap algorithm
+func aesKeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, errors.New("invalid wrapped key length")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	r := make([]byte, n*8)
+	copy(r, wrapped[8:])
+
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Decrypt(buf, buf)
+			copy(a, buf[:8])
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+
+	if !bytes.Equal(a, []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}) {
+		return nil, errors.New("key unwrap integrity check failed")
+	}
+
+	return r, nil
+}
+
+// privKeyFromInfo returns the private key bytes from an amino encoded, length prefixed
+// keyring localInfo. The private key is held in field 3, as the amino encoding of the
+// private key, which ends with the 32 byte key preceded by its length.
+func privKeyFromInfo(info []byte) ([]byte, error) {
+	length, n := binary.Uvarint(info)
+	if n <= 0 || uint64(len(info)-n) != length {
+		return nil, errors.New("invalid length prefix")
+	}
+
+	// Skip the length prefix and the 4 byte amino prefix of the concrete info type
+	if len(info) < n+4 {
+		return nil, errors.New("info too short")
+	}
+	fields := info[n+4:]
+
+	for len(fields) > 0 {
+		tag, n := binary.Uvarint(fields)
+		if n <= 0 {
+			return nil, errors.New("invalid field tag")
+		}
+		fields = fields[n:]
+
+		if tag&7 != 2 {
+			return nil, fmt.Errorf("unexpected wire type %d", tag&7)
+		}
+
+		size, n := binary.Uvarint(fields)
+		if n <= 0 || uint64(len(fields)-n) < size {
+			return nil, errors.New("invalid field length")
+		}
+		value := fields[n : n+int(size)]
+		fields = fields[n+int(size):]
+
+		if tag>>3 != 3 {
+			continue
+		}
+
+		if len(value) < 33 || value[len(value)-33] != 32 {
+			return nil, errors.New("info does not hold a local secp256k1 private key")
+		}
+		return value[len(value)-32:], nil
+	}
+
+	return nil, errors.New("info does not hold a private key")
+}
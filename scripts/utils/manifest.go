@@ -0,0 +1,185 @@
+/** manifest.go contains the deployment manifest, a versioned JSON file that records
+  the contracts deployed to each network, together with a history of their earlier
+  deployments.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// ManifestVersion is the version of the manifest format written by this package
+	ManifestVersion = 1
+
+	// DefaultManifestFile is the manifest file used when no other path is given
+	DefaultManifestFile = "deployments.json"
+)
+
+// ErrContractNotDeployed is returned when the manifest has no deployment of a
+// contract on a network
+var ErrContractNotDeployed = errors.New("contract not deployed")
+
+// Deployment describes a single deployment of a contract
+type Deployment struct {
+	Contract     string         `json:"contract"`
+	Address      common.Address `json:"address"`
+	TxHash       common.Hash    `json:"tx_hash"`
+	BlockNumber  uint64         `json:"block_number"`
+	ChainID      int64          `json:"chain_id"`
+	Deployer     common.Address `json:"deployer"`
+	GasUsed      uint64         `json:"gas_used"`
+	ABIHash      common.Hash    `json:"abi_hash"`
+	BytecodeHash common.Hash    `json:"bytecode_hash"`
+	DeployedAt   time.Time      `json:"deployed_at"`
+	// Token holds the constructor parameters of a Token deployment
+	Token *TokenParams `json:"token,omitempty"`
+}
+
+// ContractManifest holds the current and earlier deployments of a contract
+type ContractManifest struct {
+	Current Deployment   `json:"current"`
+	History []Deployment `json:"history,omitempty"`
+}
+
+// NetworkManifest holds the contracts deployed to a network, by contract name
+type NetworkManifest struct {
+	Contracts map[string]*ContractManifest `json:"contracts"`
+}
+
+// Manifest holds the deployments of every network, by network profile name
+type Manifest struct {
+	Version  int                         `json:"version"`
+	Networks map[string]*NetworkManifest `json:"networks"`
+}
+
+// NewDeployment returns the deployment of a contract from its binding metadata and
+// the receipt of its deployment transaction
+func NewDeployment(name string, metadata *bind.MetaData, chainID *big.Int, deployer common.Address, receipt *types.Receipt) Deployment {
+	return Deployment{
+		Contract:     name,
+		Address:      receipt.ContractAddress,
+		TxHash:       receipt.TxHash,
+		BlockNumber:  receipt.BlockNumber.Uint64(),
+		ChainID:      chainID.Int64(),
+		Deployer:     deployer,
+		GasUsed:      receipt.GasUsed,
+		ABIHash:      crypto.Keccak256Hash([]byte(metadata.ABI)),
+		BytecodeHash: crypto.Keccak256Hash(common.FromHex(metadata.Bin)),
+		DeployedAt:   time.Now().UTC(),
+	}
+}
+
+// LoadManifest reads and returns the manifest at the given path.
+// A missing manifest file results in an empty manifest.
+func LoadManifest(path string) (*Manifest, error) {
+	if path == "" {
+		path = DefaultManifestFile
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{Version: ManifestVersion, Networks: map[string]*NetworkManifest{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := new(Manifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	if manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("manifest %s has unsupported version %d, expected at most %d", path, manifest.Version, ManifestVersion)
+	}
+	manifest.Version = ManifestVersion
+
+	if manifest.Networks == nil {
+		manifest.Networks = map[string]*NetworkManifest{}
+	}
+
+	return manifest, nil
+}
+
+// Save writes the manifest to the given path. The file is replaced atomically,
+// so an interrupted write does not corrupt an existing manifest.
+func (m *Manifest) Save(path string) error {
+	if path == "" {
+		path = DefaultManifestFile
+	}
+
+	return writeJSONFile(path, m)
+}
+
+// Record sets the deployment as the current deployment of its contract on the network,
+// moving any previous deployment into the contract's history
+func (m *Manifest) Record(network string, deployment Deployment) {
+	networkManifest, ok := m.Networks[network]
+	if !ok {
+		networkManifest = &NetworkManifest{Contracts: map[string]*ContractManifest{}}
+		m.Networks[network] = networkManifest
+	}
+
+	contract, ok := networkManifest.Contracts[deployment.Contract]
+	if !ok {
+		networkManifest.Contracts[deployment.Contract] = &ContractManifest{Current: deployment}
+		return
+	}
+
+	contract.History = append(contract.History, contract.Current)
+	contract.Current = deployment
+}
+
+// Lookup returns the current deployment of the named contract on the network
+func (m *Manifest) Lookup(network, name string) (*Deployment, error) {
+	networkManifest, ok := m.Networks[network]
+	if !ok {
+		return nil, fmt.Errorf("%w: no deployments on network %q", ErrContractNotDeployed, network)
+	}
+
+	contract, ok := networkManifest.Contracts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: no deployment of %q on network %q", ErrContractNotDeployed, name, network)
+	}
+
+	deployment := contract.Current
+	return &deployment, nil
+}
+
+// ResolveContract returns the address of a contract given either as a hex or bech32
+// address, or as a contract name deployed to the network profile according to the
+// manifest at path
+func ResolveContract(path string, profile *NetworkProfile, contract string) (common.Address, error) {
+	if IsAddress(contract) {
+		return ParseAddress(contract)
+	}
+
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	deployment, err := manifest.Lookup(profile.Name, contract)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	// A manifest entry for another chain is stale, e.g. after the localnet was restarted
+	if profile.ChainID != 0 && deployment.ChainID != profile.ChainID {
+		return common.Address{}, fmt.Errorf("%w: manifest entry for %q is on chain %d, network %q expects %d", ErrChainIDMismatch, contract, deployment.ChainID, profile.Name, profile.ChainID)
+	}
+
+	return deployment.Address, nil
+}
@@ -0,0 +1,243 @@
+/** network.go contains the named network profiles used to connect to an evmos
+  node. Profiles are read from a JSON config file (networks.json), and each field
+  can be overridden with an environment variable.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// DefaultNetworksFile is the config file read when no other path is given
+	DefaultNetworksFile = "networks.json"
+
+	// DefaultNetwork is the profile used when no profile is selected
+	DefaultNetwork = "local"
+)
+
+// Environment variables that override the selected profile
+const (
+	EnvNetworksFile = "EVMOS_NETWORKS_FILE"
+	EnvNetwork      = "EVMOS_NETWORK"
+	EnvHTTP         = "EVMOS_RPC_HTTP"
+	EnvWS           = "EVMOS_RPC_WS"
+	EnvREST         = "EVMOS_REST"
+	EnvTendermint   = "EVMOS_TENDERMINT_RPC"
+	EnvCosmosChain  = "EVMOS_COSMOS_CHAIN_ID"
+	EnvChainID      = "EVMOS_CHAIN_ID"
+	EnvGasLimit     = "EVMOS_GAS_LIMIT"
+	EnvGasPrice     = "EVMOS_GAS_PRICE"
+)
+
+// ErrChainIDMismatch is returned when the node's chain ID differs from the
+// chain ID expected by the network profile
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// NetworkProfile defines the endpoints, expected chain ID and default gas settings
+// of a named network. REST is the Cosmos SDK REST API, used for module queries, and
+// Tendermint the RPC endpoint that evmosd broadcasts Cosmos transactions to, on the
+// chain with the Cosmos chain ID, such as evmos_9000-1.
+type NetworkProfile struct {
+	Name          string   `json:"-"`
+	HTTP          string   `json:"http"`
+	WS            string   `json:"ws,omitempty"`
+	REST          string   `json:"rest,omitempty"`
+	Tendermint    string   `json:"tendermint,omitempty"`
+	ChainID       int64    `json:"chain_id"`
+	CosmosChainID string   `json:"cosmos_chain_id,omitempty"`
+	GasLimit      uint64   `json:"gas_limit,omitempty"`
+	GasPrice      *big.Int `json:"gas_price,omitempty"`
+}
+
+// NetworkConfig defines the contents of a networks config file
+type NetworkConfig struct {
+	Default  string                    `json:"default"`
+	Networks map[string]NetworkProfile `json:"networks"`
+}
+
+// LocalNetwork is the profile of the local node started by evmos/init.sh.
+// It is used when no config file is present.
+var LocalNetwork = NetworkProfile{
+	Name:          DefaultNetwork,
+	HTTP:          "http://localhost:8545",
+	WS:            "ws://localhost:8546",
+	REST:          "http://localhost:1317",
+	Tendermint:    "tcp://localhost:26657",
+	ChainID:       9000,
+	CosmosChainID: "evmos_9000-1",
+}
+
+// LoadNetworkConfig reads and returns the networks config file at the given path.
+// If the path is empty, the path from EVMOS_NETWORKS_FILE or DefaultNetworksFile is used.
+// A missing default config file results in a config containing only LocalNetwork.
+func LoadNetworkConfig(path string) (*NetworkConfig, error) {
+	if path == "" {
+		path = os.Getenv(EnvNetworksFile)
+	}
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultNetworksFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &NetworkConfig{
+				Default:  DefaultNetwork,
+				Networks: map[string]NetworkProfile{DefaultNetwork: LocalNetwork},
+			}, nil
+		}
+		return nil, err
+	}
+
+	config := new(NetworkConfig)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid networks file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Profile returns the named network profile with environment variable overrides applied.
+// If the name is empty, the profile from EVMOS_NETWORK or the config default is used.
+func (c *NetworkConfig) Profile(name string) (*NetworkProfile, error) {
+	if name == "" {
+		name = os.Getenv(EnvNetwork)
+	}
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		name = DefaultNetwork
+	}
+
+	profile, ok := c.Networks[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown network profile %q", name)
+	}
+	profile.Name = name
+
+	if err := profile.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if profile.HTTP == "" {
+		return nil, fmt.Errorf("network profile %q has no http endpoint", name)
+	}
+
+	return &profile, nil
+}
+
+// LoadNetworkProfile loads the networks config file at path, and returns the named profile
+func LoadNetworkProfile(path, name string) (*NetworkProfile, error) {
+	config, err := LoadNetworkConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Profile(name)
+}
+
+// applyEnv overrides the profile fields with any set environment variables
+func (p *NetworkProfile) applyEnv() error {
+	if v := os.Getenv(EnvHTTP); v != "" {
+		p.HTTP = v
+	}
+
+	if v := os.Getenv(EnvWS); v != "" {
+		p.WS = v
+	}
+
+	if v := os.Getenv(EnvREST); v != "" {
+		p.REST = v
+	}
+
+	if v := os.Getenv(EnvTendermint); v != "" {
+		p.Tendermint = v
+	}
+
+	if v := os.Getenv(EnvCosmosChain); v != "" {
+		p.CosmosChainID = v
+	}
+
+	if v := os.Getenv(EnvChainID); v != "" {
+		chainID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvChainID, err)
+		}
+		p.ChainID = chainID
+	}
+
+	if v := os.Getenv(EnvGasLimit); v != "" {
+		gasLimit, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvGasLimit, err)
+		}
+		p.GasLimit = gasLimit
+	}
+
+	if v := os.Getenv(EnvGasPrice); v != "" {
+		gasPrice, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return fmt.Errorf("invalid %s: %q", EnvGasPrice, v)
+		}
+		p.GasPrice = gasPrice
+	}
+
+	return nil
+}
+
+// ApplyGasDefaults sets the profile's default gas limit and gas price on the
+// transaction options, where the profile defines them. A fixed gas price makes the
+// transaction a legacy transaction, so it is refused when the fee options ask for
+// dynamic fees or caps.
+func (p *NetworkProfile) ApplyGasDefaults(auth *bind.TransactOpts, fees FeeOptions) error {
+	if p.GasPrice != nil {
+		switch {
+		case fees.Mode == FeeModeDynamic:
+			return fmt.Errorf("network %q sets a gas price of %v, which conflicts with the dynamic fee mode", p.Name, p.GasPrice)
+		case fees.MaxFeeCap != nil || fees.MaxTipCap != nil:
+			return fmt.Errorf("network %q sets a gas price of %v, which conflicts with the max fee and max tip caps", p.Name, p.GasPrice)
+		}
+	}
+
+	if p.GasLimit != 0 {
+		auth.GasLimit = p.GasLimit
+	}
+	if p.GasPrice != nil {
+		auth.GasPrice = new(big.Int).Set(p.GasPrice)
+		auth.GasFeeCap = nil
+		auth.GasTipCap = nil
+	}
+	return nil
+}
+
+// CheckChainID returns ErrChainIDMismatch if the client's chain ID differs from the
+// profile's expected chain ID. A profile chain ID of 0 accepts any chain.
+func (p *NetworkProfile) CheckChainID(client *ethclient.Client) error {
+	if p.ChainID == 0 {
+		return nil
+	}
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if chainID.Cmp(big.NewInt(p.ChainID)) != 0 {
+		return fmt.Errorf("%w: network %q expects %d, node reports %v", ErrChainIDMismatch, p.Name, p.ChainID, chainID)
+	}
+
+	return nil
+}
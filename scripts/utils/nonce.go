@@ -0,0 +1,228 @@
+/** nonce.go contains a thread-safe nonce manager, which hands out sequential nonces
+  per address, so that many transactions can be sent from one account without
+  waiting for each receipt.
+*/
+
+package utils
+
+import (
+	"context"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxNonceRetries is the number of times Send resyncs and retries a transaction
+// rejected for its nonce
+const maxNonceRetries = 3
+
+// NonceBackend defines the node methods used by a NonceManager
+type NonceBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// NonceManager hands out sequential nonces per address. It queries the node's pending
+// nonce once per address, and again only to resync after a nonce error.
+type NonceManager struct {
+	backend NonceBackend
+
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonces
+}
+
+// accountNonces holds the nonce state of a single address
+type accountNonces struct {
+	synced bool
+	next   uint64
+	// released holds nonces below next that were handed out but never sent,
+	// in ascending order
+	released []uint64
+}
+
+// NewNonceManager returns a NonceManager for the given backend
+func NewNonceManager(backend NonceBackend) *NonceManager {
+	return &NonceManager{
+		backend:  backend,
+		accounts: make(map[common.Address]*accountNonces),
+	}
+}
+
+// account returns the nonce state of the address, which must be called with the lock held
+func (m *NonceManager) account(address common.Address) *accountNonces {
+	acc, ok := m.accounts[address]
+	if !ok {
+		acc = new(accountNonces)
+		m.accounts[address] = acc
+	}
+	return acc
+}
+
+// Next reserves and returns the next nonce of the address. Released nonces are
+// handed out again first, so that no gaps are left.
+func (m *NonceManager) Next(ctx context.Context, address common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(address)
+	if !acc.synced {
+		nonce, err := m.backend.PendingNonceAt(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+		acc.next = nonce
+		acc.synced = true
+	}
+
+	if len(acc.released) > 0 {
+		nonce := acc.released[0]
+		acc.released = acc.released[1:]
+		return nonce, nil
+	}
+
+	nonce := acc.next
+	acc.next++
+	return nonce, nil
+}
+
+// Release returns a reserved nonce, whose transaction was never sent, so that it
+// is handed out again
+func (m *NonceManager) Release(address common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(address)
+	if !acc.synced || nonce >= acc.next {
+		return
+	}
+
+	i := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= nonce })
+	if i < len(acc.released) && acc.released[i] == nonce {
+		return
+	}
+	acc.released = append(acc.released, 0)
+	copy(acc.released[i+1:], acc.released[i:])
+	acc.released[i] = nonce
+
+	// Released nonces at the end of the sequence are simply not handed out yet
+	for len(acc.released) > 0 && acc.released[len(acc.released)-1] == acc.next-1 {
+		acc.released = acc.released[:len(acc.released)-1]
+		acc.next--
+	}
+}
+
+// Resync queries the node's pending nonce of the address. Nonces the node has
+// already seen are skipped, while nonces reserved for transactions still in
+// flight are kept.
+func (m *NonceManager) Resync(ctx context.Context, address common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, err := m.backend.PendingNonceAt(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	acc := m.account(address)
+	if !acc.synced || nonce > acc.next {
+		acc.next = nonce
+	}
+	acc.synced = true
+
+	i := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= nonce })
+	acc.released = acc.released[i:]
+
+	return nil
+}
+
+// Reset forgets the nonce state of the address, so that the next nonce is queried
+// from the node again. It must only be called when no transactions are in flight.
+func (m *NonceManager) Reset(address common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.accounts, address)
+}
+
+// Send reserves a nonce for auth.From, builds and signs a transaction with it by
+// calling transact with a copy of auth, and sends the transaction.
+// Transactions rejected for a used nonce are resent with a new nonce after a resync,
+// and the nonce of a transaction that fails to build or send is released.
+func (m *NonceManager) Send(ctx context.Context, auth *bind.TransactOpts, transact func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	for attempt := 0; ; attempt++ {
+		nonce, err := m.Next(ctx, auth.From)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := *auth
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+		opts.NoSend = true
+		if opts.Context == nil {
+			opts.Context = ctx
+		}
+
+		tx, err := transact(&opts)
+		if err != nil {
+			m.Release(auth.From, nonce)
+			return nil, err
+		}
+
+		err = m.backend.SendTransaction(ctx, tx)
+		switch {
+		case err == nil:
+			return tx, nil
+		case isAlreadyKnown(err):
+			// The node already holds this exact transaction
+			return tx, m.Resync(ctx, auth.From)
+		case isNonceTooLow(err):
+			if resyncErr := m.Resync(ctx, auth.From); resyncErr != nil {
+				return nil, resyncErr
+			}
+			if attempt < maxNonceRetries {
+				continue
+			}
+			return nil, err
+		default:
+			m.Release(auth.From, nonce)
+			return nil, err
+		}
+	}
+}
+
+// ethermintNonceRegexp matches the got and expected nonces of the ethermint "invalid
+// nonce; got 1, expected 2: invalid sequence" error, which is returned for nonces both
+// below and above the account's sequence
+var ethermintNonceRegexp = regexp.MustCompile(`invalid nonce; got (\d+), expected (\d+)`)
+
+// isNonceTooLow reports whether a send error is caused by a nonce the node has already seen,
+// as reported by go-ethereum and ethermint nodes
+func isNonceTooLow(err error) bool {
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "nonce too low") {
+		return true
+	}
+
+	match := ethermintNonceRegexp.FindStringSubmatch(msg)
+	if match == nil {
+		return false
+	}
+	got, gotErr := strconv.ParseUint(match[1], 10, 64)
+	expected, expErr := strconv.ParseUint(match[2], 10, 64)
+	return gotErr == nil && expErr == nil && got < expected
+}
+
+// isAlreadyKnown reports whether a send error is caused by the transaction already
+// being in the node's pool
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "already in mempool")
+}
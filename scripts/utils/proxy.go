@@ -0,0 +1,155 @@
+/** proxy.go contains the helpers for upgradeable contracts behind an ERC-1967 proxy.
+  The proxy keeps the state and delegates every call to an implementation, whose
+  address, and that of the proxy admin, are read from the ERC-1967 storage slots.
+  Transparent proxies are upgraded by their admin, and UUPS proxies by the upgrade
+  function of the implementation itself.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ProxyKind is the kind of an ERC-1967 proxy
+type ProxyKind string
+
+const (
+	// ProxyUUPS is an ERC1967Proxy, upgraded through its implementation
+	ProxyUUPS ProxyKind = "uups"
+
+	// ProxyTransparent is a TransparentUpgradeableProxy, upgraded by its admin
+	ProxyTransparent ProxyKind = "transparent"
+)
+
+// ProxyArtifacts are the OpenZeppelin contracts of each kind of proxy, which are
+// deployed from the build directory
+var ProxyArtifacts = map[ProxyKind]string{
+	ProxyUUPS:        "ERC1967Proxy",
+	ProxyTransparent: "TransparentUpgradeableProxy",
+}
+
+// TokenUpgradeableArtifact is the Token contract for deployment behind a proxy, which
+// is initialised with the Token parameters by the proxy rather than a constructor
+const TokenUpgradeableArtifact = "TokenUpgradeable"
+
+var (
+	// ImplementationSlot is the ERC-1967 slot of the implementation address,
+	// keccak256("eip1967.proxy.implementation") - 1
+	ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+	// AdminSlot is the ERC-1967 slot of the admin address, keccak256("eip1967.proxy.admin") - 1
+	AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+// ErrNotProxy is returned when a contract has no implementation in its ERC-1967 slot
+var ErrNotProxy = errors.New("not an ERC-1967 proxy")
+
+// proxyABI holds the upgrade functions of ERC-1967 proxies and UUPS implementations
+var proxyABI = mustParseABI(`[
+	{"type": "function", "name": "upgradeTo", "stateMutability": "nonpayable", "inputs": [{"name": "newImplementation", "type": "address"}], "outputs": []},
+	{"type": "function", "name": "upgradeToAndCall", "stateMutability": "payable", "inputs": [{"name": "newImplementation", "type": "address"}, {"name": "data", "type": "bytes"}], "outputs": []},
+	{"type": "function", "name": "changeAdmin", "stateMutability": "nonpayable", "inputs": [{"name": "newAdmin", "type": "address"}], "outputs": []}
+]`)
+
+// StorageReader reads the storage of an account, as implemented by ethclient.Client
+// and the simulated backend
+type StorageReader interface {
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// ProxyInfo is the implementation and admin of a proxy. The admin of a UUPS proxy
+// is the zero address.
+type ProxyInfo struct {
+	Implementation common.Address `json:"implementation"`
+	Admin          common.Address `json:"admin"`
+}
+
+// ParseProxyKind returns the proxy kind with the given name
+func ParseProxyKind(value string) (ProxyKind, error) {
+	kind := ProxyKind(strings.ToLower(value))
+	if _, ok := ProxyArtifacts[kind]; !ok {
+		return "", fmt.Errorf("invalid proxy kind %q, expected %s or %s", value, ProxyUUPS, ProxyTransparent)
+	}
+	return kind, nil
+}
+
+// ReadProxy returns the implementation and admin of the proxy from its ERC-1967 slots
+func ReadProxy(ctx context.Context, backend StorageReader, proxy common.Address) (*ProxyInfo, error) {
+	implementation, err := backend.StorageAt(ctx, proxy, ImplementationSlot, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read implementation slot of %s: %w", proxy.Hex(), err)
+	}
+	admin, err := backend.StorageAt(ctx, proxy, AdminSlot, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admin slot of %s: %w", proxy.Hex(), err)
+	}
+
+	info := &ProxyInfo{
+		Implementation: common.BytesToAddress(implementation),
+		Admin:          common.BytesToAddress(admin),
+	}
+	if info.Implementation == (common.Address{}) {
+		return nil, fmt.Errorf("%w: %s", ErrNotProxy, proxy.Hex())
+	}
+	return info, nil
+}
+
+// DeployProxy deploys a proxy of the given kind from its compiled artifact, in front
+// of the implementation. The admin is only given to transparent proxies, and the
+// initializer call data, which may be empty, is called on the implementation by the
+// proxy's constructor.
+func DeployProxy(auth *bind.TransactOpts, backend bind.ContractBackend, kind ProxyKind, artifact Artifact, implementation, admin common.Address, initData []byte) (common.Address, *types.Transaction, error) {
+	contractABI, err := abi.JSON(bytes.NewReader(artifact.ABI))
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("invalid ABI of %s: %w", artifact.Name, err)
+	}
+
+	args := []interface{}{implementation, initData}
+	if kind == ProxyTransparent {
+		args = []interface{}{implementation, admin, initData}
+	}
+
+	address, tx, _, err := bind.DeployContract(auth, contractABI, common.FromHex(artifact.Bin), backend, args...)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("failed to deploy %s: %w", artifact.Name, err)
+	}
+	return address, tx, nil
+}
+
+// UpgradeProxy sends a transaction upgrading the proxy to the implementation, and
+// calling it with the call data if it is not empty. It is sent by the admin of a
+// transparent proxy, or by an account allowed to upgrade a UUPS implementation.
+func UpgradeProxy(auth *bind.TransactOpts, backend bind.ContractBackend, proxy, implementation common.Address, data []byte) (*types.Transaction, error) {
+	contract := bind.NewBoundContract(proxy, proxyABI, backend, backend, backend)
+	if len(data) == 0 {
+		return contract.Transact(auth, "upgradeTo", implementation)
+	}
+	return contract.Transact(auth, "upgradeToAndCall", implementation, data)
+}
+
+// ChangeProxyAdmin sends a transaction, from the current admin, making admin the
+// admin of the transparent proxy
+func ChangeProxyAdmin(auth *bind.TransactOpts, backend bind.ContractBackend, proxy, admin common.Address) (*types.Transaction, error) {
+	contract := bind.NewBoundContract(proxy, proxyABI, backend, backend, backend)
+	return contract.Transact(auth, "changeAdmin", admin)
+}
+
+// mustParseABI parses an ABI defined in this package
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,237 @@
+/** receipt.go contains helper functions that wait for a transaction to be mined
+  and confirmed, by polling for its receipt or by subscribing to new block headers.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrTxReverted is returned when a mined transaction has a failed receipt status
+var ErrTxReverted = errors.New("transaction reverted")
+
+// WaitMode defines how WaitForReceipt learns about new blocks
+type WaitMode int
+
+const (
+	// WaitPoll polls the node for the receipt on a fixed interval
+	WaitPoll WaitMode = iota
+	// WaitSubscribe checks for the receipt on every new block header from a subscription
+	WaitSubscribe
+)
+
+// String returns the flag name of the wait mode
+func (m WaitMode) String() string {
+	if m == WaitSubscribe {
+		return "subscribe"
+	}
+	return "poll"
+}
+
+// Set parses a wait mode flag value
+func (m *WaitMode) Set(value string) error {
+	switch value {
+	case "poll":
+		*m = WaitPoll
+	case "subscribe":
+		*m = WaitSubscribe
+	default:
+		return fmt.Errorf("invalid wait mode %q, expected poll or subscribe", value)
+	}
+	return nil
+}
+
+// WaitOptions defines how long, how often and how deep to wait for a receipt
+type WaitOptions struct {
+	Mode          WaitMode
+	Timeout       time.Duration
+	PollInterval  time.Duration
+	Confirmations uint64
+}
+
+// DefaultWaitOptions waits up to 2 minutes for a receipt in the latest block,
+// polling every second
+var DefaultWaitOptions = WaitOptions{
+	Mode:          WaitPoll,
+	Timeout:       2 * time.Minute,
+	PollInterval:  time.Second,
+	Confirmations: 1,
+}
+
+// RegisterFlags registers the wait options as flags of the given flag set,
+// using the current values as defaults
+func (o *WaitOptions) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var(&o.Mode, "wait-mode", "how to wait for receipts: poll or subscribe")
+	fs.DurationVar(&o.Timeout, "timeout", o.Timeout, "maximum time to wait for a receipt")
+	fs.DurationVar(&o.PollInterval, "poll-interval", o.PollInterval, "interval between receipt polls")
+	fs.Uint64Var(&o.Confirmations, "confirmations", o.Confirmations, "number of blocks required to confirm a transaction")
+}
+
+// ReceiptBackend defines the node methods required to wait for a receipt by polling
+type ReceiptBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// SubscribeBackend defines the node methods required to wait for a receipt by subscription
+type SubscribeBackend interface {
+	ReceiptBackend
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// GetReceiptBackend returns the backend used to wait for receipts, and a function that
+// closes it once done. In subscribe mode a websocket client is dialled for the network
+// profile, otherwise the given client is used, and is left open.
+func GetReceiptBackend(profile *NetworkProfile, client *ethclient.Client, opts WaitOptions) (ReceiptBackend, func(), error) {
+	if opts.Mode == WaitSubscribe {
+		wsClient, err := GetWSClient(profile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return wsClient, wsClient.Close, nil
+	}
+	return client, func() {}, nil
+}
+
+// WaitForReceipt waits until the transaction with the given hash is mined and has
+// the required number of confirmations, and returns its receipt.
+// If the transaction reverted, the receipt is returned together with ErrTxReverted.
+func WaitForReceipt(ctx context.Context, backend ReceiptBackend, txHash common.Hash, opts WaitOptions) (*types.Receipt, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var (
+		receipt *types.Receipt
+		err     error
+	)
+	switch opts.Mode {
+	case WaitSubscribe:
+		subBackend, ok := backend.(SubscribeBackend)
+		if !ok {
+			return nil, errors.New("backend does not support subscriptions")
+		}
+		receipt, err = waitBySubscription(ctx, subBackend, txHash, opts.Confirmations)
+	default:
+		receipt, err = waitByPolling(ctx, backend, txHash, opts)
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out waiting for transaction %s: %w", txHash.Hex(), err)
+		}
+		return nil, err
+	}
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		return receipt, fmt.Errorf("%w: transaction %s in block %v", ErrTxReverted, txHash.Hex(), receipt.BlockNumber)
+	}
+
+	return receipt, nil
+}
+
+// waitByPolling checks for a confirmed receipt every poll interval
+func waitByPolling(ctx context.Context, backend ReceiptBackend, txHash common.Hash, opts WaitOptions) (*types.Receipt, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultWaitOptions.PollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := confirmedReceipt(ctx, backend, txHash, nil, opts.Confirmations)
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitBySubscription checks for a confirmed receipt on every new block header
+func waitBySubscription(ctx context.Context, backend SubscribeBackend, txHash common.Hash, confirmations uint64) (*types.Receipt, error) {
+	heads := make(chan *types.Header, 16)
+	sub, err := backend.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	// The transaction may already be mined before the subscription started
+	receipt, err := confirmedReceipt(ctx, backend, txHash, nil, confirmations)
+	if err != nil || receipt != nil {
+		return receipt, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-sub.Err():
+			return nil, err
+		case head := <-heads:
+			receipt, err := confirmedReceipt(ctx, backend, txHash, head, confirmations)
+			if err != nil || receipt != nil {
+				return receipt, err
+			}
+		}
+	}
+}
+
+// confirmedReceipt returns the receipt of the transaction if it is mined with the
+// required number of confirmations, or nil if it is not yet confirmed.
+// If head is nil, the latest header is fetched from the backend.
+func confirmedReceipt(ctx context.Context, backend ReceiptBackend, txHash common.Hash, head *types.Header, confirmations uint64) (*types.Receipt, error) {
+	receipt, err := backend.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) || (err == nil && receipt == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if confirmations <= 1 {
+		return receipt, nil
+	}
+
+	if head == nil {
+		head, err = backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The receipt's block counts as the first confirmation
+	depth := new(big.Int).Sub(head.Number, receipt.BlockNumber)
+	if depth.Sign() < 0 || depth.Uint64()+1 < confirmations {
+		return nil, nil
+	}
+
+	return receipt, nil
+}
+
+// ReceiptStatus returns a readable status of the given receipt
+func ReceiptStatus(receipt *types.Receipt) string {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return "success"
+	}
+	return "reverted"
+}
@@ -0,0 +1,79 @@
+/** rest.go contains the GET requests made to the Cosmos SDK REST API of an evmos
+  node, which serves the gRPC queries of every module as JSON.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNoRESTEndpoint is returned when a query has no REST endpoint to query
+var ErrNoRESTEndpoint = errors.New("no REST endpoint")
+
+// ErrRESTUnreachable is returned when the REST endpoint cannot be connected to, such
+// as a node started without its API server
+var ErrRESTUnreachable = errors.New("REST endpoint unreachable")
+
+// RESTError is an error response of the REST API, with its gRPC status code
+type RESTError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *RESTError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("REST query failed: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("REST query failed: %s (code %d)", e.Message, e.Code)
+}
+
+// restGet decodes into out the JSON response of the REST API at restURL, such as
+// http://localhost:1317, to a GET request of the path and query
+func restGet(ctx context.Context, restURL, path string, query url.Values, out interface{}) error {
+	if restURL == "" {
+		return ErrNoRESTEndpoint
+	}
+
+	endpoint := strings.TrimSuffix(restURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrRESTUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		restErr := &RESTError{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(body, restErr)
+		return restErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+/** revert.go contains the decoding of the revert data of failed calls and transactions.
+  Mined transactions carry no revert data in their receipt, so they are replayed with
+  eth_call on the state they ran on. The data is decoded as an Error(string) reason,
+  a Panic(uint256) code, or a custom error of the contract ABIs in contract/build.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// errorSelector is the selector of Error(string), the revert data of require and revert
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	// panicSelector is the selector of Panic(uint256), the revert data of failed
+	// assertions and checked arithmetic
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicCodes describes the codes of Panic(uint256), as listed in the Solidity docs
+var panicCodes = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to invalid internal function",
+}
+
+// RevertError is a reverted call or transaction, with its decoded revert reason.
+// It wraps the error of the call, such as ErrTxReverted.
+type RevertError struct {
+	Reason string
+	Data   []byte
+	Err    error
+}
+
+func (e *RevertError) Error() string {
+	// Nodes include Error(string) reasons in the message of reverted calls
+	if e.Reason == "" || strings.Contains(e.Err.Error(), e.Reason) {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %s", e.Err, e.Reason)
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// LoadBuildABIs reads the ABI of every contract compiled into buildDir
+func LoadBuildABIs(buildDir string) ([]*abi.ABI, error) {
+	paths, err := filepath.Glob(filepath.Join(buildDir, "*.abi"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no ABI files in %s: %w", buildDir, os.ErrNotExist)
+	}
+
+	abis := make([]*abi.ABI, 0, len(paths))
+	for _, path := range paths {
+		contractABI, err := LoadABI(buildDir, path)
+		if err != nil {
+			return nil, err
+		}
+		abis = append(abis, contractABI)
+	}
+	return abis, nil
+}
+
+// DecodeRevertReason decodes revert data as an Error(string) reason, a Panic(uint256)
+// code, or a custom error of one of the ABIs
+func DecodeRevertReason(data []byte, abis []*abi.ABI) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("reverted without a reason")
+	}
+	if len(data) < 4 {
+		return "", fmt.Errorf("invalid revert data %s", hexutil.Encode(data))
+	}
+
+	switch selector := data[:4]; {
+	case bytes.Equal(selector, errorSelector):
+		return abi.UnpackRevert(data)
+	case bytes.Equal(selector, panicSelector):
+		if len(data) != 4+32 {
+			return "", fmt.Errorf("invalid panic data %s", hexutil.Encode(data))
+		}
+		code := new(big.Int).SetBytes(data[4:])
+		if description, ok := panicCodes[code.Uint64()]; ok && code.IsUint64() {
+			return fmt.Sprintf("panic: %s (0x%x)", description, code), nil
+		}
+		return fmt.Sprintf("panic: unknown code 0x%x", code), nil
+	}
+
+	for _, contractABI := range abis {
+		for _, customError := range contractABI.Errors {
+			if !bytes.Equal(data[:4], customError.ID[:4]) {
+				continue
+			}
+			values, err := customError.Inputs.Unpack(data[4:])
+			if err != nil {
+				return "", fmt.Errorf("invalid %s data: %w", customError.Sig, err)
+			}
+			args := make([]string, len(values))
+			for i, value := range FormatValues(customError.Inputs, values) {
+				args[i] = fmt.Sprintf("%s: %v", value.Name, value.Value)
+			}
+			return fmt.Sprintf("%s(%s)", customError.Name, strings.Join(args, ", ")), nil
+		}
+	}
+	return "", fmt.Errorf("unknown revert data %s", hexutil.Encode(data))
+}
+
+// RevertData returns the revert data returned by the node with the error of a call
+// or gas estimate, if any
+func RevertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+
+	switch data := dataErr.ErrorData().(type) {
+	case []byte:
+		return data, true
+	case string:
+		decoded, err := hexutil.Decode(data)
+		return decoded, err == nil
+	}
+	return nil, false
+}
+
+// ExplainRevert returns the error with the decoded reason of its revert data, when the
+// node returned any, as it does for calls and gas estimates of reverting transactions.
+// Other errors are returned as they are.
+func ExplainRevert(err error, abis []*abi.ABI) error {
+	var revertErr *RevertError
+	if err == nil || errors.As(err, &revertErr) {
+		return err
+	}
+	data, ok := RevertData(err)
+	if !ok {
+		return err
+	}
+
+	reason, decodeErr := DecodeRevertReason(data, abis)
+	if decodeErr != nil {
+		reason = decodeErr.Error()
+	}
+	return &RevertError{Reason: reason, Data: data, Err: err}
+}
+
+// ReplayTransaction calls the transaction with eth_call on the state of the block
+// before it was mined, and returns the revert data of the call, which is nil when it
+// does not revert. Nodes that prune old states are called on the latest block instead.
+func ReplayTransaction(ctx context.Context, backend bind.ContractCaller, tx *types.Transaction, receipt *types.Receipt) ([]byte, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender of %s: %w", tx.Hash().Hex(), err)
+	}
+	msg := ethereum.CallMsg{From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data()}
+
+	var block *big.Int
+	if receipt.BlockNumber != nil && receipt.BlockNumber.Sign() > 0 {
+		block = new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	}
+
+	_, err = backend.CallContract(ctx, msg, block)
+	if err != nil && block != nil {
+		if _, ok := replayRevertData(err); !ok {
+			_, err = backend.CallContract(ctx, msg, nil)
+		}
+	}
+	if err == nil {
+		return nil, nil
+	}
+	if data, ok := replayRevertData(err); ok {
+		return data, nil
+	}
+	return nil, fmt.Errorf("failed to replay %s: %w", tx.Hash().Hex(), err)
+}
+
+// replayRevertData returns the revert data of a replayed call. Calls that revert
+// without a reason return no data, only the execution reverted error.
+func replayRevertData(err error) ([]byte, bool) {
+	if data, ok := RevertData(err); ok {
+		return data, true
+	}
+	if strings.Contains(err.Error(), vm.ErrExecutionReverted.Error()) {
+		return []byte{}, true
+	}
+	return nil, false
+}
+
+// ExplainRevertedTx returns the error of a reverted transaction with the reason found
+// by replaying it. A transaction that used all of its gas ran out of gas.
+func ExplainRevertedTx(ctx context.Context, backend bind.ContractCaller, tx *types.Transaction, receipt *types.Receipt, err error, abis []*abi.ABI) error {
+	data, replayErr := ReplayTransaction(ctx, backend, tx, receipt)
+
+	var reason string
+	switch {
+	case receipt.GasUsed >= tx.Gas():
+		reason = "out of gas"
+	case replayErr != nil:
+		reason = replayErr.Error()
+	case data == nil:
+		reason = "no revert reason, the replay of the transaction succeeded"
+	default:
+		var decodeErr error
+		if reason, decodeErr = DecodeRevertReason(data, abis); decodeErr != nil {
+			reason = decodeErr.Error()
+		}
+	}
+	return &RevertError{Reason: reason, Data: data, Err: err}
+}
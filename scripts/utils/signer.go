@@ -0,0 +1,193 @@
+/** signer.go contains the Signer abstraction used to sign transactions, and its
+  backends: go-ethereum keystore files, evmosd keyring directories, and raw hex
+  private keys as an explicitly insecure option.
+*/
+
+package utils
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/term"
+)
+
+// Signer backends selectable with SignerOptions
+const (
+	SignerKeyring     = "keyring"
+	SignerKeystore    = "keystore"
+	SignerInsecureHex = "insecure-hex"
+)
+
+// DefaultKeyEnv is the environment variable read for a raw hex private key
+const DefaultKeyEnv = "EVMOS_PRIVATE_KEY"
+
+// Signer signs transactions on behalf of a single address
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// PassphraseFunc returns the passphrase used to decrypt a key, given a description of the key
+type PassphraseFunc func(description string) (string, error)
+
+// keySigner signs transactions with an in-memory private key
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner returns a Signer for the given private key
+func NewKeySigner(pk *ecdsa.PrivateKey) Signer {
+	return &keySigner{key: pk, address: crypto.PubkeyToAddress(pk.PublicKey)}
+}
+
+// Address returns the address of the signer's private key
+func (s *keySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs the transaction with the signer's private key for the given chain
+func (s *keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// NewInsecureHexSigner returns a Signer for a raw hex private key.
+// Raw keys are exposed in shell history and process lists, so prefer a keystore or keyring.
+func NewInsecureHexSigner(hexkey string) (Signer, error) {
+	privateKey, _, err := GetPKAndAddress(strings.TrimPrefix(strings.TrimSpace(hexkey), "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(privateKey), nil
+}
+
+// NewKeystoreSigner returns a Signer for a go-ethereum keystore JSON file,
+// decrypted with the passphrase returned by the passphrase function
+func NewKeystoreSigner(path string, passphrase PassphraseFunc) (Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pass, err := passphrase("keystore " + path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(data, pass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// PromptPassphrase reads a passphrase from the terminal without echoing it
+func PromptPassphrase(description string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("a passphrase is required for %s, but stdin is not a terminal", description)
+	}
+
+	fmt.Fprintf(os.Stderr, "Passphrase for %s: ", description)
+	pass, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pass), nil
+}
+
+// PassphraseFromFile returns a PassphraseFunc that reads the passphrase from the
+// first line of the given file
+func PassphraseFromFile(path string) PassphraseFunc {
+	return func(string) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r"), nil
+	}
+}
+
+// SignerOptions defines which Signer backend to load, and where from
+type SignerOptions struct {
+	Backend        string
+	From           string
+	KeyringHome    string
+	KeyringBackend string
+	Keystore       string
+	PassphraseFile string
+	KeyEnv         string
+}
+
+// DefaultSignerOptions signs with the "mykey" key of the evmosd test keyring
+// created by evmos/init.sh
+var DefaultSignerOptions = SignerOptions{
+	Backend:        SignerKeyring,
+	From:           "mykey",
+	KeyringHome:    defaultKeyringHome(),
+	KeyringBackend: KeyringBackendTest,
+	KeyEnv:         DefaultKeyEnv,
+}
+
+// defaultKeyringHome returns the default evmosd home directory
+func defaultKeyringHome() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".evmosd"
+	}
+	return filepath.Join(home, ".evmosd")
+}
+
+// RegisterFlags registers the signer options as flags of the given flag set,
+// using the current values as defaults
+func (o *SignerOptions) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Backend, "signer", o.Backend, "signer backend: keyring, keystore or insecure-hex")
+	fs.StringVar(&o.From, "from", o.From, "name of the key in the evmosd keyring")
+	fs.StringVar(&o.KeyringHome, "keyring-home", o.KeyringHome, "evmosd home directory containing the keyring")
+	fs.StringVar(&o.KeyringBackend, "keyring-backend", o.KeyringBackend, "evmosd keyring backend: test or file")
+	fs.StringVar(&o.Keystore, "keystore", o.Keystore, "path to a go-ethereum keystore JSON file")
+	fs.StringVar(&o.PassphraseFile, "passphrase-file", o.PassphraseFile, "file containing the keystore or keyring passphrase, instead of a prompt")
+	fs.StringVar(&o.KeyEnv, "key-env", o.KeyEnv, "environment variable containing a raw hex private key, for the insecure-hex signer")
+}
+
+// NewSigner loads the Signer selected by the options
+func (o *SignerOptions) NewSigner() (Signer, error) {
+	passphrase := PassphraseFunc(PromptPassphrase)
+	if o.PassphraseFile != "" {
+		passphrase = PassphraseFromFile(o.PassphraseFile)
+	}
+
+	switch o.Backend {
+	case SignerKeyring:
+		if o.From == "" {
+			return nil, errors.New("a key name is required for the keyring signer")
+		}
+		return NewKeyringSigner(o.KeyringHome, o.KeyringBackend, o.From, passphrase)
+	case SignerKeystore:
+		if o.Keystore == "" {
+			return nil, errors.New("a keystore file is required for the keystore signer")
+		}
+		return NewKeystoreSigner(o.Keystore, passphrase)
+	case SignerInsecureHex:
+		hexkey := os.Getenv(o.KeyEnv)
+		if hexkey == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", o.KeyEnv)
+		}
+		return NewInsecureHexSigner(hexkey)
+	default:
+		return nil, fmt.Errorf("invalid signer %q, expected keyring, keystore or insecure-hex", o.Backend)
+	}
+}
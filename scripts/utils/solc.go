@@ -0,0 +1,501 @@
+/** solc.go contains the compile-and-bind pipeline of the contracts. Sources are
+  compiled with solc in standard JSON mode, the ABI and bytecode of every contract
+  are written to contract/build, and Go bindings are generated with go-ethereum's
+  bind package. The compiler version, settings and source hashes are recorded in a
+  build info file, which is used to check that the committed bindings, artifacts
+  and sources still agree.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// BuildInfoVersion is the version of the build info format written by this package
+	BuildInfoVersion = 1
+
+	// DefaultBuildInfoFile is the name of the build info file in the build directory
+	DefaultBuildInfoFile = "build-info.json"
+)
+
+// OptimizerSettings are the solc optimizer settings
+type OptimizerSettings struct {
+	Enabled bool `json:"enabled"`
+	Runs    int  `json:"runs"`
+}
+
+// CompilerSettings are the solc settings that change the compiled bytecode
+type CompilerSettings struct {
+	Optimizer  OptimizerSettings `json:"optimizer"`
+	EVMVersion string            `json:"evmVersion,omitempty"`
+}
+
+// DefaultCompilerSettings are the settings of solc when run without options
+var DefaultCompilerSettings = CompilerSettings{Optimizer: OptimizerSettings{Enabled: false, Runs: 200}}
+
+// BindingConfig selects the contract bound to Go, and where its binding is written
+type BindingConfig struct {
+	Contract string `json:"contract"`
+	Package  string `json:"package"`
+	Out      string `json:"out"`
+}
+
+// CompilerInfo is the version and settings of the compiler of a build
+type CompilerInfo struct {
+	Version  string           `json:"version"`
+	Settings CompilerSettings `json:"settings"`
+}
+
+// SourceInfo is a compiled source file
+type SourceInfo struct {
+	Keccak256 common.Hash `json:"keccak256"`
+}
+
+// ImmutableReference is the position of an immutable variable in the runtime bytecode
+// of a contract, which is only filled in by its constructor
+type ImmutableReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// ArtifactInfo records the artifacts of a compiled contract
+type ArtifactInfo struct {
+	Source              string               `json:"source"`
+	ABIHash             common.Hash          `json:"abi_hash"`
+	BinHash             common.Hash          `json:"bin_hash"`
+	ImmutableReferences []ImmutableReference `json:"immutable_references,omitempty"`
+}
+
+// BuildInfo records how the artifacts in the build directory were compiled
+type BuildInfo struct {
+	Version   int                     `json:"version"`
+	Compiler  CompilerInfo            `json:"compiler"`
+	Sources   map[string]SourceInfo   `json:"sources"`
+	Contracts map[string]ArtifactInfo `json:"contracts"`
+	Binding   BindingConfig           `json:"binding"`
+}
+
+// Artifact is the ABI and bytecode of a compiled contract
+type Artifact struct {
+	Name   string
+	Source string
+	ABI    []byte
+	Bin    string
+
+	// ImmutableReferences are the positions of immutable variables in the runtime
+	// bytecode, sorted by start
+	ImmutableReferences []ImmutableReference
+
+	// StorageLayout is the solc storage layout of the contract's state variables
+	StorageLayout json.RawMessage
+}
+
+// solcInput is the standard JSON input of solc
+type solcInput struct {
+	Language string                     `json:"language"`
+	Sources  map[string]solcInputSource `json:"sources"`
+	Settings solcInputSettings          `json:"settings"`
+}
+
+type solcInputSource struct {
+	Content string `json:"content"`
+}
+
+type solcInputSettings struct {
+	Optimizer       OptimizerSettings              `json:"optimizer"`
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+// SolcOutput is the standard JSON output of solc
+type SolcOutput struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		FormattedMessage string `json:"formattedMessage"`
+	} `json:"errors"`
+	Contracts map[string]map[string]struct {
+		ABI           json.RawMessage `json:"abi"`
+		Metadata      string          `json:"metadata"`
+		StorageLayout json.RawMessage `json:"storageLayout"`
+		EVM           struct {
+			Bytecode struct {
+				Object string `json:"object"`
+			} `json:"bytecode"`
+			DeployedBytecode struct {
+				ImmutableReferences map[string][]ImmutableReference `json:"immutableReferences"`
+			} `json:"deployedBytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+// solcMetadata is the part of a contract's metadata recording its compilation
+type solcMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Sources map[string]struct {
+		Keccak256 common.Hash `json:"keccak256"`
+	} `json:"sources"`
+}
+
+// NewSolcInput returns the standard JSON input compiling the source files with the
+// settings. Imports are resolved by solc, relative to the working directory.
+func NewSolcInput(sources []string, settings CompilerSettings) ([]byte, error) {
+	input := solcInput{
+		Language: "Solidity",
+		Sources:  map[string]solcInputSource{},
+		Settings: solcInputSettings{
+			Optimizer:  settings.Optimizer,
+			EVMVersion: settings.EVMVersion,
+			OutputSelection: map[string]map[string][]string{
+				"*": {"*": {"abi", "evm.bytecode.object", "evm.deployedBytecode.immutableReferences", "metadata", "storageLayout"}},
+			},
+		},
+	}
+
+	for _, source := range sources {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		input.Sources[filepath.ToSlash(source)] = solcInputSource{Content: string(content)}
+	}
+
+	return json.Marshal(input)
+}
+
+// RunSolc runs the solc binary in standard JSON mode, and returns its output
+func RunSolc(ctx context.Context, solc string, input []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, solc, "--standard-json", "--allow-paths", ".")
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w: %s", solc, err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// ParseSolcOutput parses the standard JSON output of solc. Compilation errors are
+// returned as an error, while warnings are ignored.
+func ParseSolcOutput(data []byte) (*SolcOutput, error) {
+	output := new(SolcOutput)
+	if err := json.Unmarshal(data, output); err != nil {
+		return nil, fmt.Errorf("invalid solc output: %w", err)
+	}
+
+	var problems []string
+	for _, e := range output.Errors {
+		if e.Severity == "error" {
+			problems = append(problems, strings.TrimSpace(e.FormattedMessage))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("solc failed to compile:\n%s", strings.Join(problems, "\n"))
+	}
+
+	if len(output.Contracts) == 0 {
+		return nil, errors.New("solc output has no contracts")
+	}
+
+	return output, nil
+}
+
+// Artifacts returns the ABI and bytecode of every compiled contract, sorted by name
+func (o *SolcOutput) Artifacts() ([]Artifact, error) {
+	var artifacts []Artifact
+	seen := map[string]string{}
+
+	for source, contracts := range o.Contracts {
+		for name, contract := range contracts {
+			if other, ok := seen[name]; ok {
+				return nil, fmt.Errorf("contract %s is defined in both %s and %s", name, other, source)
+			}
+			seen[name] = source
+
+			var abiJSON bytes.Buffer
+			if err := json.Compact(&abiJSON, contract.ABI); err != nil {
+				return nil, fmt.Errorf("invalid ABI of %s: %w", name, err)
+			}
+			// References are grouped by the AST id of their variable
+			var references []ImmutableReference
+			for _, refs := range contract.EVM.DeployedBytecode.ImmutableReferences {
+				references = append(references, refs...)
+			}
+			sort.Slice(references, func(i, j int) bool { return references[i].Start < references[j].Start })
+
+			artifacts = append(artifacts, Artifact{
+				Name:                name,
+				Source:              source,
+				ABI:                 abiJSON.Bytes(),
+				Bin:                 strings.TrimPrefix(contract.EVM.Bytecode.Object, "0x"),
+				ImmutableReferences: references,
+				StorageLayout:       contract.StorageLayout,
+			})
+		}
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, nil
+}
+
+// NewBuildInfo returns the build info of the solc output, with the compiler version
+// and the hashes of every source, including imports, read from the contract metadata
+func NewBuildInfo(output *SolcOutput, settings CompilerSettings, binding BindingConfig) (*BuildInfo, []Artifact, error) {
+	artifacts, err := output.Artifacts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &BuildInfo{
+		Version:   BuildInfoVersion,
+		Compiler:  CompilerInfo{Settings: settings},
+		Sources:   map[string]SourceInfo{},
+		Contracts: map[string]ArtifactInfo{},
+		Binding:   binding,
+	}
+
+	for _, artifact := range artifacts {
+		metadata := new(solcMetadata)
+		if err := json.Unmarshal([]byte(output.Contracts[artifact.Source][artifact.Name].Metadata), metadata); err != nil {
+			return nil, nil, fmt.Errorf("invalid metadata of %s: %w", artifact.Name, err)
+		}
+		info.Compiler.Version = metadata.Compiler.Version
+		for source, hash := range metadata.Sources {
+			info.Sources[source] = SourceInfo{Keccak256: hash.Keccak256}
+		}
+
+		info.Contracts[artifact.Name] = ArtifactInfo{
+			Source:              artifact.Source,
+			ABIHash:             crypto.Keccak256Hash(artifact.ABI),
+			BinHash:             crypto.Keccak256Hash([]byte(artifact.Bin)),
+			ImmutableReferences: artifact.ImmutableReferences,
+		}
+	}
+
+	if _, ok := info.Contracts[binding.Contract]; !ok {
+		return nil, nil, fmt.Errorf("no contract %s to bind in the solc output", binding.Contract)
+	}
+
+	return info, artifacts, nil
+}
+
+// WriteArtifacts writes the <name>.abi and <name>.bin files of the artifacts to the
+// build directory, and the <name>.storage.json file of those with a storage layout
+func WriteArtifacts(buildDir string, artifacts []Artifact) error {
+	if err := os.MkdirAll(buildDir, 0o755); err != nil {
+		return err
+	}
+
+	for _, artifact := range artifacts {
+		if err := os.WriteFile(filepath.Join(buildDir, artifact.Name+".abi"), artifact.ABI, 0o644); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(buildDir, artifact.Name+".bin"), []byte(artifact.Bin), 0o644); err != nil {
+			return err
+		}
+		if len(artifact.StorageLayout) > 0 {
+			var layout bytes.Buffer
+			if err := json.Indent(&layout, artifact.StorageLayout, "", "  "); err != nil {
+				return fmt.Errorf("invalid storage layout of %s: %w", artifact.Name, err)
+			}
+			if err := os.WriteFile(filepath.Join(buildDir, artifact.Name+StorageLayoutExt), layout.Bytes(), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadBuildInfo reads and returns the build info file at the given path
+func LoadBuildInfo(path string) (*BuildInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(BuildInfo)
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("invalid build info %s: %w", path, err)
+	}
+
+	if info.Version > BuildInfoVersion {
+		return nil, fmt.Errorf("build info %s has version %d, newer than the supported version %d", path, info.Version, BuildInfoVersion)
+	}
+
+	return info, nil
+}
+
+// Save writes the build info to the given path
+func (b *BuildInfo) Save(path string) error {
+	return writeJSONFile(path, b)
+}
+
+// GenerateBinding returns the Go binding of a contract's ABI and bytecode, as
+// generated by abigen
+func GenerateBinding(abiJSON []byte, bin string, binding BindingConfig) (string, error) {
+	code, err := bind.Bind([]string{binding.Contract}, []string{string(abiJSON)}, []string{bin}, nil, binding.Package, bind.LangGo, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate binding of %s: %w", binding.Contract, err)
+	}
+	return code, nil
+}
+
+// ReadArtifact reads the ABI and bytecode files of the named contract from the
+// build directory
+func ReadArtifact(buildDir, name string) (Artifact, error) {
+	abiJSON, err := os.ReadFile(filepath.Join(buildDir, name+".abi"))
+	if err != nil {
+		return Artifact{}, err
+	}
+	bin, err := os.ReadFile(filepath.Join(buildDir, name+".bin"))
+	if err != nil {
+		return Artifact{}, err
+	}
+	return Artifact{Name: name, ABI: abiJSON, Bin: strings.TrimSpace(string(bin))}, nil
+}
+
+// CheckBuild checks that the sources, artifacts and binding in the root directory
+// agree with the build info. Every disagreement is returned in a single error.
+// Sources under node_modules that are not installed are skipped.
+func CheckBuild(root, buildDir string, info *BuildInfo) error {
+	var problems []string
+
+	sources := make([]string, 0, len(info.Sources))
+	for source := range info.Sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(source)))
+		if errors.Is(err, os.ErrNotExist) && strings.HasPrefix(source, "node_modules/") {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("source %s: %v", source, err))
+			continue
+		}
+		if crypto.Keccak256Hash(content) != info.Sources[source].Keccak256 {
+			problems = append(problems, fmt.Sprintf("source %s has changed since the artifacts were compiled", source))
+		}
+	}
+
+	names := make([]string, 0, len(info.Contracts))
+	for name := range info.Contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		artifact, err := ReadArtifact(filepath.Join(root, buildDir), name)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("artifact %s: %v", name, err))
+			continue
+		}
+		if crypto.Keccak256Hash(artifact.ABI) != info.Contracts[name].ABIHash {
+			problems = append(problems, fmt.Sprintf("%s.abi does not match the build info", name))
+		}
+		if crypto.Keccak256Hash([]byte(artifact.Bin)) != info.Contracts[name].BinHash {
+			problems = append(problems, fmt.Sprintf("%s.bin does not match the build info", name))
+		}
+		if version, err := BytecodeCompilerVersion(artifact.Bin); err == nil && !strings.HasPrefix(info.Compiler.Version, version) {
+			problems = append(problems, fmt.Sprintf("%s.bin was compiled by solc %s, not %s", name, version, info.Compiler.Version))
+		}
+	}
+
+	if err := checkBinding(root, buildDir, info.Binding); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("build is out of date:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// checkBinding checks that the committed binding is generated from the artifacts
+func checkBinding(root, buildDir string, binding BindingConfig) error {
+	artifact, err := ReadArtifact(filepath.Join(root, buildDir), binding.Contract)
+	if err != nil {
+		return fmt.Errorf("binding %s: %w", binding.Out, err)
+	}
+
+	expected, err := GenerateBinding(artifact.ABI, artifact.Bin, binding)
+	if err != nil {
+		return err
+	}
+
+	committed, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(binding.Out)))
+	if err != nil {
+		return fmt.Errorf("binding %s: %w", binding.Out, err)
+	}
+	if string(committed) != expected {
+		return fmt.Errorf("binding %s is not generated from %s.abi and %s.bin", binding.Out, binding.Contract, binding.Contract)
+	}
+	return nil
+}
+
+// CompareArtifacts checks that the committed artifacts in the build directory are
+// the same as freshly compiled artifacts
+func CompareArtifacts(buildDir string, artifacts []Artifact) error {
+	var problems []string
+	for _, artifact := range artifacts {
+		committed, err := ReadArtifact(buildDir, artifact.Name)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("artifact %s: %v", artifact.Name, err))
+			continue
+		}
+		if !bytes.Equal(committed.ABI, artifact.ABI) {
+			problems = append(problems, fmt.Sprintf("%s.abi differs from the compiled ABI", artifact.Name))
+		}
+		if committed.Bin != artifact.Bin {
+			problems = append(problems, fmt.Sprintf("%s.bin differs from the compiled bytecode", artifact.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("committed artifacts differ from the sources:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// BytecodeCompilerVersion returns the solc version recorded in the CBOR metadata at the
+// end of a contract's hex bytecode, such as "0.8.6"
+func BytecodeCompilerVersion(bin string) (string, error) {
+	code := common.FromHex(bin)
+	if len(code) < 2 {
+		return "", errors.New("bytecode has no metadata")
+	}
+
+	// The last 2 bytes are the length of the CBOR encoded metadata before them
+	length := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	if length+2 > len(code) {
+		return "", errors.New("bytecode has no metadata")
+	}
+	metadata := code[len(code)-2-length : len(code)-2]
+
+	// The version is encoded as the key "solc" followed by 3 bytes
+	key := append([]byte{0x64}, "solc"...)
+	i := bytes.Index(metadata, append(key, 0x43))
+	if i < 0 || i+len(key)+4 > len(metadata) {
+		return "", errors.New("bytecode metadata has no solc version")
+	}
+	v := metadata[i+len(key)+1 : i+len(key)+4]
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2]), nil
+}
@@ -0,0 +1,111 @@
+/** storage_layout.go contains the storage layout compatibility check run before a
+  proxy is upgraded. The solc storage layouts of the old and new implementations are
+  compared, as the proxy keeps the state of the old implementation, and a variable
+  that moves or changes type would read the state of another.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StorageLayoutExt is the extension of the storage layout files in the build directory
+const StorageLayoutExt = ".storage.json"
+
+// StorageVariable is a state variable of a solc storage layout
+type StorageVariable struct {
+	Label    string `json:"label"`
+	Contract string `json:"contract"`
+	Slot     string `json:"slot"`
+	Offset   int    `json:"offset"`
+	Type     string `json:"type"`
+}
+
+// StorageType is a type of a solc storage layout
+type StorageType struct {
+	Encoding      string `json:"encoding"`
+	Label         string `json:"label"`
+	NumberOfBytes string `json:"numberOfBytes"`
+}
+
+// StorageLayout is the solc storage layout of a contract, as written by bindgen
+type StorageLayout struct {
+	Storage []StorageVariable      `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+// LoadStorageLayout reads the storage layout of a contract, given either as the path
+// of a .json file, or as the name of a contract compiled into buildDir
+func LoadStorageLayout(buildDir, contract string) (*StorageLayout, error) {
+	path := contract
+	if !strings.HasSuffix(contract, ".json") {
+		if buildDir == "" {
+			buildDir = DefaultBuildDir
+		}
+		path = filepath.Join(buildDir, contract+StorageLayoutExt)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && path != contract {
+		return nil, fmt.Errorf("no storage layout for contract %q in %s, run go run ./scripts/bindgen: %w", contract, buildDir, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	layout := new(StorageLayout)
+	if err := json.Unmarshal(data, layout); err != nil {
+		return nil, fmt.Errorf("invalid storage layout %s: %w", path, err)
+	}
+	return layout, nil
+}
+
+// CheckStorageUpgrade checks that an implementation with the new storage layout can
+// replace one with the old layout behind a proxy. Every old variable must keep its
+// name, slot, offset and type, in the same order, and new variables may only be
+// added after them. Every problem is returned in a single error.
+func CheckStorageUpgrade(old, upgraded *StorageLayout) error {
+	var problems []string
+
+	for i, variable := range old.Storage {
+		if i >= len(upgraded.Storage) {
+			problems = append(problems, fmt.Sprintf("%s at slot %s was removed", variable.Label, variable.Slot))
+			continue
+		}
+		replacement := upgraded.Storage[i]
+
+		if replacement.Slot != variable.Slot || replacement.Offset != variable.Offset {
+			problems = append(problems, fmt.Sprintf("%s moved from slot %s offset %d to slot %s offset %d", variable.Label, variable.Slot, variable.Offset, replacement.Slot, replacement.Offset))
+			continue
+		}
+		if replacement.Label != variable.Label {
+			problems = append(problems, fmt.Sprintf("%s at slot %s was replaced by %s", variable.Label, variable.Slot, replacement.Label))
+		}
+
+		oldType, newType := old.typeOf(variable), upgraded.typeOf(replacement)
+		if oldType.Label != newType.Label || oldType.NumberOfBytes != newType.NumberOfBytes {
+			problems = append(problems, fmt.Sprintf("%s at slot %s changed type from %s to %s", variable.Label, variable.Slot, oldType.Label, newType.Label))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("storage layouts are incompatible:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// typeOf returns the type of a variable of the layout. Type identifiers hold AST ids,
+// which differ between compilations, so types are compared by their label and size.
+func (l *StorageLayout) typeOf(variable StorageVariable) StorageType {
+	t, ok := l.Types[variable.Type]
+	if !ok {
+		return StorageType{Label: variable.Type}
+	}
+	return t
+}
@@ -0,0 +1,93 @@
+/** token.go contains the constructor parameters of the Token contract, and their
+  validation before the contract is deployed.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// MaxTokenDecimals is the largest number of decimals of a token of which one whole
+// token still fits in a uint256
+const MaxTokenDecimals = 77
+
+// TokenParams are the constructor parameters of the Token contract
+type TokenParams struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	// InitialSupply is the minted supply in base units
+	InitialSupply *big.Int `json:"initial_supply"`
+	// Holders are the accounts the initial supply is split between, the deployer if
+	// there are none
+	Holders []common.Address `json:"holders,omitempty"`
+}
+
+// DefaultTokenParams are the parameters of the original Token contract, 100 TOK with
+// 18 decimals
+var DefaultTokenParams = TokenParams{
+	Name:          "Token",
+	Symbol:        "TOK",
+	Decimals:      18,
+	InitialSupply: new(big.Int).Mul(big.NewInt(100), math.BigPow(10, 18)),
+}
+
+// Validate checks that the parameters can be deployed, and that every holder is
+// minted a share of the initial supply
+func (p TokenParams) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("empty token name")
+	}
+	if strings.TrimSpace(p.Symbol) == "" || strings.ContainsAny(p.Symbol, " \t\n") {
+		return fmt.Errorf("invalid token symbol %q, expected a symbol without spaces such as TOK", p.Symbol)
+	}
+	if p.Decimals > MaxTokenDecimals {
+		return fmt.Errorf("invalid decimals %d, at most %d are supported", p.Decimals, MaxTokenDecimals)
+	}
+
+	if p.InitialSupply == nil || p.InitialSupply.Sign() <= 0 {
+		return errors.New("the initial supply must be positive")
+	}
+	if p.InitialSupply.Cmp(math.MaxBig256) > 0 {
+		return fmt.Errorf("initial supply %s does not fit in a uint256", p.InitialSupply)
+	}
+
+	seen := make(map[common.Address]bool, len(p.Holders))
+	for _, holder := range p.Holders {
+		if holder == (common.Address{}) {
+			return errors.New("the zero address cannot be an initial holder")
+		}
+		if seen[holder] {
+			return fmt.Errorf("initial holder %s is given more than once", holder.Hex())
+		}
+		seen[holder] = true
+	}
+	if p.InitialSupply.Cmp(big.NewInt(int64(len(p.Holders)))) < 0 {
+		return fmt.Errorf("initial supply of %s base units cannot be split between %d holders", p.InitialSupply, len(p.Holders))
+	}
+
+	return nil
+}
+
+// Shares returns the amount minted to each holder by the Token constructor, in the
+// order of the holders. The remainder of an uneven split goes to the first holder.
+func (p TokenParams) Shares() []*big.Int {
+	if len(p.Holders) == 0 {
+		return nil
+	}
+
+	share, remainder := new(big.Int).QuoRem(p.InitialSupply, big.NewInt(int64(len(p.Holders))), new(big.Int))
+	shares := make([]*big.Int, len(p.Holders))
+	for i := range shares {
+		shares[i] = new(big.Int).Set(share)
+	}
+	shares[0].Add(shares[0], remainder)
+	return shares
+}
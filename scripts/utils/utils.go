@@ -7,23 +7,54 @@ package utils
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
-	"math"
+	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// GetClient retrieves and returns an ethClient for the local evmos node
-func GetClient() (*ethclient.Client, error) {
-	client, err := ethclient.Dial("http://localhost:8545")
+// GetClient retrieves and returns an ethClient for the node of the given network profile.
+// The connection is refused if the node's chain ID does not match the profile.
+func GetClient(profile *NetworkProfile) (*ethclient.Client, error) {
+	client, err := ethclient.Dial(profile.HTTP)
 	if err != nil {
 		return nil, err
 	}
-	return client, err
+
+	if err := profile.CheckChainID(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// GetWSClient retrieves and returns a websocket ethClient for the node of the given
+// network profile, which can be used for subscriptions
+func GetWSClient(profile *NetworkProfile) (*ethclient.Client, error) {
+	if profile.WS == "" {
+		return nil, fmt.Errorf("network profile %q has no websocket endpoint", profile.Name)
+	}
+
+	client, err := ethclient.Dial(profile.WS)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := profile.CheckChainID(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
 }
 
 // GetPKAndAddress derives and returns an ecsda private key and associated address,
@@ -44,9 +75,11 @@ func GetPKAndAddress(hexkey string) (*ecdsa.PrivateKey, common.Address, error) {
 	return privateKey, address, nil
 }
 
-// GetAuth derives the transaction options for a given primary key and associated address,
-// with a default set of values
-func GetAuth(client *ethclient.Client, pk *ecdsa.PrivateKey, address common.Address) (*bind.TransactOpts, error) {
+// GetAuth derives the transaction options for a given signer, with fees chosen by the
+// fee options. The gas limit is left at 0, so that it is estimated for each call;
+// bind contracts with NewGasEstimator to apply the fee options' gas multiplier.
+func GetAuth(client AuthBackend, signer Signer, fees FeeOptions) (*bind.TransactOpts, error) {
+	address := signer.Address()
 
 	// Check for invalid address
 	if address == common.HexToAddress("0x0") {
@@ -58,34 +91,59 @@ func GetAuth(client *ethclient.Client, pk *ecdsa.PrivateKey, address common.Addr
 		return nil, err
 	}
 
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	chainID, err := client.ChainID(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	chainID, err := client.ChainID(context.Background())
-	if err != nil {
+	auth := NewTransactOpts(signer, chainID)
+	auth.Nonce = big.NewInt(int64(nonce))
+	auth.Value = big.NewInt(0)
+
+	if err := fees.applyFees(context.Background(), client, auth); err != nil {
 		return nil, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(pk, chainID)
+	return auth, nil
+}
+
+// NewTransactOpts returns transaction options that sign with the given signer,
+// for the given chain ID
+func NewTransactOpts(signer Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx, chainID)
+		},
+		Context: context.Background(),
+	}
+}
+
+// writeJSONFile writes the value as indented JSON to the file at path. The file is
+// written to a temporary file and renamed, so that an interrupted write never leaves
+// a partial file behind.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(3000000)
-	auth.GasPrice = gasPrice
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
 
-	return auth, nil
-}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
 
-// GetReadableBalance takes the ERC20 contract balance of an account, and reformatts the balance with the correct
-// decimal places from the contract's decimals variable
-func GetReadableBalance(balance *big.Int, decimals uint8) *big.Float {
-	bal := new(big.Float)
-	bal.SetString(balance.String())
-	value := new(big.Float).Quo(bal, big.NewFloat(math.Pow10(int(decimals))))
-	return value
+	return os.Rename(tmp.Name(), path)
 }
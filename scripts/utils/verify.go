@@ -0,0 +1,181 @@
+/** verify.go contains the helpers that check the code deployed at an address was
+  compiled from a local build artifact. The expected runtime bytecode is derived by
+  running the artifact's creation code in an in-memory EVM, and compared with the
+  deployed code, ignoring the solc metadata hash at its end and the immutable
+  variables filled in by the constructor.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VerifyStatus is the outcome of comparing deployed code with a build artifact
+type VerifyStatus string
+
+const (
+	// VerifyMatch is deployed code identical to the artifact, apart from immutables
+	VerifyMatch VerifyStatus = "match"
+
+	// VerifyMetadataOnly is deployed code that only differs from the artifact in its
+	// metadata hash, such as code compiled from a source with changed comments
+	VerifyMetadataOnly VerifyStatus = "metadata-only difference"
+
+	// VerifyMismatch is deployed code that was not compiled from the artifact
+	VerifyMismatch VerifyStatus = "mismatch"
+)
+
+// ErrNoCode is returned when there is no contract code at the verified address
+var ErrNoCode = errors.New("no contract code at address")
+
+// CodeReader reads the code of an account, as implemented by ethclient.Client and
+// the simulated backend
+type CodeReader interface {
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// VerifyResult is the comparison of deployed code with the runtime bytecode of a
+// build artifact
+type VerifyResult struct {
+	Status           VerifyStatus `json:"status"`
+	ExpectedSize     int          `json:"expected_size"`
+	ActualSize       int          `json:"actual_size"`
+	ExpectedHash     common.Hash  `json:"expected_hash"`
+	ActualHash       common.Hash  `json:"actual_hash"`
+	ExpectedMetadata string       `json:"expected_metadata,omitempty"`
+	ActualMetadata   string       `json:"actual_metadata,omitempty"`
+	// FirstDifference is the offset of the first differing byte of a mismatch, or -1
+	FirstDifference int `json:"first_difference"`
+}
+
+// DeployedBytecode runs the creation code, including any ABI encoded constructor
+// arguments, in an in-memory EVM as sent by the deployer, and returns the runtime
+// bytecode it deploys
+func DeployedBytecode(creation []byte, deployer common.Address) ([]byte, error) {
+	if len(creation) == 0 {
+		return nil, errors.New("creation code is empty")
+	}
+
+	code, _, _, err := runtime.Create(creation, &runtime.Config{Origin: deployer})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run creation code: %w", err)
+	}
+	return code, nil
+}
+
+// SplitMetadata splits runtime bytecode into its code and the CBOR encoded solc
+// metadata at its end, including the 2 length bytes. Bytecode without metadata is
+// returned whole, with nil metadata.
+func SplitMetadata(code []byte) ([]byte, []byte) {
+	if len(code) < 2 {
+		return code, nil
+	}
+
+	length := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - length
+	// The metadata is a CBOR map, whose first byte is 0xa0 to 0xb7
+	if length == 0 || start < 0 || code[start] < 0xa0 || code[start] > 0xb7 {
+		return code, nil
+	}
+	return code[:start], code[start:]
+}
+
+// CompareBytecode compares the expected runtime bytecode of an artifact with the
+// deployed code. The bytes of the immutable references are ignored, as they hold
+// values set by the constructor, such as the deployer or deployment time.
+func CompareBytecode(expected, actual []byte, immutables []ImmutableReference) VerifyResult {
+	result := VerifyResult{
+		Status:          VerifyMatch,
+		ExpectedSize:    len(expected),
+		ActualSize:      len(actual),
+		ExpectedHash:    crypto.Keccak256Hash(expected),
+		ActualHash:      crypto.Keccak256Hash(actual),
+		FirstDifference: -1,
+	}
+
+	expectedCode, expectedMetadata := SplitMetadata(maskImmutables(expected, immutables))
+	actualCode, actualMetadata := SplitMetadata(maskImmutables(actual, immutables))
+	if expectedMetadata != nil {
+		result.ExpectedMetadata = common.Bytes2Hex(expectedMetadata)
+	}
+	if actualMetadata != nil {
+		result.ActualMetadata = common.Bytes2Hex(actualMetadata)
+	}
+
+	switch {
+	case !bytes.Equal(expectedCode, actualCode):
+		result.Status = VerifyMismatch
+		result.FirstDifference = firstDifference(expectedCode, actualCode)
+	case !bytes.Equal(expectedMetadata, actualMetadata):
+		result.Status = VerifyMetadataOnly
+	}
+	return result
+}
+
+// VerifyCode compares the code deployed at the address with the runtime bytecode
+// that the artifact's creation code, followed by the constructor arguments, deploys
+func VerifyCode(ctx context.Context, backend CodeReader, address common.Address, artifact Artifact, constructorArgs []byte, deployer common.Address) (*VerifyResult, error) {
+	creation := common.FromHex(artifact.Bin)
+	if len(creation) == 0 {
+		return nil, fmt.Errorf("%s has no bytecode, it may be an interface or abstract contract", artifact.Name)
+	}
+
+	expected, err := DeployedBytecode(append(creation, constructorArgs...), deployer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive runtime bytecode of %s: %w", artifact.Name, err)
+	}
+
+	actual, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code at %s: %w", address.Hex(), err)
+	}
+	if len(actual) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrNoCode, address.Hex())
+	}
+
+	result := CompareBytecode(expected, actual, artifact.ImmutableReferences)
+	return &result, nil
+}
+
+// ConstructorArgs returns the ABI encoded constructor arguments of a deployment
+// transaction's input, which is the creation code followed by its arguments
+func ConstructorArgs(creation, input []byte) ([]byte, error) {
+	if !bytes.HasPrefix(input, creation) {
+		return nil, errors.New("deployment input does not start with the creation code of the artifact")
+	}
+	return input[len(creation):], nil
+}
+
+// maskImmutables returns a copy of the code, with the bytes of the immutable
+// references set to zero
+func maskImmutables(code []byte, immutables []ImmutableReference) []byte {
+	masked := common.CopyBytes(code)
+	for _, ref := range immutables {
+		for i := ref.Start; i < ref.Start+ref.Length && i < len(masked); i++ {
+			masked[i] = 0
+		}
+	}
+	return masked
+}
+
+// firstDifference returns the offset of the first byte that differs between a and b
+func firstDifference(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
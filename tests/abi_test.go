@@ -0,0 +1,251 @@
+/** abi_test.go contains TDD ( Test Driven Development ) style tests for the
+  ABI driven method calls in scripts/utils/abi.go.
+*/
+
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// buildDir is the directory of the compiled contracts, relative to the tests
+const buildDir = "../contract/build"
+
+// typesABI has a method taking every supported argument type
+const typesABI = `[{
+	"type": "function",
+	"name": "types",
+	"stateMutability": "nonpayable",
+	"inputs": [
+		{"name": "small", "type": "uint8"},
+		{"name": "signed", "type": "int16"},
+		{"name": "big", "type": "uint256"},
+		{"name": "flag", "type": "bool"},
+		{"name": "data", "type": "bytes"},
+		{"name": "id", "type": "bytes32"},
+		{"name": "label", "type": "string"},
+		{"name": "accounts", "type": "address[]"},
+		{"name": "pair", "type": "uint256[2]"},
+		{"name": "payment", "type": "tuple", "components": [
+			{"name": "to", "type": "address"},
+			{"name": "amount", "type": "uint256"}
+		]},
+		{"name": "tag", "type": "bytes4"},
+		{"name": "levels", "type": "uint8[3]"}
+	],
+	"outputs": []
+}]`
+
+// Test ParseArgs
+// Checks that command line values are converted to the Go values of each ABI type,
+// and that values outside of their type are rejected
+func TestParseArgs(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(typesABI))
+	require.NoError(t, err, "Error parsing ABI")
+	inputs := contractABI.Methods["types"].Inputs
+
+	valid := []string{
+		"255",
+		"-32768",
+		"0x" + strings.Repeat("ff", 32),
+		"true",
+		"0x0102",
+		"0x" + strings.Repeat("ab", 32),
+		"hello",
+		`["0x0000000000000000000000000000000000000001","0x0000000000000000000000000000000000000002"]`,
+		`[1,"0x02"]`,
+		`{"to":"0x0000000000000000000000000000000000000003","amount":"1000000000000000000000"}`,
+		"0x01020304",
+		"[1,2,255]",
+	}
+
+	testcases := []struct {
+		name   string
+		index  int
+		value  string
+		expErr bool
+	}{
+		{"Valid values", 0, "", false},
+		{"Tuple as array", 9, `["0x0000000000000000000000000000000000000003",5]`, false},
+		{"Uint overflow", 0, "256", true},
+		{"Uint256 overflow", 2, "0x10000000000000000000000000000000000000000000000000000000000000000", true},
+		{"Negative uint", 2, "-1", true},
+		{"Int underflow", 1, "-32769", true},
+		{"Leading zero is not octal", 0, "010", false},
+		{"Invalid bool", 3, "yes", true},
+		{"Bytes without 0x", 4, "0102", true},
+		{"Short fixed bytes", 5, "0x01", true},
+		{"Invalid address", 7, `["0x123"]`, true},
+		{"Wrong array length", 8, "[1]", true},
+		{"Missing tuple component", 9, `{"to":"0x0000000000000000000000000000000000000003"}`, true},
+		{"Invalid JSON", 8, "1,2", true},
+		{"Uint8 array overflow", 11, "[1,2,256]", true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := append([]string{}, valid...)
+			if tc.value != "" {
+				values[tc.index] = tc.value
+			}
+
+			args, err := util.ParseArgs(inputs, values)
+			if tc.expErr {
+				require.Error(t, err, "ParseArgs should raise an error")
+				return
+			}
+			require.NoError(t, err, "Error during ParseArgs")
+
+			// The parsed values are packed by the ABI, and decode to the same values
+			packed, err := inputs.Pack(args...)
+			require.NoError(t, err, "Parsed values should pack")
+			unpacked, err := inputs.Unpack(packed)
+			require.NoError(t, err, "Error unpacking values")
+			require.Equal(t, util.FormatValues(inputs, args), util.FormatValues(inputs, unpacked), "Values should round trip")
+		})
+	}
+
+	args, err := util.ParseArgs(inputs, valid)
+	require.NoError(t, err, "Error during ParseArgs")
+	formatted := util.FormatValues(inputs, args)
+	require.Equal(t, "255", formatted[0].Value, "Incorrect uint8")
+	require.Equal(t, "-32768", formatted[1].Value, "Incorrect int16")
+	require.Equal(t, "0x0102", formatted[4].Value, "Incorrect bytes")
+	require.Equal(t, "0x"+strings.Repeat("ab", 32), formatted[5].Value, "Incorrect bytes32")
+	require.Equal(t, []interface{}{"1", "2"}, formatted[8].Value, "Incorrect array")
+	require.Equal(t, map[string]interface{}{
+		"to":     "0x0000000000000000000000000000000000000003",
+		"amount": "1000000000000000000000",
+	}, formatted[9].Value, "Incorrect tuple")
+	require.Equal(t, "0x01020304", formatted[10].Value, "Incorrect bytes4")
+	require.Equal(t, []interface{}{"1", "2", "255"}, formatted[11].Value, "A uint8 array should be formatted by element, not as bytes")
+
+	_, err = util.ParseArgs(inputs, valid[:3])
+	require.Error(t, err, "ParseArgs should reject a wrong number of arguments")
+}
+
+// overloadedABI has two transfer methods, and a transferFrom method
+const overloadedABI = `[
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": []},
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}, {"name": "data", "type": "bytes"}], "outputs": []},
+	{"type": "function", "name": "transferFrom", "stateMutability": "nonpayable", "inputs": [{"name": "from", "type": "address"}, {"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": []}
+]`
+
+// Test FindMethod
+// Checks that methods are found by name or signature, and that overloaded methods
+// must be given by signature
+func TestFindMethod(t *testing.T) {
+	contractABI, err := util.LoadABI(buildDir, "IERC20")
+	require.NoError(t, err, "Error loading ABI")
+
+	method, err := util.FindMethod(contractABI, "transfer")
+	require.NoError(t, err, "Error finding method by name")
+	require.Equal(t, "transfer(address,uint256)", method.Sig, "Incorrect method")
+
+	method, err = util.FindMethod(contractABI, "approve(address,uint256)")
+	require.NoError(t, err, "Error finding method by signature")
+	require.Equal(t, "approve", method.Name, "Incorrect method")
+
+	_, err = util.FindMethod(contractABI, "mint")
+	require.Error(t, err, "FindMethod should reject an unknown method")
+
+	_, err = util.LoadABI(buildDir, "Missing")
+	require.Error(t, err, "LoadABI should reject an unknown contract")
+
+	overloaded, err := abi.JSON(strings.NewReader(overloadedABI))
+	require.NoError(t, err, "Error parsing overloaded ABI")
+
+	testcases := []struct {
+		name   string
+		method string
+		expSig string
+		expErr string
+	}{
+		{"Overloaded name", "transfer", "", "is overloaded, give one of its signatures: transfer(address,uint256), transfer(address,uint256,bytes)"},
+		{"Overload signature", "transfer(address,uint256,bytes)", "transfer(address,uint256,bytes)", ""},
+		{"go-ethereum overload name", "transfer0", "transfer(address,uint256,bytes)", ""},
+		{"Name with an overloaded prefix", "transferFrom", "transferFrom(address,address,uint256)", ""},
+		{"Unknown method", "transfer2", "", "no method"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			method, err := util.FindMethod(&overloaded, tc.method)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr, "Incorrect FindMethod error")
+				return
+			}
+			require.NoError(t, err, "Error during FindMethod")
+			require.Equal(t, tc.expSig, method.Sig, "Incorrect method")
+		})
+	}
+}
+
+// Test CallMethod and DecodeLogs
+// Checks that a method is called and sent by name with an ABI from contract/build,
+// and that its return values and events are decoded
+func TestCallMethod(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(2)
+	require.NoError(t, err, "Error generating private keys")
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock*10, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	contractAddress, _, _, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+
+	contractABI, err := util.LoadABI(buildDir, "ERC20")
+	require.NoError(t, err, "Error loading ABI")
+	ctx := context.Background()
+
+	balanceOf, err := util.FindMethod(contractABI, "balanceOf")
+	require.NoError(t, err, "Error finding balanceOf")
+	args, err := util.ParseArgs(balanceOf.Inputs, []string{addresses[0].Hex()})
+	require.NoError(t, err, "Error during ParseArgs")
+	outputs, err := util.CallMethod(ctx, client, contractAddress, contractABI, balanceOf, args)
+	require.NoError(t, err, "Error during CallMethod")
+	require.Len(t, outputs, 1, "Incorrect number of outputs")
+	require.Equal(t, "100000000000000000000", outputs[0].Value, "Incorrect balance")
+	require.Equal(t, "uint256", outputs[0].Type, "Incorrect output type")
+
+	transfer, err := util.FindMethod(contractABI, "transfer")
+	require.NoError(t, err, "Error finding transfer")
+	args, err = util.ParseArgs(transfer.Inputs, []string{addresses[1].Hex(), "25"})
+	require.NoError(t, err, "Error during ParseArgs")
+
+	contract := bind.NewBoundContract(contractAddress, *contractABI, client, client, client)
+	tx, err := contract.Transact(auth, transfer.Name, args...)
+	require.NoError(t, err, "Error sending transfer")
+	client.Commit()
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err, "Error getting receipt")
+	events, err := util.DecodeLogs(contractABI, contractAddress, receipt.Logs)
+	require.NoError(t, err, "Error during DecodeLogs")
+	require.Len(t, events, 1, "Incorrect number of events")
+	require.Equal(t, "Transfer", events[0].Name, "Incorrect event")
+	require.Equal(t, []util.NamedValue{
+		{Name: "from", Type: "address", Value: addresses[0].Hex()},
+		{Name: "to", Type: "address", Value: addresses[1].Hex()},
+		{Name: "value", Type: "uint256", Value: "25"},
+	}, events[0].Args, "Incorrect event arguments")
+
+	outputs, err = util.CallMethod(ctx, client, contractAddress, contractABI, balanceOf, []interface{}{addresses[1]})
+	require.NoError(t, err, "Error during CallMethod")
+	require.Equal(t, "25", outputs[0].Value, "Incorrect recipient balance")
+
+	// Logs of other contracts are skipped
+	events, err = util.DecodeLogs(contractABI, addresses[0], receipt.Logs)
+	require.NoError(t, err, "Error during DecodeLogs")
+	require.Empty(t, events, "Logs of other contracts should be skipped")
+}
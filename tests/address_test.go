@@ -0,0 +1,75 @@
+/** address_test.go contains TDD ( Test Driven Development ) style tests for the
+  hex and bech32 address conversion in scripts/utils/address.go.
+*/
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// Test AddressToBech32
+// Checks that addresses are converted to evmos1 bech32, and back to the same address
+func TestAddressToBech32(t *testing.T) {
+	testcases := []struct {
+		name    string
+		address common.Address
+		bech32  string
+	}{
+		{"Evmos documentation address", common.HexToAddress("0x14574a6DFF2Ddf9e07828b4345d3040919AF5652"), "evmos1z3t55m0l9h0eupuz3dp5t5cypyv674jj7mz2jw"},
+		{"Zero address", common.Address{}, "evmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq3z33a4"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bech32 := util.AddressToBech32(tc.address)
+			require.Equal(t, tc.bech32, bech32, "Incorrect bech32 address")
+
+			address, err := util.Bech32ToAddress(bech32)
+			require.NoError(t, err, "Error during Bech32ToAddress")
+			require.Equal(t, tc.address, address, "Incorrect address from bech32")
+		})
+	}
+}
+
+// Test ParseAddress
+// Checks that addresses are accepted in both formats, and that invalid bech32
+// addresses are rejected
+func TestParseAddress(t *testing.T) {
+	address := common.HexToAddress("0x14574a6DFF2Ddf9e07828b4345d3040919AF5652")
+
+	testcases := []struct {
+		name    string
+		value   string
+		isValid bool
+	}{
+		{"Hex", "0x14574a6DFF2Ddf9e07828b4345d3040919AF5652", true},
+		{"Hex without prefix", "14574a6dff2ddf9e07828b4345d3040919af5652", true},
+		{"Bech32", "evmos1z3t55m0l9h0eupuz3dp5t5cypyv674jj7mz2jw", true},
+		{"Upper case bech32", "EVMOS1Z3T55M0L9H0EUPUZ3DP5T5CYPYV674JJ7MZ2JW", true},
+		{"Mixed case bech32", "evmos1Z3t55m0l9h0eupuz3dp5t5cypyv674jj7mz2jw", false},
+		{"Invalid checksum", "evmos1z3t55m0l9h0eupuz3dp5t5cypyv674jj7mz2jx", false},
+		{"Invalid character", "evmos1z3t55m0l9h0eupuz3dp5t5cypyv674jj7mz2jb", false},
+		{"Other prefix", "cosmos1z3t55m0l9h0eupuz3dp5t5cypyv674jju6nygx", false},
+		{"Short hex", "0x1234", false},
+		{"Empty address", "", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := util.ParseAddress(tc.value)
+			require.Equal(t, tc.isValid, util.IsAddress(tc.value), "Incorrect IsAddress")
+			if !tc.isValid {
+				require.Error(t, err, "Invalid inputs should return an error")
+				return
+			}
+			require.NoError(t, err, "Error during ParseAddress")
+			require.Equal(t, address, parsed, "Incorrect address")
+		})
+	}
+}
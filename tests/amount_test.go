@@ -0,0 +1,122 @@
+/** amount_test.go contains TDD ( Test Driven Development ) style tests for the
+  exact token amount conversions in scripts/utils/amount.go.
+*/
+
+package tests
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// bigFromString returns the big integer of a decimal string
+func bigFromString(t *testing.T, s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok, "Invalid big integer %s", s)
+	return v
+}
+
+// Test FormatAmount
+// Checks that base unit amounts are formatted exactly, without trailing zeros
+func TestFormatAmount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		amount   string
+		decimals uint8
+		expected string
+	}{
+		{"Whole amount", "10000000000000000000", 18, "10"},
+		{"Fractional amount", "10500000000000000000", 18, "10.5"},
+		{"Smallest unit", "1", 18, "0.000000000000000001"},
+		{"Zero", "0", 18, "0"},
+		{"No decimals", "12345", 0, "12345"},
+		{"Negative amount", "-1500", 3, "-1.5"},
+		// 2^256 - 1 cannot be represented exactly by a float64
+		{
+			"Max uint256",
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935",
+			18,
+			"115792089237316195423570985008687907853269984665640564039457.584007913129639935",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, util.FormatAmount(bigFromString(t, tc.amount), tc.decimals), "Incorrect formatted amount")
+		})
+	}
+
+	require.Equal(t, "10.5 TOK", util.FormatTokenAmount(bigFromString(t, "10500000000000000000"), 18, "TOK"), "Incorrect token amount")
+}
+
+// errAnyAmount is expected from ParseAmount inputs that may fail with any error
+var errAnyAmount = errors.New("any error")
+
+// Test ParseAmount
+// Checks that decimal strings are parsed exactly, and that amounts more precise than
+// the token are rejected
+func TestParseAmount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		value    string
+		decimals uint8
+		expErr   error
+		expected string
+	}{
+		{"Whole amount", "10", 18, nil, "10000000000000000000"},
+		{"Fractional amount", "10.5", 18, nil, "10500000000000000000"},
+		{"Leading point", ".5", 2, nil, "50"},
+		{"Trailing point", "7.", 2, nil, "700"},
+		{"Smallest unit", "0.000000000000000001", 18, nil, "1"},
+		{"Trailing zeros beyond decimals", "1.5000", 2, nil, "150"},
+		{"Surrounding space", " 3.25 ", 2, nil, "325"},
+		{"Too many decimal places", "1.005", 2, util.ErrTooManyDecimals, ""},
+		{"Fraction with no decimals", "1.5", 0, util.ErrTooManyDecimals, ""},
+		{"Empty", "", 18, errAnyAmount, ""},
+		{"Negative", "-1", 18, errAnyAmount, ""},
+		{"Exponent", "1e18", 18, errAnyAmount, ""},
+		{"Only a point", ".", 18, errAnyAmount, ""},
+		{"Two points", "1.2.3", 18, errAnyAmount, ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := util.ParseAmount(tc.value, tc.decimals)
+			if tc.expErr != nil {
+				require.Error(t, err, "ParseAmount should raise an error")
+				if tc.expErr != errAnyAmount {
+					require.ErrorIs(t, err, tc.expErr)
+				}
+				return
+			}
+			require.NoError(t, err, "Error during ParseAmount")
+			require.Equal(t, bigFromString(t, tc.expected), amount, "Incorrect parsed amount")
+		})
+	}
+}
+
+// Test ParseTokenAmount
+// Checks that an optional symbol must match the token symbol, and that formatted
+// amounts parse back to the same value
+func TestParseTokenAmount(t *testing.T) {
+	amount, err := util.ParseTokenAmount("10.5 TOK", 18, "TOK")
+	require.NoError(t, err, "Error parsing amount with symbol")
+	require.Equal(t, bigFromString(t, "10500000000000000000"), amount, "Incorrect parsed amount")
+
+	amount, err = util.ParseTokenAmount("10.5", 18, "TOK")
+	require.NoError(t, err, "Error parsing amount without symbol")
+	require.Equal(t, bigFromString(t, "10500000000000000000"), amount, "Incorrect parsed amount")
+
+	_, err = util.ParseTokenAmount("10.5 ETH", 18, "TOK")
+	require.Error(t, err, "ParseTokenAmount should reject another symbol")
+
+	max := bigFromString(t, "115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	parsed, err := util.ParseTokenAmount(util.FormatTokenAmount(max, 18, "TOK"), 18, "TOK")
+	require.NoError(t, err, "Error parsing formatted amount")
+	require.Equal(t, max, parsed, "Formatted amount should parse to the same value")
+}
@@ -0,0 +1,80 @@
+/** bank_test.go contains TDD ( Test Driven Development ) style tests for the bank
+  balance query in scripts/utils/bank.go, against a fake REST API.
+*/
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// Test GetBankBalance
+// Checks that the aevmos balance of an account is read from the bank module's REST
+// query, by its bech32 address, and that API and connection errors are returned
+func TestGetBankBalance(t *testing.T) {
+	account := common.HexToAddress("0x14574a6DFF2Ddf9e07828b4345d3040919AF5652")
+	balances := map[string]string{
+		util.AddressToBech32(account): "1500000000000000000",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var bech32 string
+		if _, err := fmt.Sscanf(r.URL.Path, "/cosmos/bank/v1beta1/balances/%s", &bech32); err != nil || r.URL.Query().Get("denom") != util.NativeDenom {
+			w.WriteHeader(http.StatusNotImplemented)
+			fmt.Fprint(w, `{"code":12,"message":"Not Implemented"}`)
+			return
+		}
+		bech32 = bech32[:len(bech32)-len("/by_denom")]
+		if _, err := util.Bech32ToAddress(bech32); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"code":3,"message":"invalid address: %s"}`, err)
+			return
+		}
+		fmt.Fprintf(w, `{"balance":{"denom":"aevmos","amount":"%s"}}`, balances[bech32])
+	}))
+	defer server.Close()
+
+	amount, _ := new(big.Int).SetString("1500000000000000000", 10)
+
+	testcases := []struct {
+		name    string
+		restURL string
+		account common.Address
+		denom   string
+		balance *big.Int
+		isValid bool
+	}{
+		{"Funded account", server.URL, account, util.NativeDenom, amount, true},
+		{"Trailing slash", server.URL + "/", account, util.NativeDenom, amount, true},
+		{"Empty account", server.URL, common.HexToAddress("0x01"), util.NativeDenom, new(big.Int), true},
+		{"API error", server.URL, account, "uatom", nil, false},
+		{"No REST endpoint", "", account, util.NativeDenom, nil, false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			balance, err := util.GetBankBalance(context.Background(), tc.restURL, tc.account, tc.denom)
+			if !tc.isValid {
+				require.Error(t, err, "Invalid inputs should return an error")
+				return
+			}
+			require.NoError(t, err, "Error during GetBankBalance")
+			require.Equal(t, 0, tc.balance.Cmp(balance), "Incorrect balance %v", balance)
+		})
+	}
+	// A node without its API server refuses the connection
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err := util.GetBankBalance(context.Background(), closed.URL, account, util.NativeDenom)
+	require.ErrorIs(t, err, util.ErrRESTUnreachable, "Refused connections should be reported as unreachable")
+}
@@ -0,0 +1,251 @@
+/** batch_test.go contains TDD ( Test Driven Development ) style tests for the
+  batch transfer CSV files and checkpoints in scripts/utils/batch.go, using a
+  simulated backend.
+*/
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// writeTempFile writes the contents to a file in a temporary directory, and returns its path
+func writeTempFile(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600), "Error writing %s", name)
+	return path
+}
+
+// Test ReadTransferCSV
+// Checks that every row is validated before any row is returned
+func TestReadTransferCSV(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	testcases := []struct {
+		name    string
+		csv     string
+		expErr  bool
+		expRows int
+	}{
+		{
+			"Valid rows with header",
+			"address,amount\n0x0000000000000000000000000000000000000001,10\n0x0000000000000000000000000000000000000002, 20.25\n",
+			false,
+			2,
+		},
+		{
+			"Valid rows with comments and no header",
+			"# airdrop\n0x0000000000000000000000000000000000000001,10\n",
+			false,
+			1,
+		},
+		{
+			"Invalid address",
+			"0x0000000000000000000000000000000000000001,10\n0x123,10\n",
+			true,
+			0,
+		},
+		{
+			"Too many decimal places",
+			"0x0000000000000000000000000000000000000001,1.505\n",
+			true,
+			0,
+		},
+		{
+			"Invalid amount",
+			"0x0000000000000000000000000000000000000001,1e18\n",
+			true,
+			0,
+		},
+		{
+			"Zero amount",
+			"0x0000000000000000000000000000000000000001,0\n",
+			true,
+			0,
+		},
+		{
+			"Duplicate recipient",
+			"0x0000000000000000000000000000000000000001,10\n0x0000000000000000000000000000000000000001,10\n",
+			true,
+			0,
+		},
+		{
+			"Sender as recipient",
+			"0x00000000000000000000000000000000000000aa,10\n",
+			true,
+			0,
+		},
+		{
+			"Wrong number of fields",
+			"0x0000000000000000000000000000000000000001,10,extra\n",
+			true,
+			0,
+		},
+		{
+			"No rows",
+			"address,amount\n",
+			true,
+			0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTempFile(t, "transfers.csv", tc.csv)
+			rows, input, err := util.ReadTransferCSV(path, sender, 2)
+			if tc.expErr {
+				require.Error(t, err, "ReadTransferCSV should raise an error")
+				return
+			}
+			require.NoError(t, err, "Error during ReadTransferCSV")
+			require.Len(t, rows, tc.expRows, "Incorrect number of rows")
+			require.Len(t, input, 64, "Input should be a sha256 hex digest")
+		})
+	}
+}
+
+// Test BatchCheckpoint
+// Checks that a batch is sent, confirmed and reconciled, and that a resumed batch
+// only resends transfers that were never mined
+func TestBatchCheckpoint(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(4)
+	require.NoError(t, err, "Error generating private keys")
+	sender, recipients := addresses[0], addresses[1:]
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock*10, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	contractAddress, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+
+	rows := make([]util.TransferRow, len(recipients))
+	for i, recipient := range recipients {
+		rows[i] = util.TransferRow{Line: i + 1, To: recipient, Amount: big.NewInt(int64(100 * (i + 1)))}
+	}
+
+	balanceOf := func(account common.Address) (*big.Int, error) {
+		return contract.BalanceOf(&bind.CallOpts{}, account)
+	}
+	transfer := func(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+		return contract.Transfer(opts, to, amount)
+	}
+	wait := util.WaitOptions{Mode: util.WaitPoll, Timeout: time.Second, PollInterval: 10 * time.Millisecond, Confirmations: 1}
+	path := filepath.Join(t.TempDir(), "transfers.csv.checkpoint.json")
+	ctx := context.Background()
+
+	checkpoint, err := util.NewBatchCheckpoint("simulated", contractAddress, sender, "input", rows, balanceOf)
+	require.NoError(t, err, "Error during NewBatchCheckpoint")
+	require.Equal(t, big.NewInt(600), checkpoint.Unsent(), "Incorrect unsent total")
+
+	// Send the batch, and have the node drop every transfer before it is mined
+	require.NoError(t, checkpoint.Send(ctx, util.NewNonceManager(client), auth, transfer, path), "Error during Send")
+	require.Equal(t, len(rows), checkpoint.Count(util.BatchSent), "Every row should be sent")
+	client.Rollback()
+
+	// Dropped transfers are unsent when the batch is resumed
+	require.NoError(t, checkpoint.Resume(ctx, client, path), "Error during Resume")
+	require.Equal(t, len(rows), checkpoint.Count(util.BatchUnsent), "Dropped transfers should be unsent")
+	require.Equal(t, big.NewInt(600), checkpoint.Unsent(), "Dropped transfers should be sent again")
+
+	// Send the batch again with pipelined nonces, and mine it in a single block
+	require.NoError(t, checkpoint.Send(ctx, util.NewNonceManager(client), auth, transfer, path), "Error during Send")
+	client.Commit()
+
+	// An interrupted run is resumed from the saved checkpoint
+	checkpoint, err = util.LoadBatchCheckpoint(path)
+	require.NoError(t, err, "Error during LoadBatchCheckpoint")
+	require.NoError(t, checkpoint.Matches("simulated", contractAddress, sender, "input"), "Checkpoint should match its batch")
+	require.ErrorIs(t, checkpoint.Matches("simulated", contractAddress, sender, "changed"), util.ErrCheckpointMismatch)
+	require.ErrorIs(t, checkpoint.Matches("simulated", contractAddress, recipients[0], "input"), util.ErrCheckpointMismatch)
+
+	// Mined transfers are not sent again
+	require.NoError(t, checkpoint.Resume(ctx, client, path), "Error during Resume")
+	require.Equal(t, len(rows), checkpoint.Count(util.BatchSuccess), "Mined transfers should succeed")
+	require.Equal(t, 0, checkpoint.Unsent().Sign(), "No transfers should be unsent")
+	require.NoError(t, checkpoint.Send(ctx, util.NewNonceManager(client), auth, transfer, path), "Error during Send")
+
+	// Sent transfers are confirmed from their receipts
+	checkpoint.Rows[0].Status = util.BatchSent
+	require.NoError(t, checkpoint.Confirm(ctx, client, wait, path), "Error during Confirm")
+	require.Equal(t, util.BatchSuccess, checkpoint.Rows[0].Status, "Confirmed transfer should succeed")
+
+	results, err := checkpoint.Reconcile(balanceOf)
+	require.NoError(t, err, "Error during Reconcile")
+	for i, result := range results {
+		require.True(t, result.Match, "Balance of line %d should reconcile", result.Line)
+		require.Equal(t, rows[i].Amount, result.Actual, "Each transfer should be received once")
+	}
+
+	_, err = util.LoadBatchCheckpoint(filepath.Join(t.TempDir(), "missing.json"))
+	require.True(t, errors.Is(err, os.ErrNotExist), "Missing checkpoint should wrap os.ErrNotExist")
+}
+
+// Test BatchCheckpoint Explain
+// Checks that reverted transfers are replayed for their revert reason, and that the
+// reason is cleared when the transfer is sent again
+func TestBatchCheckpointExplain(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(3)
+	require.NoError(t, err, "Error generating private keys")
+	sender, recipients := addresses[0], addresses[1:]
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock*10, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	contractAddress, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+	supply, err := contract.TotalSupply(&bind.CallOpts{})
+	require.NoError(t, err, "Error getting total supply")
+
+	rows := []util.TransferRow{
+		{Line: 1, To: recipients[0], Amount: big.NewInt(100)},
+		{Line: 2, To: recipients[1], Amount: new(big.Int).Add(supply, big.NewInt(1))},
+	}
+	balanceOf := func(account common.Address) (*big.Int, error) {
+		return contract.BalanceOf(&bind.CallOpts{}, account)
+	}
+	// A fixed gas limit skips the gas estimate, so the transfer of more than the
+	// supply is mined and reverts
+	transfer := func(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+		opts.GasLimit = 100000
+		return contract.Transfer(opts, to, amount)
+	}
+	wait := util.WaitOptions{Mode: util.WaitPoll, Timeout: time.Second, PollInterval: 10 * time.Millisecond, Confirmations: 1}
+	path := filepath.Join(t.TempDir(), "transfers.csv.checkpoint.json")
+	ctx := context.Background()
+
+	checkpoint, err := util.NewBatchCheckpoint("simulated", contractAddress, sender, "input", rows, balanceOf)
+	require.NoError(t, err, "Error during NewBatchCheckpoint")
+	require.NoError(t, checkpoint.Send(ctx, util.NewNonceManager(client), auth, transfer, path), "Error during Send")
+	client.Commit()
+	require.NoError(t, checkpoint.Confirm(ctx, client, wait, path), "Error during Confirm")
+	require.Equal(t, util.BatchSuccess, checkpoint.Rows[0].Status, "Transfer within the balance should succeed")
+	require.Equal(t, util.BatchReverted, checkpoint.Rows[1].Status, "Transfer of more than the balance should revert")
+
+	require.NoError(t, checkpoint.Explain(ctx, client, nil, path), "Error during Explain")
+	require.Empty(t, checkpoint.Rows[0].Reason, "Successful transfers have no reason")
+	require.Equal(t, "ERC20: transfer amount exceeds balance", checkpoint.Rows[1].Reason, "Incorrect revert reason")
+
+	saved, err := util.LoadBatchCheckpoint(path)
+	require.NoError(t, err, "Error during LoadBatchCheckpoint")
+	require.Equal(t, checkpoint.Rows[1].Reason, saved.Rows[1].Reason, "The reason should be saved")
+
+	require.NoError(t, checkpoint.Send(ctx, util.NewNonceManager(client), auth, transfer, path), "Error during Send")
+	require.Equal(t, util.BatchSent, checkpoint.Rows[1].Status, "Reverted transfers should be sent again")
+	require.Empty(t, checkpoint.Rows[1].Reason, "The reason of the previous attempt should be cleared")
+}
@@ -0,0 +1,171 @@
+/** create2_test.go contains TDD ( Test Driven Development ) style tests for the
+  CREATE2 deployments in scripts/utils/create2.go.
+*/
+
+package tests
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Test ParseSalt
+// Checks that salts are parsed from hex, and hashed from text
+func TestParseSalt(t *testing.T) {
+	testcases := []struct {
+		name    string
+		value   string
+		salt    common.Hash
+		isValid bool
+	}{
+		{"Short hex", "0x1", common.HexToHash("0x01"), true},
+		{"Full hex", "0x" + strings.Repeat("ab", 32), common.HexToHash("0x" + strings.Repeat("ab", 32)), true},
+		{"Text", "token-v1", crypto.Keccak256Hash([]byte("token-v1")), true},
+		{"Empty salt", "", common.Hash{}, false},
+		{"Invalid hex", "0xzz", common.Hash{}, false},
+		{"Hex longer than 32 bytes", "0x" + strings.Repeat("ab", 33), common.Hash{}, false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			salt, err := util.ParseSalt(tc.value)
+			if !tc.isValid {
+				require.Error(t, err, "Invalid inputs should return an error")
+				return
+			}
+			require.NoError(t, err, "Error during ParseSalt")
+			require.Equal(t, tc.salt, salt, "Incorrect salt")
+		})
+	}
+}
+
+// Test Create2Deploy
+// Checks that contracts are deployed through the factory at the predicted address,
+// whoever sends the deployment, and that a second deployment is rejected
+func TestCreate2Deploy(t *testing.T) {
+	h, err := testUtil.NewHarness("deployer", "other")
+	require.NoError(t, err, "Error starting harness")
+	defer h.Close()
+	ctx := context.Background()
+
+	factory, tx, err := util.DeployCreate2Factory(h.Auth("deployer"), h.Backend)
+	_, err = h.Commit(tx, err)
+	require.NoError(t, err, "Error deploying factory")
+	require.NoError(t, util.CheckCreate2Factory(ctx, h.Backend, factory), "Deployed factory should be recognised")
+
+	tokenAddress, _, err := testUtil.Deploy(h, "deployer", testUtil.DeployToken)
+	require.NoError(t, err, "Error deploying Token")
+	require.ErrorIs(t, util.CheckCreate2Factory(ctx, h.Backend, tokenAddress), util.ErrNotCreate2Factory, "Other contracts are not the factory")
+	require.ErrorIs(t, util.CheckCreate2Factory(ctx, h.Backend, h.Address("other")), util.ErrNoCode, "Accounts are not the factory")
+
+	// The ERC20 artifact, with its constructor arguments
+	artifact, err := util.ReadArtifact(buildDir, "ERC20")
+	require.NoError(t, err, "Error reading ERC20 artifact")
+	erc20ABI, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	require.NoError(t, err, "Error parsing ERC20 ABI")
+	args, err := erc20ABI.Pack("", "Other", "OTH")
+	require.NoError(t, err, "Error packing constructor arguments")
+	creation := append(common.FromHex(artifact.Bin), args...)
+
+	salt, err := util.ParseSalt("token-v1")
+	require.NoError(t, err, "Error parsing salt")
+	predicted := util.PredictCreate2Address(factory, salt, creation)
+	snapshot := h.Snapshot()
+
+	for _, sender := range []string{"deployer", "other"} {
+		require.NoError(t, h.Revert(snapshot), "Error reverting snapshot")
+
+		_, err = h.Commit(util.Create2Deploy(h.Auth(sender), h.Backend, factory, salt, creation))
+		require.NoError(t, err, "Error deploying through factory from %s", sender)
+
+		result, err := util.VerifyCode(ctx, h.Backend, predicted, artifact, args, factory)
+		require.NoError(t, err, "Error verifying code at predicted address")
+		require.Equal(t, util.VerifyMatch, result.Status, "Predicted address should hold the deployed contract")
+	}
+
+	// The address is taken, so CREATE2 fails and the factory reverts
+	auth := h.Auth("deployer")
+	auth.GasLimit = testUtil.MaxGasPerBlock
+	_, err = h.Commit(util.Create2Deploy(auth, h.Backend, factory, salt, creation))
+	require.ErrorIs(t, err, util.ErrTxReverted, "Second deployment with the same salt should revert")
+
+	// Another salt gives another address
+	other, err := util.ParseSalt("token-v2")
+	require.NoError(t, err, "Error parsing salt")
+	require.NotEqual(t, predicted, util.PredictCreate2Address(factory, other, creation), "Salts should give different addresses")
+}
+
+// create2Result is the JSON output of a CREATE2 deployment by tokencli deploy
+type create2Result struct {
+	Address string            `json:"address"`
+	Factory string            `json:"factory"`
+	Salt    string            `json:"salt"`
+	Skipped bool              `json:"skipped"`
+	Token   *util.TokenParams `json:"token"`
+}
+
+// Test deploy -salt
+// Checks that tokencli deploys Token through the CREATE2 factory at the address
+// predicted from its creation code, minting the supply to the given holder rather
+// than to the factory, and that deploying it again is skipped
+func TestDeployTokenCreate2(t *testing.T) {
+	h, profile := startLocalNode(t)
+	cli, err := testUtil.NewTokencli(t.TempDir(), h, profile)
+	require.NoError(t, err, "Error building tokencli")
+	ctx := context.Background()
+
+	args := []string{"-salt", "token-v1", "-supply", "250", "-holders", h.Address("alice").Hex()}
+	var result create2Result
+	require.NoError(t, cli.Run("deployer", &result, "deploy", args...), "Error deploying Token with -salt")
+	require.False(t, result.Skipped, "First deployment should not be skipped")
+
+	// The address is predicted from the creation code with the explicit holder
+	params := util.DefaultTokenParams
+	params.InitialSupply = new(big.Int).Mul(big.NewInt(250), testUtil.Ten18)
+	params.Holders = []common.Address{h.Address("alice")}
+	require.Equal(t, &params, result.Token, "Incorrect Token parameters")
+	tokenABI, err := token.TokenMetaData.GetAbi()
+	require.NoError(t, err, "Error parsing Token ABI")
+	packed, err := tokenABI.Pack("", params.Name, params.Symbol, params.Decimals, params.InitialSupply, params.Holders)
+	require.NoError(t, err, "Error packing constructor arguments")
+	creation := append(common.FromHex(token.TokenMetaData.Bin), packed...)
+	salt, err := util.ParseSalt("token-v1")
+	require.NoError(t, err, "Error parsing salt")
+	factory := common.HexToAddress(result.Factory)
+	require.NoError(t, util.CheckCreate2Factory(ctx, h.Backend, factory), "Factory should be deployed on the localnet")
+	predicted := util.PredictCreate2Address(factory, salt, creation)
+	require.Equal(t, predicted.Hex(), result.Address, "Token should be deployed at the predicted address")
+
+	contract, err := token.NewToken(predicted, h.Backend)
+	require.NoError(t, err, "Error binding Token")
+	balance, err := contract.BalanceOf(nil, h.Address("alice"))
+	require.NoError(t, err, "Error getting holder balance")
+	require.Equal(t, params.InitialSupply, balance, "The holder should hold the initial supply")
+	balance, err = contract.BalanceOf(nil, factory)
+	require.NoError(t, err, "Error getting factory balance")
+	require.Zero(t, balance.Sign(), "Nothing should be minted to the factory")
+
+	// The same salt and parameters give the same address, which is skipped
+	var again create2Result
+	require.NoError(t, cli.Run("deployer", &again, "deploy", args...), "Error deploying Token again")
+	require.True(t, again.Skipped, "Second deployment should be skipped")
+	require.Equal(t, result.Address, again.Address, "Second deployment should have the same address")
+
+	manifest, err := util.LoadManifest(cli.Manifest)
+	require.NoError(t, err, "Error loading manifest")
+	deployment, err := manifest.Lookup(profile.Name, "Token")
+	require.NoError(t, err, "Token should be recorded in the manifest")
+	require.Equal(t, predicted, deployment.Address, "Manifest should record the predicted address")
+	require.Equal(t, &params, deployment.Token, "Manifest should record the Token parameters")
+}
@@ -0,0 +1,189 @@
+/** erc20_module_test.go contains TDD ( Test Driven Development ) style tests for the
+  x/erc20 registration and conversion flow in scripts/utils/erc20_module.go and
+  scripts/utils/gov.go. evmosd is replaced by a fake command runner, and the REST API
+  by a fake server, which pairs the token once its proposal has passed.
+*/
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// fakeEvmos is a fake evmos node, for both evmosd and the REST API
+type fakeEvmos struct {
+	mu       sync.Mutex
+	calls    [][]string
+	votes    int
+	polls    int
+	contract common.Address
+}
+
+// run is the fake evmosd binary, which records its arguments and returns the JSON
+// result of the transaction
+func (f *fakeEvmos) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, append([]string{name}, args...))
+
+	command := strings.Join(args[:3], " ")
+	switch {
+	case command == "tx gov submit-proposal":
+		return []byte(`{"height":"10","txhash":"SUBMIT","code":0,"logs":[{"events":[{"type":"submit_proposal","attributes":[{"key":"proposal_id","value":"3"}]}]}]}`), nil
+	case command == "tx gov vote":
+		f.votes++
+		return []byte(`{"height":"11","txhash":"VOTE","code":0,"logs":[]}`), nil
+	case strings.HasPrefix(command, "tx erc20 convert-erc20"):
+		return []byte(`{"height":"12","txhash":"CONVERT","code":5,"codespace":"erc20","raw_log":"insufficient funds"}`), nil
+	}
+	return nil, fmt.Errorf("unexpected command %v", args)
+}
+
+// ServeHTTP is the fake REST API. The proposal passes after a vote and a poll, and
+// the token is then paired.
+func (f *fakeEvmos) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/cosmos/gov/v1beta1/proposals/3":
+		status := util.ProposalStatusVoting
+		if f.votes > 0 && f.polls > 0 {
+			status = util.ProposalStatusPassed
+		}
+		f.polls++
+		fmt.Fprintf(w, `{"proposal":{"proposal_id":"3","status":%q}}`, status)
+	case "/evmos/erc20/v1/token_pairs/" + f.contract.Hex():
+		if f.votes == 0 || f.polls < 2 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"code":5,"message":"token pair with token '%s': not found"}`, f.contract.Hex())
+			return
+		}
+		fmt.Fprintf(w, `{"token_pair":{"erc20_address":%q,"denom":%q,"enabled":true,"contract_owner":"OWNER_EXTERNAL"}}`, f.contract.Hex(), util.ERC20Denom(f.contract))
+	case "/evmos/erc20/v1/token_pairs":
+		if r.URL.Query().Get("pagination.key") == "" {
+			fmt.Fprint(w, `{"token_pairs":[{"erc20_address":"0x01","denom":"erc20/0x01","enabled":true}],"pagination":{"next_key":"AQ=="}}`)
+			return
+		}
+		fmt.Fprint(w, `{"token_pairs":[{"erc20_address":"0x02","denom":"erc20/0x02","enabled":false}],"pagination":{"next_key":null}}`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		fmt.Fprint(w, `{"code":12,"message":"Not Implemented"}`)
+	}
+}
+
+// Test ERC20Registration
+// Checks that the register-erc20 proposal is submitted and voted with evmosd, and that
+// the flow waits for the proposal to pass and the token to be paired
+func TestERC20Registration(t *testing.T) {
+	contract := common.HexToAddress("0x5dC3C0DC3A1Cc0b5D5D2a89E8f6D2aB3C9e4c5a1")
+	fake := &fakeEvmos{contract: contract}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	evmosd, err := util.NewEvmosd(&util.LocalNetwork, util.DefaultSignerOptions)
+	require.NoError(t, err, "Error creating evmosd")
+	evmosd.Run = fake.run
+	evmosd.GasPrices = util.CosmosGasPrices(big.NewInt(7))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = util.GetTokenPair(ctx, server.URL, contract.Hex())
+	require.ErrorIs(t, err, util.ErrTokenPairNotFound, "Unregistered tokens should not be paired")
+
+	id, res, err := evmosd.SubmitRegisterERC20Proposal(ctx, "mykey", contract.Hex(), "Register TOK", "Pair TOK", util.DefaultProposalDeposit)
+	require.NoError(t, err, "Error submitting proposal")
+	require.Equal(t, uint64(3), id, "Incorrect proposal ID")
+	require.Equal(t, "SUBMIT", res.TxHash, "Incorrect transaction hash")
+
+	_, err = evmosd.Vote(ctx, "mykey", id, "yes")
+	require.NoError(t, err, "Error voting")
+
+	proposal, err := util.WaitForProposal(ctx, server.URL, id, time.Millisecond)
+	require.NoError(t, err, "Error waiting for proposal")
+	require.Equal(t, util.ProposalStatusPassed, proposal.Status, "Proposal should pass")
+
+	pair, err := util.WaitForTokenPair(ctx, server.URL, contract, time.Millisecond)
+	require.NoError(t, err, "Error waiting for token pair")
+	require.Equal(t, util.TokenPair{
+		ERC20Address:  contract.Hex(),
+		Denom:         "erc20/" + contract.Hex(),
+		Enabled:       true,
+		ContractOwner: "OWNER_EXTERNAL",
+	}, *pair, "Incorrect token pair")
+
+	// Every transaction is signed with the keyring and broadcast to the profile's node
+	require.Len(t, fake.calls, 2, "Incorrect number of evmosd calls")
+	submit := strings.Join(fake.calls[0], " ")
+	for _, arg := range []string{
+		"evmosd tx gov submit-proposal register-erc20 " + contract.Hex(),
+		"--title Register TOK",
+		"--deposit 10000000aevmos",
+		"--from mykey",
+		"--keyring-backend test",
+		"--chain-id evmos_9000-1",
+		"--node tcp://localhost:26657",
+		"--gas-prices 7aevmos",
+		"--broadcast-mode block",
+	} {
+		require.Contains(t, submit, arg, "Missing evmosd argument")
+	}
+	require.Contains(t, strings.Join(fake.calls[1], " "), "evmosd tx gov vote 3 yes", "Incorrect vote")
+}
+
+// Test ERC20Conversion
+// Checks that conversions are sent to evmosd with the receiver in the format of each
+// message, and that failed transactions return their log
+func TestERC20Conversion(t *testing.T) {
+	contract := common.HexToAddress("0x5dC3C0DC3A1Cc0b5D5D2a89E8f6D2aB3C9e4c5a1")
+	receiver := common.HexToAddress("0x14574a6DFF2Ddf9e07828b4345d3040919AF5652")
+	fake := &fakeEvmos{contract: contract}
+
+	evmosd, err := util.NewEvmosd(&util.LocalNetwork, util.DefaultSignerOptions)
+	require.NoError(t, err, "Error creating evmosd")
+	evmosd.Run = fake.run
+
+	res, err := evmosd.ConvertERC20(context.Background(), "mykey", contract, big.NewInt(10), receiver)
+	require.ErrorIs(t, err, util.ErrCosmosTxFailed, "Failed transactions should return an error")
+	require.Contains(t, err.Error(), "insufficient funds", "The error should hold the transaction log")
+	require.Equal(t, "CONVERT", res.TxHash, "The response of failed transactions should be returned")
+	require.Equal(t, []string{"tx", "erc20", "convert-erc20", contract.Hex(), "10", util.AddressToBech32(receiver)}, fake.calls[0][1:7], "Incorrect convert-erc20 arguments")
+
+	_, err = evmosd.ConvertCoin(context.Background(), "mykey", util.ERC20Denom(contract), big.NewInt(10), receiver)
+	require.Error(t, err, "Unexpected commands should return an error")
+	require.Equal(t, []string{"tx", "erc20", "convert-coin", "10" + util.ERC20Denom(contract), receiver.Hex()}, fake.calls[1][1:6], "Incorrect convert-coin arguments")
+}
+
+// Test GetTokenPairs
+// Checks that every page of token pairs is returned
+func TestGetTokenPairs(t *testing.T) {
+	server := httptest.NewServer(&fakeEvmos{})
+	defer server.Close()
+
+	pairs, err := util.GetTokenPairs(context.Background(), server.URL)
+	require.NoError(t, err, "Error during GetTokenPairs")
+	data, err := json.Marshal(pairs)
+	require.NoError(t, err, "Error encoding token pairs")
+	require.JSONEq(t, `[
+		{"erc20_address":"0x01","denom":"erc20/0x01","enabled":true,"contract_owner":""},
+		{"erc20_address":"0x02","denom":"erc20/0x02","enabled":false,"contract_owner":""}
+	]`, string(data), "Incorrect token pairs")
+
+	_, err = util.GetTokenPairs(context.Background(), "")
+	require.ErrorIs(t, err, util.ErrNoRESTEndpoint, "Profiles without a REST endpoint should return an error")
+}
@@ -0,0 +1,200 @@
+/** erc20_property_test.go contains property-based tests of the ERC20 invariants of the
+  Token contract, with the model and stateful tester in tests/test_utils. Random
+  sequences of calls are run against the simulated backend, and native fuzz targets
+  decode their sequences from the fuzzed bytes. Failing sequences are shrunk before
+  being reported.
+*/
+
+package tests
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+var (
+	propertyRuns   = flag.Int("property-runs", 10, "number of random sequences run by TestERC20Properties")
+	propertyLength = flag.Int("property-length", 30, "number of calls in each random sequence")
+	propertySeed   = flag.Int64("property-seed", 0, "seed of the first random sequence, 0 for the current time")
+)
+
+// tokenSupply is the supply minted to the deployer of the Token contract
+var tokenSupply = new(big.Int).Mul(big.NewInt(100), testUtil.Ten18)
+
+// checkERC20Sequence fails the test with the shrunk sequence if an invariant does not
+// hold after one of the ops
+func checkERC20Sequence(t *testing.T, ops []testUtil.ERC20Op) {
+	t.Helper()
+	err := testUtil.CheckERC20Sequence(ops)
+	if err == nil {
+		return
+	}
+	fails := func(ops []testUtil.ERC20Op) bool {
+		return testUtil.CheckERC20Sequence(ops) != nil
+	}
+	if failure, ok := err.(*testUtil.ERC20PropertyError); ok {
+		err = testUtil.CheckERC20Sequence(testUtil.ShrinkERC20Sequence(failure.Ops, fails))
+	}
+	t.Fatalf("ERC20 invariant violated, minimal reproduction %v", err)
+}
+
+// Test ERC20Model
+// Checks that the model accepts and rejects calls as the ERC20 specification does,
+// with the events of each accepted call, and that rejected calls change nothing
+func TestERC20Model(t *testing.T) {
+	addresses := map[string]common.Address{
+		"deployer": common.HexToAddress("0x01"),
+		"alice":    common.HexToAddress("0x02"),
+		"bob":      common.HexToAddress("0x03"),
+	}
+	address := func(name string) common.Address { return addresses[name] }
+	op := func(kind testUtil.ERC20OpKind, sender, from, to string, amount int64) testUtil.ERC20Op {
+		return testUtil.ERC20Op{Kind: kind, Sender: sender, From: from, To: to, Amount: big.NewInt(amount)}
+	}
+	infinite := testUtil.ERC20Op{Kind: testUtil.OpApprove, Sender: "deployer", To: "alice", Amount: math.MaxBig256}
+
+	testcases := []struct {
+		name       string
+		setup      []testUtil.ERC20Op
+		op         testUtil.ERC20Op
+		events     string
+		rejected   bool
+		balances   map[string]int64
+		allowances int64
+	}{
+		{"Transfer", nil, op(testUtil.OpTransfer, "deployer", "", "alice", 40), "[Transfer(0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000002, 40)]", false, map[string]int64{"deployer": 60, "alice": 40}, 0},
+		{"Transfer exceeding balance", nil, op(testUtil.OpTransfer, "alice", "", "bob", 1), "[]", true, map[string]int64{"deployer": 100}, 0},
+		{"Transfer to self", nil, op(testUtil.OpTransfer, "deployer", "", "deployer", 100), "[Transfer(0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000001, 100)]", false, map[string]int64{"deployer": 100}, 0},
+		{"TransferFrom spends allowance", []testUtil.ERC20Op{op(testUtil.OpApprove, "deployer", "", "alice", 50)}, op(testUtil.OpTransferFrom, "alice", "deployer", "bob", 30), "[Approval(0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000002, 20) Transfer(0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000003, 30)]", false, map[string]int64{"deployer": 70, "bob": 30}, 20},
+		{"TransferFrom exceeding allowance", []testUtil.ERC20Op{op(testUtil.OpApprove, "deployer", "", "alice", 10)}, op(testUtil.OpTransferFrom, "alice", "deployer", "bob", 30), "[]", true, map[string]int64{"deployer": 100}, 10},
+		{"Increase allowance", []testUtil.ERC20Op{op(testUtil.OpApprove, "deployer", "", "alice", 10)}, op(testUtil.OpIncreaseAllowance, "deployer", "", "alice", 5), "[Approval(0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000002, 15)]", false, map[string]int64{"deployer": 100}, 15},
+		{"Increase allowance overflow", []testUtil.ERC20Op{infinite}, op(testUtil.OpIncreaseAllowance, "deployer", "", "alice", 1), "[]", true, map[string]int64{"deployer": 100}, -1},
+		{"Decrease allowance below zero", []testUtil.ERC20Op{op(testUtil.OpApprove, "deployer", "", "alice", 10)}, op(testUtil.OpDecreaseAllowance, "deployer", "", "alice", 11), "[]", true, map[string]int64{"deployer": 100}, 10},
+		{"Infinite allowance is not spent", []testUtil.ERC20Op{infinite}, op(testUtil.OpTransferFrom, "alice", "deployer", "alice", 100), "[Transfer(0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000002, 100)]", false, map[string]int64{"alice": 100}, -1},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			model := testUtil.NewERC20Model(addresses["deployer"], big.NewInt(100))
+			for _, op := range tc.setup {
+				_, err := model.Apply(op, address)
+				require.NoError(t, err, "Error during setup op %s", op)
+			}
+
+			events, err := model.Apply(tc.op, address)
+			if tc.rejected {
+				require.ErrorIs(t, err, testUtil.ErrERC20Rejected, "The op should be rejected")
+			} else {
+				require.NoError(t, err, "The op should be accepted")
+			}
+			require.Equal(t, tc.events, fmt.Sprint(events), "Incorrect events")
+
+			for name, account := range addresses {
+				balance := model.BalanceOf(account)
+				require.Equal(t, 0, balance.Cmp(big.NewInt(tc.balances[name])), "Incorrect balance of %s %v", name, balance)
+			}
+			allowance := big.NewInt(tc.allowances)
+			if tc.allowances < 0 {
+				allowance = math.MaxBig256
+			}
+			got := model.Allowance(addresses["deployer"], addresses["alice"])
+			require.Equal(t, 0, got.Cmp(allowance), "Incorrect allowance %v", got)
+		})
+	}
+}
+
+// Test ShrinkERC20Sequence
+// Checks that failing sequences are shrunk to the ops, and the amounts, that fail
+func TestShrinkERC20Sequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ops := testUtil.RandomERC20Sequence(rng, testUtil.ERC20Accounts, tokenSupply, 40)
+	ops[7] = testUtil.ERC20Op{Kind: testUtil.OpApprove, Sender: "alice", To: "bob", Amount: big.NewInt(500)}
+	ops[31] = testUtil.ERC20Op{Kind: testUtil.OpDecreaseAllowance, Sender: "bob", To: "carol", Amount: big.NewInt(700)}
+
+	// Fails when an approve from alice is followed by a decreaseAllowance from bob
+	fails := func(ops []testUtil.ERC20Op) bool {
+		approved := false
+		for _, op := range ops {
+			switch {
+			case op.Kind == testUtil.OpApprove && op.Sender == "alice":
+				approved = true
+			case op.Kind == testUtil.OpDecreaseAllowance && op.Sender == "bob" && approved:
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, fails(ops), "The sequence should fail")
+
+	shrunk := testUtil.ShrinkERC20Sequence(ops, fails)
+	require.Len(t, shrunk, 2, "Incorrect length of shrunk sequence %v", shrunk)
+	require.Equal(t, testUtil.OpApprove, shrunk[0].Kind, "Incorrect first op")
+	require.Equal(t, testUtil.OpDecreaseAllowance, shrunk[1].Kind, "Incorrect second op")
+	for _, op := range shrunk {
+		require.Equal(t, 0, op.Amount.Sign(), "The amounts should be shrunk to 0")
+	}
+}
+
+// Test ERC20Properties
+// Checks that the invariants of the ERC20 specification hold after every call of
+// random sequences across several accounts
+func TestERC20Properties(t *testing.T) {
+	seed := *propertySeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	for run := 0; run < *propertyRuns; run++ {
+		runSeed := seed + int64(run)
+		ops := testUtil.RandomERC20Sequence(rand.New(rand.NewSource(runSeed)), testUtil.ERC20Accounts, tokenSupply, *propertyLength)
+		t.Logf("Run %d with -property-seed %d", run, runSeed)
+		checkERC20Sequence(t, ops)
+	}
+}
+
+// FuzzERC20Sequence checks the invariants of the ERC20 specification after every call
+// of the sequence decoded from the fuzzed bytes
+func FuzzERC20Sequence(f *testing.F) {
+	// deployer approves alice for the supply, alice sends half of it to bob, and bob
+	// sends more than he holds
+	f.Add([]byte{1, 0, 0, 1, 252, 2, 1, 0, 2, 100, 0, 2, 0, 3, 253})
+	// deployer approves alice for an infinite allowance, which can't be increased
+	f.Add([]byte{1, 0, 0, 1, 255, 3, 0, 0, 1, 1, 2, 1, 0, 1, 252})
+	// alice decreases an allowance she never gave
+	f.Add([]byte{4, 1, 0, 2, 1})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkERC20Sequence(t, testUtil.DecodeERC20Sequence(data, testUtil.ERC20Accounts, tokenSupply))
+	})
+}
+
+// FuzzERC20Transfer checks the invariants after a transfer of any amount between any
+// two accounts
+func FuzzERC20Transfer(f *testing.F) {
+	f.Add(uint8(0), uint8(1), []byte{1})
+	f.Add(uint8(1), uint8(0), []byte{1})
+	f.Add(uint8(0), uint8(0), tokenSupply.Bytes())
+
+	f.Fuzz(func(t *testing.T, from, to uint8, amount []byte) {
+		if len(amount) > 32 {
+			t.Skip("amounts are uint256")
+		}
+		accounts := testUtil.ERC20Accounts
+		checkERC20Sequence(t, []testUtil.ERC20Op{{
+			Kind:   testUtil.OpTransfer,
+			Sender: accounts[int(from)%len(accounts)],
+			To:     accounts[int(to)%len(accounts)],
+			Amount: new(big.Int).SetBytes(amount),
+		}})
+	})
+}
@@ -0,0 +1,227 @@
+/** events_test.go contains TDD ( Test Driven Development ) style tests for the
+  Token event stream in scripts/utils/events.go, using a simulated backend.
+*/
+
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// decodeEvents returns the JSON line events read from r
+func decodeEvents(t *testing.T, r io.Reader) []util.TokenEvent {
+	var events []util.TokenEvent
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var event util.TokenEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), "Invalid event line %s", scanner.Text())
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err(), "Error reading events")
+	return events
+}
+
+// readEvents returns the JSON line events of the file at path
+func readEvents(t *testing.T, path string) []util.TokenEvent {
+	file, err := os.Open(path)
+	require.NoError(t, err, "Error opening events")
+	defer file.Close()
+	return decodeEvents(t, file)
+}
+
+// Test EventStream
+// Checks that events are backfilled in chunks, that a resumed stream writes no
+// duplicates, and that events of blocks removed by a reorg are rewound
+func TestEventStream(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(3)
+	require.NoError(t, err, "Error generating private keys")
+	alice, bob := addresses[1], addresses[2]
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock*10, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	// Block 1 mints the supply to the owner
+	contractAddress, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+
+	// Blocks 2 to 4 hold a transfer, an approval and a transfer
+	_, err = contract.Transfer(auth, alice, big.NewInt(10))
+	require.NoError(t, err, "Error transferring to alice")
+	client.Commit()
+	_, err = contract.Approve(auth, bob, big.NewInt(5))
+	require.NoError(t, err, "Error approving bob")
+	client.Commit()
+	_, err = contract.Transfer(auth, bob, big.NewInt(20))
+	require.NoError(t, err, "Error transferring to bob")
+	client.Commit()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "events.jsonl")
+	checkpointPath := outPath + ".checkpoint.json"
+	all := util.EventFilter{Transfers: true, Approvals: true}
+
+	// Backfill every block in chunks of 2 blocks
+	output, err := util.OpenEventOutput(outPath, 0)
+	require.NoError(t, err, "Error opening output")
+	stream, err := util.NewEventStream(client, all, util.NewEventCheckpoint("simulated", contractAddress, all, 0), checkpointPath, output)
+	require.NoError(t, err, "Error during NewEventStream")
+	stream.ChunkSize = 2
+	require.NoError(t, stream.SyncToHead(ctx), "Error during SyncToHead")
+	require.NoError(t, output.Close(), "Error closing output")
+
+	events := readEvents(t, outPath)
+	require.Len(t, events, 4, "Incorrect number of events")
+	require.Equal(t, util.EventTransfer, events[0].Type, "Mint should be a transfer")
+	require.Equal(t, common.Address{}, *events[0].From, "Mint should be from the zero address")
+	require.Equal(t, util.EventApproval, events[2].Type, "Approval should be in block order")
+	require.Equal(t, bob, *events[2].Spender, "Incorrect approval spender")
+	require.Equal(t, "20", events[3].Value, "Incorrect transfer value")
+
+	// A new transfer in block 5 is the only event written by a resumed stream
+	_, err = contract.Transfer(auth, alice, big.NewInt(30))
+	require.NoError(t, err, "Error transferring to alice")
+	client.Commit()
+
+	checkpoint, err := util.LoadEventCheckpoint(checkpointPath)
+	require.NoError(t, err, "Error during LoadEventCheckpoint")
+	require.NoError(t, checkpoint.Matches("simulated", contractAddress, all), "Checkpoint should match its stream")
+	require.ErrorIs(t, checkpoint.Matches("simulated", contractAddress, util.EventFilter{Transfers: true}), util.ErrCheckpointMismatch)
+	require.Equal(t, uint64(5), checkpoint.Next, "Incorrect next block")
+
+	output, err = util.OpenEventOutput(outPath, checkpoint.Offset)
+	require.NoError(t, err, "Error opening output")
+	stream, err = util.NewEventStream(client, all, checkpoint, checkpointPath, output)
+	require.NoError(t, err, "Error during NewEventStream")
+	require.NoError(t, stream.SyncToHead(ctx), "Error during SyncToHead")
+	require.NoError(t, output.Close(), "Error closing output")
+
+	events = readEvents(t, outPath)
+	require.Len(t, events, 5, "Resumed stream should not write duplicates")
+	require.Equal(t, "30", events[4].Value, "Incorrect transfer value")
+
+	// A stdout stream of the transfers to bob, which cannot be rewound
+	var buf bytes.Buffer
+	toBob := util.EventFilter{Transfers: true, To: []common.Address{bob}}
+	bufOutput := util.NewEventOutput(&buf)
+	bufStream, err := util.NewEventStream(client, toBob, util.NewEventCheckpoint("simulated", contractAddress, toBob, 0), "", bufOutput)
+	require.NoError(t, err, "Error during NewEventStream")
+	require.NoError(t, bufStream.SyncToHead(ctx), "Error during SyncToHead")
+
+	// Replace block 5 with a longer chain transferring to bob instead
+	block4, err := client.HeaderByNumber(ctx, big.NewInt(4))
+	require.NoError(t, err, "Error getting block 4")
+	require.NoError(t, client.Fork(ctx, block4.Hash()), "Error forking chain")
+	_, err = contract.Transfer(auth, bob, big.NewInt(40))
+	require.NoError(t, err, "Error transferring to bob")
+	client.Commit()
+	client.Commit()
+
+	checkpoint, err = util.LoadEventCheckpoint(checkpointPath)
+	require.NoError(t, err, "Error during LoadEventCheckpoint")
+	output, err = util.OpenEventOutput(outPath, checkpoint.Offset)
+	require.NoError(t, err, "Error opening output")
+	stream, err = util.NewEventStream(client, all, checkpoint, checkpointPath, output)
+	require.NoError(t, err, "Error during NewEventStream")
+	require.NoError(t, stream.SyncToHead(ctx), "Error during SyncToHead")
+	require.NoError(t, output.Close(), "Error closing output")
+
+	events = readEvents(t, outPath)
+	require.Len(t, events, 5, "Reorged events should be replaced")
+	require.Equal(t, "40", events[4].Value, "Removed transfer should be rewound")
+	require.Equal(t, bob, *events[4].To, "Incorrect transfer recipient")
+
+	// Outputs that cannot be rewound mark the reorg instead
+	require.NoError(t, bufStream.SyncToHead(ctx), "Error during SyncToHead")
+	require.NoError(t, bufOutput.Flush(), "Error flushing output")
+	events = decodeEvents(t, &buf)
+	require.Len(t, events, 3, "Incorrect number of filtered events")
+	require.Equal(t, "20", events[0].Value, "Incorrect transfer value")
+	require.Equal(t, util.EventReorg, events[1].Type, "Reorg should be marked")
+	require.Equal(t, uint64(5), events[1].Block, "Incorrect reorg block")
+	require.Equal(t, "40", events[2].Value, "Incorrect transfer value")
+	for _, event := range events {
+		if event.Type == util.EventTransfer {
+			require.Equal(t, bob, *event.To, "Filtered transfers should be to bob")
+		}
+	}
+}
+
+// failingWriter is an output whose writes always fail, such as a closed stdout
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+// Test EventStream checkpoint of stdout outputs
+// Checks that the checkpoint of an output that cannot be rewound only advances once a
+// chunk is written, and that its offsets continue across resumed streams
+func TestEventStreamStdoutCheckpoint(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(2)
+	require.NoError(t, err, "Error generating private keys")
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock*10, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	contractAddress, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+	_, err = contract.Transfer(auth, addresses[1], big.NewInt(10))
+	require.NoError(t, err, "Error transferring")
+	client.Commit()
+
+	ctx := context.Background()
+	checkpointPath := filepath.Join(t.TempDir(), "events.checkpoint.json")
+	transfers := util.EventFilter{Transfers: true}
+
+	// A failed write leaves no checkpoint behind
+	stream, err := util.NewEventStream(client, transfers, util.NewEventCheckpoint("simulated", contractAddress, transfers, 0), checkpointPath, util.NewEventOutput(failingWriter{}))
+	require.NoError(t, err, "Error during NewEventStream")
+	require.ErrorContains(t, stream.SyncToHead(ctx), "broken pipe", "Failed writes should fail the stream")
+	_, err = os.Stat(checkpointPath)
+	require.ErrorIs(t, err, os.ErrNotExist, "The checkpoint should not advance past unwritten events")
+
+	var first bytes.Buffer
+	stream, err = util.NewEventStream(client, transfers, util.NewEventCheckpoint("simulated", contractAddress, transfers, 0), checkpointPath, util.NewEventOutput(&first))
+	require.NoError(t, err, "Error during NewEventStream")
+	require.NoError(t, stream.SyncToHead(ctx), "Error during SyncToHead")
+	require.Len(t, decodeEvents(t, bytes.NewReader(first.Bytes())), 2, "Incorrect number of events")
+
+	checkpoint, err := util.LoadEventCheckpoint(checkpointPath)
+	require.NoError(t, err, "Error during LoadEventCheckpoint")
+	require.Equal(t, int64(first.Len()), checkpoint.Offset, "The checkpoint should record what was written")
+
+	// A resumed stream writes only the new transfer, and keeps counting the offset
+	_, err = contract.Transfer(auth, addresses[1], big.NewInt(20))
+	require.NoError(t, err, "Error transferring")
+	client.Commit()
+
+	var second bytes.Buffer
+	stream, err = util.NewEventStream(client, transfers, checkpoint, checkpointPath, util.NewEventOutput(&second))
+	require.NoError(t, err, "Error during NewEventStream")
+	require.NoError(t, stream.SyncToHead(ctx), "Error during SyncToHead")
+	events := decodeEvents(t, bytes.NewReader(second.Bytes()))
+	require.Len(t, events, 1, "Resumed stream should not write duplicates")
+	require.Equal(t, "20", events[0].Value, "Incorrect transfer value")
+
+	checkpoint, err = util.LoadEventCheckpoint(checkpointPath)
+	require.NoError(t, err, "Error during LoadEventCheckpoint")
+	require.Equal(t, int64(first.Len()+second.Len()), checkpoint.Offset, "Offsets should continue across resumed streams")
+}
@@ -0,0 +1,212 @@
+/** fees_test.go contains TDD ( Test Driven Development ) style tests for the
+  fee options and gas estimation in scripts/utils/fees.go, using a simulated backend.
+*/
+
+package tests
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// noBaseFeeBackend is a simulated backend whose headers have no base fee,
+// as returned by nodes without EIP-1559 support
+type noBaseFeeBackend struct {
+	testUtil.SimulatedAuthBackend
+}
+
+func (b noBaseFeeBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := b.SimulatedAuthBackend.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	header = types.CopyHeader(header)
+	header.BaseFee = nil
+	return header, nil
+}
+
+// Test GetAuth fees
+// Checks that dynamic or legacy fees are chosen by the fee mode, and that the caps are applied
+func TestGetAuthFees(t *testing.T) {
+	privKeys, _, err := testUtil.GeneratePrivKeysAndAddresses(1)
+	require.NoError(t, err, "Error generating private key")
+
+	client, _, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+	backend := testUtil.SimulatedAuthBackend{SimulatedBackend: client}
+
+	header, err := client.HeaderByNumber(context.Background(), nil)
+	require.NoError(t, err, "Error getting latest header")
+	baseFee := header.BaseFee
+	require.NotNil(t, baseFee, "Simulated backend should have a base fee")
+
+	tip, err := client.SuggestGasTipCap(context.Background())
+	require.NoError(t, err, "Error getting tip suggestion")
+
+	testcases := []struct {
+		name       string
+		backend    util.AuthBackend
+		fees       util.FeeOptions
+		expErr     bool
+		expDynamic bool
+		expFeeCap  *big.Int
+		expTipCap  *big.Int
+	}{
+		{
+			"Dynamic fees from the base fee",
+			backend,
+			util.DefaultFeeOptions,
+			false,
+			true,
+			new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip),
+			tip,
+		},
+		{
+			"Fee cap limited by max fee",
+			backend,
+			util.FeeOptions{Mode: util.FeeModeDynamic, MaxFeeCap: baseFee, GasMultiplier: 1},
+			false,
+			true,
+			baseFee,
+			tip,
+		},
+		{
+			"Tip cap limited by max tip",
+			backend,
+			util.FeeOptions{Mode: util.FeeModeAuto, MaxTipCap: big.NewInt(0), GasMultiplier: 1},
+			false,
+			true,
+			new(big.Int).Mul(baseFee, big.NewInt(2)),
+			big.NewInt(0),
+		},
+		{
+			"Max fee below base fee",
+			backend,
+			util.FeeOptions{Mode: util.FeeModeAuto, MaxFeeCap: new(big.Int).Sub(baseFee, big.NewInt(1)), GasMultiplier: 1},
+			true,
+			false,
+			nil,
+			nil,
+		},
+		{
+			"Legacy mode",
+			backend,
+			util.FeeOptions{Mode: util.FeeModeLegacy, GasMultiplier: 1},
+			false,
+			false,
+			nil,
+			nil,
+		},
+		{
+			"Legacy fallback without base fee",
+			noBaseFeeBackend{backend},
+			util.DefaultFeeOptions,
+			false,
+			false,
+			nil,
+			nil,
+		},
+		{
+			"Dynamic mode without base fee",
+			noBaseFeeBackend{backend},
+			util.FeeOptions{Mode: util.FeeModeDynamic, GasMultiplier: 1},
+			true,
+			false,
+			nil,
+			nil,
+		},
+		{
+			"Invalid fee mode",
+			backend,
+			util.FeeOptions{Mode: "fast", GasMultiplier: 1},
+			true,
+			false,
+			nil,
+			nil,
+		},
+		{
+			"Invalid gas multiplier",
+			backend,
+			util.FeeOptions{Mode: util.FeeModeAuto, GasMultiplier: 0.5},
+			true,
+			false,
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth, err := util.GetAuth(tc.backend, util.NewKeySigner(privKeys[0]), tc.fees)
+			if tc.expErr {
+				require.Error(t, err, "GetAuth should raise an error")
+				return
+			}
+			require.NoError(t, err, "Error during GetAuth")
+			require.Equal(t, uint64(0), auth.GasLimit, "Gas limit should be left for estimation")
+
+			if tc.expDynamic {
+				require.Nil(t, auth.GasPrice, "Dynamic fee options should not set a gas price")
+				require.Equal(t, tc.expFeeCap, auth.GasFeeCap, "Incorrect fee cap")
+				require.Equal(t, tc.expTipCap, auth.GasTipCap, "Incorrect tip cap")
+			} else {
+				require.NotNil(t, auth.GasPrice, "Legacy options should set a gas price")
+				require.Nil(t, auth.GasFeeCap, "Legacy options should not set a fee cap")
+				require.Nil(t, auth.GasTipCap, "Legacy options should not set a tip cap")
+			}
+		})
+	}
+}
+
+// Test NewGasEstimator
+// Checks that transactions made with GetAuth are dynamic fee transactions, with a gas
+// limit of the multiplied estimate
+func TestNewGasEstimator(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(2)
+	require.NoError(t, err, "Error generating private keys")
+
+	client, _, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+	backend := util.NewGasEstimator(client, 1.5)
+	signer := util.NewKeySigner(privKeys[0])
+
+	auth, err := util.GetAuth(testUtil.SimulatedAuthBackend{SimulatedBackend: client}, signer, util.DefaultFeeOptions)
+	require.NoError(t, err, "Error during GetAuth")
+	_, tx, contract, err := testUtil.DeployToken(auth, backend)
+	require.NoError(t, err, "Error deploying contract")
+	client.Commit()
+
+	receipt, err := client.TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err, "Error getting deployment receipt")
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "Deployment failed")
+	require.Equal(t, uint8(types.DynamicFeeTxType), tx.Type(), "Deployment should be a dynamic fee transaction")
+
+	callData, err := testUtil.GetCallData("transfer", addresses[1], big.NewInt(1))
+	require.NoError(t, err, "Error getting call data")
+	estimate, err := client.EstimateGas(context.Background(), ethereum.CallMsg{
+		From: addresses[0],
+		To:   &receipt.ContractAddress,
+		Data: callData,
+	})
+	require.NoError(t, err, "Error estimating gas")
+
+	auth, err = util.GetAuth(testUtil.SimulatedAuthBackend{SimulatedBackend: client}, signer, util.DefaultFeeOptions)
+	require.NoError(t, err, "Error during GetAuth")
+	tx, err = contract.Transfer(auth, addresses[1], big.NewInt(1))
+	require.NoError(t, err, "Error transferring tokens")
+	client.Commit()
+
+	require.Equal(t, uint64(math.Ceil(float64(estimate)*1.5)), tx.Gas(), "Gas limit should be the multiplied estimate")
+	require.Greater(t, tx.Gas(), estimate, "Gas limit should exceed the estimate")
+}
@@ -0,0 +1,109 @@
+/** feesplit_test.go contains TDD ( Test Driven Development ) style tests for the
+  x/feesplit registration and developer fee queries in scripts/utils/feesplit.go,
+  against a fake evmosd and a fake REST API.
+*/
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// Test RegisterFeeSplit
+// Checks that the contract is registered with the deployment nonces, and the
+// withdrawer in bech32 when one is given
+func TestRegisterFeeSplit(t *testing.T) {
+	contract := common.HexToAddress("0x5dC3C0DC3A1Cc0b5D5D2a89E8f6D2aB3C9e4c5a1")
+	withdrawer := common.HexToAddress("0x14574a6DFF2Ddf9e07828b4345d3040919AF5652")
+
+	testcases := []struct {
+		name       string
+		nonces     []uint64
+		withdrawer *common.Address
+		args       []string
+	}{
+		{"Deployer receives the fees", []uint64{4}, nil, []string{"feesplit", "register", contract.Hex(), "4"}},
+		{"Withdrawer and factory nonce", []uint64{4, 1}, &withdrawer, []string{"feesplit", "register", contract.Hex(), "4,1", util.AddressToBech32(withdrawer)}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var args []string
+			evmosd, err := util.NewEvmosd(&util.LocalNetwork, util.DefaultSignerOptions)
+			require.NoError(t, err, "Error creating evmosd")
+			evmosd.Run = func(ctx context.Context, name string, a ...string) ([]byte, error) {
+				args = a
+				return []byte(`{"height":"5","txhash":"REGISTER","code":0}`), nil
+			}
+
+			res, err := evmosd.RegisterFeeSplit(context.Background(), "mykey", contract, tc.nonces, tc.withdrawer)
+			require.NoError(t, err, "Error during RegisterFeeSplit")
+			require.Equal(t, "REGISTER", res.TxHash, "Incorrect transaction hash")
+			require.Equal(t, append([]string{"tx"}, tc.args...), args[:len(tc.args)+1], "Incorrect evmosd arguments")
+		})
+	}
+}
+
+// Test FeeSplitQueries
+// Checks that the registration of a contract is found in every page of fee splits,
+// and that only the developer fees paid by calls to the contract are summed
+func TestFeeSplitQueries(t *testing.T) {
+	contract := common.HexToAddress("0x5dC3C0DC3A1Cc0b5D5D2a89E8f6D2aB3C9e4c5a1")
+	other := common.HexToAddress("0x01")
+	deployer := util.AddressToBech32(common.HexToAddress("0x14574a6DFF2Ddf9e07828b4345d3040919AF5652"))
+
+	// devFeeTx returns a transaction paying the developer fees of each contract
+	devFeeTx := func(hash string, fees map[common.Address]string) string {
+		var events []string
+		for address, amount := range fees {
+			events = append(events, fmt.Sprintf(`{"type":%q,"attributes":[{"key":"sender","value":"0xabc"},{"key":"contract","value":%q},{"key":"withdrawer_address","value":%q},{"key":"amount","value":%q}]}`,
+				util.EventTypeDistributeDevFeeSplit, address.Hex(), deployer, amount))
+		}
+		return fmt.Sprintf(`{"height":"7","txhash":%q,"code":0,"logs":[{"events":[{"type":"message","attributes":[]},%s]}]}`, hash, strings.Join(events, ","))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/evmos/feesplit/v1/feesplits":
+			if r.URL.Query().Get("pagination.key") == "" {
+				fmt.Fprintf(w, `{"fee_splits":[{"contract_address":%q,"deployer_address":%q}],"pagination":{"next_key":"AQ=="}}`, other.Hex(), deployer)
+				return
+			}
+			fmt.Fprintf(w, `{"fee_splits":[{"contract_address":%q,"deployer_address":%q}],"pagination":{"next_key":null}}`, strings.ToLower(contract.Hex()), deployer)
+		case "/cosmos/tx/v1beta1/txs":
+			require.Equal(t, fmt.Sprintf("distribute_dev_fee_split.contract='%s'", contract.Hex()), r.URL.Query().Get("events"), "Incorrect events query")
+			fmt.Fprintf(w, `{"tx_responses":[%s,%s],"pagination":{"total":"2"}}`,
+				devFeeTx("A", map[common.Address]string{contract: "1000"}),
+				devFeeTx("B", map[common.Address]string{contract: "500", other: "300"}))
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	feeSplit, err := util.GetFeeSplit(ctx, server.URL, contract)
+	require.NoError(t, err, "Error during GetFeeSplit")
+	require.Equal(t, deployer, feeSplit.DeployerAddress, "Incorrect deployer")
+
+	_, err = util.GetFeeSplit(ctx, server.URL, common.HexToAddress("0x02"))
+	require.ErrorIs(t, err, util.ErrFeeSplitNotFound, "Unregistered contracts should not be found")
+
+	fees, total, err := util.GetDevFees(ctx, server.URL, contract)
+	require.NoError(t, err, "Error during GetDevFees")
+	require.Len(t, fees, 2, "Incorrect number of developer fees")
+	require.Equal(t, 0, big.NewInt(1500).Cmp(total), "Incorrect total developer fees %v", total)
+	require.Equal(t, "B", fees[1].TxHash, "Incorrect transaction of developer fee")
+	require.Equal(t, deployer, fees[1].Withdrawer, "Incorrect withdrawer of developer fee")
+}
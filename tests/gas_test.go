@@ -0,0 +1,90 @@
+/** gas_test.go contains TDD ( Test Driven Development ) style tests for the per-method
+  gas report in tests/test_utils/gas.go.
+*/
+
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Test GasReport
+// Checks that the gas of successful transactions is summarised by method, that the
+// baseline round trips, and that only growth beyond the tolerance is a regression
+func TestGasReport(t *testing.T) {
+	tokenABI, err := token.TokenMetaData.GetAbi()
+	require.NoError(t, err, "Error parsing Token ABI")
+	report := testUtil.NewGasReport(tokenABI)
+
+	contract := common.HexToAddress("0x01")
+	call := func(data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: &contract, Data: data})
+	}
+	transfer, err := tokenABI.Pack("transfer", contract, testUtil.Ten18)
+	require.NoError(t, err, "Error packing transfer")
+
+	for _, c := range []struct {
+		tx     *types.Transaction
+		gas    uint64
+		status uint64
+	}{
+		{types.NewTx(&types.LegacyTx{Data: []byte{0x60}}), 1000000, types.ReceiptStatusSuccessful},
+		{call(transfer), 30000, types.ReceiptStatusSuccessful},
+		{call(transfer), 50000, types.ReceiptStatusSuccessful},
+		{call(transfer), 21000, types.ReceiptStatusFailed},
+		{call([]byte{0xde, 0xad, 0xbe, 0xef}), 25000, types.ReceiptStatusSuccessful},
+	} {
+		report.Record(c.tx, &types.Receipt{GasUsed: c.gas, Status: c.status})
+	}
+
+	stats := report.Stats()
+	require.Equal(t, map[string]testUtil.GasStats{
+		testUtil.GasDeployment: {Calls: 1, Min: 1000000, Avg: 1000000, Max: 1000000},
+		"transfer":             {Calls: 2, Min: 30000, Avg: 40000, Max: 50000},
+		"0xdeadbeef":           {Calls: 1, Min: 25000, Avg: 25000, Max: 25000},
+	}, stats, "Incorrect gas statistics")
+
+	var table strings.Builder
+	require.NoError(t, report.WriteTable(&table), "Error writing table")
+	require.Contains(t, table.String(), "transfer      2    30000    40000    50000", "Incorrect table row")
+
+	path := filepath.Join(t.TempDir(), "gas", "baseline.json")
+	require.NoError(t, report.WriteBaseline(path), "Error writing baseline")
+	baseline, err := testUtil.LoadGasBaseline(path)
+	require.NoError(t, err, "Error loading baseline")
+	require.Equal(t, stats, baseline, "The baseline should hold the statistics")
+
+	testcases := []struct {
+		name      string
+		baseline  map[string]testUtil.GasStats
+		tolerance float64
+		err       string
+	}{
+		{"Same gas", baseline, 0, ""},
+		{"Growth within tolerance", map[string]testUtil.GasStats{"transfer": {Avg: 39000, Max: 48000}}, 0.05, ""},
+		{"Max grew", map[string]testUtil.GasStats{"transfer": {Avg: 40000, Max: 45000}}, 0.05, "transfer max 50000 > 45000"},
+		{"Average grew", map[string]testUtil.GasStats{"transfer": {Avg: 35000, Max: 50000}}, 0.05, "transfer avg 40000 > 35000"},
+		{"New method", map[string]testUtil.GasStats{"approve": {Avg: 1, Max: 1}}, 0, ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := report.CheckBaseline(tc.baseline, tc.tolerance)
+			if tc.err == "" {
+				require.NoError(t, err, "Unexpected gas regression")
+				return
+			}
+			require.ErrorIs(t, err, testUtil.ErrGasRegression, "Expected a gas regression")
+			require.Contains(t, err.Error(), tc.err, "Incorrect regression")
+		})
+	}
+}
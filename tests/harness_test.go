@@ -0,0 +1,82 @@
+/** harness_test.go contains TDD ( Test Driven Development ) style tests for the
+  simulated chain harness in tests/test_utils/harness.go.
+*/
+
+package tests
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Test Harness
+// Checks that accounts are funded, that contracts are deployed and transactions
+// committed with their events, and that the chain can be reverted and time travel
+func TestHarness(t *testing.T) {
+	h, err := testUtil.NewHarness("deployer", "alice", "bob")
+	require.NoError(t, err, "Error starting harness")
+	defer h.Close()
+	ctx := context.Background()
+
+	for _, address := range h.Addresses() {
+		balance, err := h.Backend.BalanceAt(ctx, address, nil)
+		require.NoError(t, err, "Error getting balance")
+		require.Equal(t, testUtil.DefaultAccountBalance, balance, "Accounts should be funded")
+	}
+
+	contractAddress, contract, err := testUtil.Deploy(h, "deployer", testUtil.DeployToken)
+	require.NoError(t, err, "Error deploying contract")
+	require.NotEqual(t, h.Address("deployer"), contractAddress, "Incorrect contract address")
+
+	snapshot := h.Snapshot()
+
+	receipt, err := h.Commit(contract.Transfer(h.Auth("deployer"), h.Address("alice"), big.NewInt(10)))
+	require.NoError(t, err, "Error committing transfer")
+	transfer, err := testUtil.ExpectEvent(receipt, contract.ParseTransfer)
+	require.NoError(t, err, "Transfer should emit a Transfer event")
+	require.Equal(t, h.Address("alice"), transfer.To, "Incorrect transfer recipient")
+	require.Empty(t, testUtil.Events(receipt, contract.ParseApproval), "Transfer should not emit approvals")
+
+	// Alice pays for gas from her genesis balance
+	_, err = h.Commit(contract.Transfer(h.Auth("alice"), h.Address("bob"), big.NewInt(4)))
+	require.NoError(t, err, "Error committing transfer from alice")
+
+	balance, err := contract.BalanceOf(nil, h.Address("bob"))
+	require.NoError(t, err, "Error getting balance")
+	require.Equal(t, big.NewInt(4), balance, "Incorrect balance of bob")
+
+	// Reverting removes both transfers
+	require.NoError(t, h.Revert(snapshot), "Error reverting snapshot")
+	balance, err = contract.BalanceOf(nil, h.Address("alice"))
+	require.NoError(t, err, "Error getting balance")
+	require.Equal(t, 0, balance.Sign(), "Reverted transfer should be removed")
+	require.Equal(t, snapshot, h.Snapshot(), "Head should be the snapshot block")
+
+	// The reverted chain accepts new transactions
+	_, err = h.Commit(contract.Approve(h.Auth("deployer"), h.Address("bob"), big.NewInt(7)))
+	require.NoError(t, err, "Error committing approval after revert")
+
+	// A snapshot of blocks that were reverted cannot be restored
+	later := h.Snapshot()
+	require.NoError(t, h.Revert(snapshot), "Error reverting snapshot")
+	h.Backend.Commit()
+	h.Backend.Commit()
+	require.Error(t, h.Revert(later), "Reverted snapshot should be rejected")
+
+	before := h.Now()
+	require.NoError(t, h.AdvanceTime(time.Hour), "Error advancing time")
+	require.GreaterOrEqual(t, h.Now().Sub(before), time.Hour, "Time should move forward")
+
+	// Transactions reverted by the contract are reported
+	auth := h.Auth("alice")
+	auth.GasLimit = testUtil.MaxGasPerBlock
+	_, err = h.Commit(contract.Transfer(auth, h.Address("bob"), big.NewInt(1)))
+	require.ErrorIs(t, err, util.ErrTxReverted, "Failed transfer should be reported")
+}
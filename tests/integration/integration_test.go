@@ -0,0 +1,216 @@
+//go:build integration
+
+/** integration_test.go contains integration tests of tokencli against an in-process Evmos
+  network, started with the evmos/testutil/network package. It lives in its own Go module,
+  so that the Evmos app and its replace directives stay out of the tokencli dependencies.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	ethermint "github.com/evmos/ethermint/types"
+	evmtypes "github.com/evmos/ethermint/x/evm/types"
+	"github.com/evmos/evmos/v8/testutil/network"
+	"github.com/stretchr/testify/require"
+)
+
+// chainID is the EIP-155 chain ID of the evmos_9000-1 test network
+const chainID = 9000
+
+// tokencli runs the tokencli binary against a validator of the test network
+type tokencli struct {
+	t   *testing.T
+	bin string
+	rpc string
+	env []string
+}
+
+// buildTokencli builds tokencli from the repository root into a temporary directory
+func buildTokencli(t *testing.T, val *network.Validator) *tokencli {
+	bin := filepath.Join(t.TempDir(), "tokencli")
+	build := exec.Command("go", "build", "-o", bin, "./scripts/tokencli")
+	build.Dir = "../.."
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, "Error building tokencli: %s", out)
+
+	// The JSON-RPC server listens on all interfaces
+	jsonRPC := val.AppConfig.JSONRPC.Address
+	rpc := "http://127.0.0.1" + jsonRPC[strings.LastIndex(jsonRPC, ":"):]
+	env := append(os.Environ(),
+		"EVMOS_NETWORK=local",
+		"EVMOS_RPC_HTTP="+rpc,
+		"EVMOS_REST="+val.APIAddress,
+		"EVMOS_TENDERMINT_RPC="+val.RPCAddress,
+		"EVMOS_COSMOS_CHAIN_ID="+val.ClientCtx.ChainID,
+		"EVMOS_CHAIN_ID="+big.NewInt(chainID).String(),
+	)
+	return &tokencli{t: t, bin: bin, rpc: rpc, env: env}
+}
+
+// run runs tokencli with the arguments and extra environment, and returns its stdout
+func (c *tokencli) run(env []string, args ...string) []byte {
+	cmd := exec.Command(c.bin, args...)
+	cmd.Dir = "../.."
+	cmd.Env = append(c.env, env...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	require.NoError(c.t, err, "Error running tokencli %s: %s", strings.Join(args, " "), stderr.String())
+	return out
+}
+
+// Test Token on Evmos
+// Checks that tokencli deploys the Token contract with a keyring signer, transfers tokens
+// from the validator and from an eth_secp256k1 account funded in genesis, that the
+// transfer pays the base fee of the feemarket module, and queries the token and bank
+// balances through the JSON-RPC and REST endpoints of a one-validator Evmos network
+func TestTokenOnEvmos(t *testing.T) {
+	cfg := network.DefaultConfig()
+	cfg.NumValidators = 1
+	cfg.ChainID = "evmos_9000-1"
+	cfg.BondDenom = "aevmos"
+	cfg.MinGasPrices = "0aevmos"
+
+	// Fund a fresh account with native aevmos in genesis, so that it can pay for its
+	// own transfer
+	aliceKey, err := crypto.GenerateKey()
+	require.NoError(t, err, "Error generating a key")
+	alice := crypto.PubkeyToAddress(aliceKey.PublicKey)
+	fundGenesisAccount(t, &cfg, alice, new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+
+	net, err := network.New(t, t.TempDir(), cfg)
+	require.NoError(t, err, "Error starting the test network")
+	defer net.Cleanup()
+	_, err = net.WaitForHeight(2)
+	require.NoError(t, err, "Error waiting for the first blocks")
+
+	val := net.Validators[0]
+	cli := buildTokencli(t, val)
+	manifest := filepath.Join(t.TempDir(), "deployments.json")
+	wait := []string{"-manifest", manifest, "-wait-mode", "poll", "-timeout", "30s"}
+
+	// The validator key is an eth_secp256k1 key of the test keyring
+	cli.run(nil, append([]string{"deploy", "-signer", "keyring", "-keyring-home", val.ClientCtx.KeyringDir,
+		"-keyring-backend", "test", "-from", "node0"}, wait...)...)
+
+	cli.run(nil, append([]string{"transfer", "-signer", "keyring", "-keyring-home", val.ClientCtx.KeyringDir,
+		"-keyring-backend", "test", "-from", "node0", "-to", alice.Hex(), "-amount", "10"}, wait...)...)
+
+	aliceEnv := []string{"EVMOS_PRIVATE_KEY=" + common.Bytes2Hex(crypto.FromECDSA(aliceKey))}
+	burn := "0x000000000000000000000000000000000000dEaD"
+	out := cli.run(aliceEnv, append([]string{"transfer", "-signer", "insecure-hex", "-to", burn, "-amount", "2.5", "-output", "json"}, wait...)...)
+	var transfer struct {
+		TxHash string `json:"tx_hash"`
+	}
+	require.NoError(t, json.Unmarshal(out, &transfer), "Error parsing the transfer output: %s", out)
+	checkBaseFee(t, cli, common.HexToHash(transfer.TxHash))
+
+	testcases := []struct {
+		name          string
+		account       string
+		balance       string
+		nativeNonZero bool
+	}{
+		{"Funded account by hex address", alice.Hex(), "7500000000000000000", true},
+		{"Burn address by bech32 address", "evmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqph4dhxzv69", "2500000000000000000", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var balance struct {
+				Balance      string `json:"balance"`
+				Native       string `json:"native"`
+				NativeSource string `json:"native_source"`
+			}
+			out := cli.run(nil, "balance", "-manifest", manifest, "-account", tc.account, "-output", "json")
+			require.NoError(t, json.Unmarshal(out, &balance), "Error parsing the balance output: %s", out)
+			require.Equal(t, tc.balance, balance.Balance, "Incorrect token balance")
+			require.Equal(t, "bank", balance.NativeSource, "The native balance should come from the bank module")
+			require.Equal(t, tc.nativeNonZero, balance.Native != "0", "Incorrect native balance %s", balance.Native)
+		})
+	}
+}
+
+// fundGenesisAccount adds an eth_secp256k1 account holding the amount of aevmos to
+// the auth and bank genesis state of the network. The bank supply is left empty, so
+// that it is computed from the balances, including those of the validators.
+func fundGenesisAccount(t *testing.T, cfg *network.Config, address common.Address, amount *big.Int) {
+	account := sdk.AccAddress(address.Bytes())
+
+	var authGenState authtypes.GenesisState
+	cfg.Codec.MustUnmarshalJSON(cfg.GenesisState[authtypes.ModuleName], &authGenState)
+	accounts, err := authtypes.PackAccounts(authtypes.GenesisAccounts{&ethermint.EthAccount{
+		BaseAccount: authtypes.NewBaseAccount(account, nil, 0, 0),
+		CodeHash:    common.BytesToHash(evmtypes.EmptyCodeHash).Hex(),
+	}})
+	require.NoError(t, err, "Error packing the genesis account")
+	authGenState.Accounts = append(authGenState.Accounts, accounts...)
+	cfg.GenesisState[authtypes.ModuleName] = cfg.Codec.MustMarshalJSON(&authGenState)
+
+	var bankGenState banktypes.GenesisState
+	cfg.Codec.MustUnmarshalJSON(cfg.GenesisState[banktypes.ModuleName], &bankGenState)
+	bankGenState.Balances = append(bankGenState.Balances, banktypes.Balance{
+		Address: account.String(),
+		Coins:   sdk.NewCoins(sdk.NewCoin(cfg.BondDenom, sdk.NewIntFromBigInt(amount))),
+	})
+	cfg.GenesisState[banktypes.ModuleName] = cfg.Codec.MustMarshalJSON(&bankGenState)
+}
+
+// checkBaseFee checks that the latest header has the base fee of the feemarket module,
+// and that the dynamic fee transaction paid the effective gas price of its block, the
+// base fee plus its tip, capped by its fee cap
+func checkBaseFee(t *testing.T, cli *tokencli, txHash common.Hash) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rpcClient, err := rpc.DialContext(ctx, cli.rpc)
+	require.NoError(t, err, "Error dialing the JSON-RPC endpoint")
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	latest, err := client.HeaderByNumber(ctx, nil)
+	require.NoError(t, err, "Error getting the latest header")
+	require.NotNil(t, latest.BaseFee, "The latest header should have a base fee")
+	require.Positive(t, latest.BaseFee.Sign(), "The base fee should be positive")
+
+	tx, _, err := client.TransactionByHash(ctx, txHash)
+	require.NoError(t, err, "Error getting the transaction")
+	require.Equal(t, uint8(types.DynamicFeeTxType), tx.Type(), "The transfer should be a dynamic fee transaction")
+
+	// The receipt of go-ethereum v1.10 has no effective gas price, it is read raw
+	var receipt struct {
+		BlockNumber       *hexutil.Big `json:"blockNumber"`
+		EffectiveGasPrice *hexutil.Big `json:"effectiveGasPrice"`
+	}
+	require.NoError(t, rpcClient.CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash), "Error getting the receipt")
+	require.NotNil(t, receipt.EffectiveGasPrice, "The receipt should have an effective gas price")
+	header, err := client.HeaderByNumber(ctx, receipt.BlockNumber.ToInt())
+	require.NoError(t, err, "Error getting the header of the transaction block")
+	require.NotNil(t, header.BaseFee, "The transaction block should have a base fee")
+
+	expected := new(big.Int).Add(header.BaseFee, tx.GasTipCap())
+	if expected.Cmp(tx.GasFeeCap()) > 0 {
+		expected = tx.GasFeeCap()
+	}
+	price := receipt.EffectiveGasPrice.ToInt()
+	require.GreaterOrEqual(t, price.Cmp(header.BaseFee), 0, "The effective gas price %s should cover the base fee %s", price, header.BaseFee)
+	require.Equal(t, expected.String(), price.String(), "The effective gas price should be the base fee plus the tip, capped by the fee cap")
+}
@@ -0,0 +1,139 @@
+/** manifest_test.go contains TDD ( Test Driven Development ) style tests for the
+  deployment manifest in scripts/utils/manifest.go.
+*/
+
+package tests
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// newTestDeployment returns a Token deployment at the given address
+func newTestDeployment(address common.Address) util.Deployment {
+	receipt := &types.Receipt{
+		ContractAddress: address,
+		TxHash:          common.HexToHash("0x01"),
+		BlockNumber:     big.NewInt(5),
+		GasUsed:         21000,
+	}
+	return util.NewDeployment("Token", token.TokenMetaData, big.NewInt(9000), common.HexToAddress("0xd1"), receipt)
+}
+
+// Test Manifest Record and Save
+// Checks that deployments are saved and loaded, and earlier deployments are kept in the history
+func TestManifestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+
+	// A missing manifest is empty
+	manifest, err := util.LoadManifest(path)
+	require.NoError(t, err, "Error loading missing manifest")
+	_, err = manifest.Lookup("local", "Token")
+	require.True(t, errors.Is(err, util.ErrContractNotDeployed), "Lookup should raise ErrContractNotDeployed")
+
+	first := newTestDeployment(common.HexToAddress("0xa1"))
+	second := newTestDeployment(common.HexToAddress("0xa2"))
+	manifest.Record("local", first)
+	manifest.Record("local", second)
+	require.NoError(t, manifest.Save(path), "Error saving manifest")
+
+	loaded, err := util.LoadManifest(path)
+	require.NoError(t, err, "Error loading manifest")
+	require.Equal(t, util.ManifestVersion, loaded.Version, "Incorrect manifest version")
+
+	current, err := loaded.Lookup("local", "Token")
+	require.NoError(t, err, "Error during Lookup")
+	require.Equal(t, second.Address, current.Address, "Incorrect current deployment")
+	require.Equal(t, uint64(5), current.BlockNumber, "Incorrect block number")
+	require.NotEqual(t, common.Hash{}, current.ABIHash, "ABI hash should be set")
+	require.NotEqual(t, common.Hash{}, current.BytecodeHash, "Bytecode hash should be set")
+
+	history := loaded.Networks["local"].Contracts["Token"].History
+	require.Len(t, history, 1, "Incorrect history length")
+	require.Equal(t, first.Address, history[0].Address, "Incorrect history deployment")
+
+	_, err = loaded.Lookup("local2", "Token")
+	require.True(t, errors.Is(err, util.ErrContractNotDeployed), "Lookup should be per network")
+}
+
+// Test LoadManifest versions
+// Checks that manifests written by a newer version are rejected
+func TestLoadManifestVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "networks": {}}`), 0o600), "Error writing manifest")
+
+	_, err := util.LoadManifest(path)
+	require.Error(t, err, "LoadManifest should raise an error for an unsupported version")
+}
+
+// Test ResolveContract
+// Checks that contracts are resolved by address, or by name for the profile's network and chain
+func TestResolveContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	address := common.HexToAddress("0xa1")
+
+	manifest, err := util.LoadManifest(path)
+	require.NoError(t, err, "Error loading manifest")
+	manifest.Record("local", newTestDeployment(address))
+	require.NoError(t, manifest.Save(path), "Error saving manifest")
+
+	testcases := []struct {
+		name       string
+		profile    util.NetworkProfile
+		contract   string
+		expErr     bool
+		expAddress common.Address
+	}{
+		{
+			"Hex address",
+			util.NetworkProfile{Name: "other", ChainID: 1},
+			"0x00000000000000000000000000000000000000b1",
+			false,
+			common.HexToAddress("0xb1"),
+		},
+		{
+			"Manifest name",
+			util.NetworkProfile{Name: "local", ChainID: 9000},
+			"Token",
+			false,
+			address,
+		},
+		{
+			"Unknown name",
+			util.NetworkProfile{Name: "local", ChainID: 9000},
+			"Other",
+			true,
+			common.Address{},
+		},
+		{
+			"Chain ID mismatch",
+			util.NetworkProfile{Name: "local", ChainID: 9001},
+			"Token",
+			true,
+			common.Address{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := tc.profile
+			resolved, err := util.ResolveContract(path, &profile, tc.contract)
+			if tc.expErr {
+				require.Error(t, err, "ResolveContract should raise an error")
+				return
+			}
+			require.NoError(t, err, "Error during ResolveContract")
+			require.Equal(t, tc.expAddress, resolved, "Incorrect address")
+		})
+	}
+}
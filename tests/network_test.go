@@ -0,0 +1,201 @@
+/** network_test.go contains TDD ( Test Driven Development ) style tests for the
+  network profiles in scripts/utils/network.go.
+*/
+
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// writeNetworksFile writes the given config to a temporary networks file
+// and returns its path
+func writeNetworksFile(t *testing.T, config string) string {
+	path := filepath.Join(t.TempDir(), "networks.json")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600), "Error writing networks file")
+	return path
+}
+
+// Test LoadNetworkProfile
+// Checks that profiles are selected by name, by default and by environment variable,
+// and that environment variables override the profile fields
+func TestLoadNetworkProfile(t *testing.T) {
+	path := writeNetworksFile(t, `{
+		"default": "one",
+		"networks": {
+			"one": {"http": "http://one:8545", "chain_id": 9000},
+			"two": {"http": "http://two:8545", "ws": "ws://two:8546", "chain_id": 9001, "gas_limit": 100, "gas_price": 7}
+		}
+	}`)
+
+	testcases := []struct {
+		name       string
+		network    string
+		env        map[string]string
+		expErr     bool
+		expHTTP    string
+		expChainID int64
+	}{
+		{
+			"Default profile",
+			"",
+			nil,
+			false,
+			"http://one:8545",
+			9000,
+		},
+		{
+			"Named profile",
+			"two",
+			nil,
+			false,
+			"http://two:8545",
+			9001,
+		},
+		{
+			"Profile from environment",
+			"",
+			map[string]string{util.EnvNetwork: "two"},
+			false,
+			"http://two:8545",
+			9001,
+		},
+		{
+			"Environment overrides",
+			"one",
+			map[string]string{util.EnvHTTP: "http://other:8545", util.EnvChainID: "1"},
+			false,
+			"http://other:8545",
+			1,
+		},
+		{
+			"Unknown profile",
+			"three",
+			nil,
+			true,
+			"",
+			0,
+		},
+		{
+			"Invalid chain ID override",
+			"one",
+			map[string]string{util.EnvChainID: "nine"},
+			true,
+			"",
+			0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			profile, err := util.LoadNetworkProfile(path, tc.network)
+			if tc.expErr {
+				require.Error(t, err, "LoadNetworkProfile should raise an error")
+				return
+			}
+			require.NoError(t, err, "Error during LoadNetworkProfile")
+			require.Equal(t, tc.expHTTP, profile.HTTP, "Incorrect http endpoint")
+			require.Equal(t, tc.expChainID, profile.ChainID, "Incorrect chain ID")
+		})
+	}
+}
+
+// Test LoadNetworkConfig
+// Checks that a missing default config file falls back to the local network,
+// while a missing explicit config file raises an error
+func TestLoadNetworkConfig(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err, "Error getting working directory")
+	require.NoError(t, os.Chdir(t.TempDir()), "Error changing directory")
+	defer func() { _ = os.Chdir(wd) }()
+
+	config, err := util.LoadNetworkConfig("")
+	require.NoError(t, err, "Error loading default config")
+	require.Equal(t, util.LocalNetwork.HTTP, config.Networks[util.DefaultNetwork].HTTP, "Incorrect default network")
+
+	_, err = util.LoadNetworkConfig("missing.json")
+	require.Error(t, err, "LoadNetworkConfig should raise an error for a missing file")
+}
+
+// Test ApplyGasDefaults
+// Checks that the profile's gas settings are applied to the transaction options, and
+// that a fixed gas price is refused with dynamic fee options
+func TestApplyGasDefaults(t *testing.T) {
+	path := writeNetworksFile(t, `{"networks": {"local": {"http": "http://x", "gas_limit": 100, "gas_price": 7}}}`)
+
+	profile, err := util.LoadNetworkProfile(path, "")
+	require.NoError(t, err, "Error during LoadNetworkProfile")
+
+	privKeys, _, err := testUtil.GeneratePrivKeysAndAddresses(1)
+	require.NoError(t, err, "Error generating private key")
+
+	auth, err := bind.NewKeyedTransactorWithChainID(privKeys[0], testUtil.TestChainID)
+	require.NoError(t, err, "Error getting auth")
+	auth.GasFeeCap = big.NewInt(10)
+	auth.GasTipCap = big.NewInt(1)
+
+	err = profile.ApplyGasDefaults(auth, util.DefaultFeeOptions)
+	require.NoError(t, err, "Error during ApplyGasDefaults")
+	require.Equal(t, uint64(100), auth.GasLimit, "Incorrect gas limit")
+	require.Equal(t, big.NewInt(7), auth.GasPrice, "Incorrect gas price")
+	require.Nil(t, auth.GasFeeCap, "Fixed gas price should clear the fee cap")
+	require.Nil(t, auth.GasTipCap, "Fixed gas price should clear the tip cap")
+
+	testcases := []struct {
+		name string
+		fees util.FeeOptions
+	}{
+		{"Dynamic fee mode", util.FeeOptions{Mode: util.FeeModeDynamic, GasMultiplier: 1}},
+		{"Max fee", util.FeeOptions{Mode: util.FeeModeAuto, MaxFeeCap: big.NewInt(10), GasMultiplier: 1}},
+		{"Max tip", util.FeeOptions{Mode: util.FeeModeLegacy, MaxTipCap: big.NewInt(1), GasMultiplier: 1}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth.GasPrice = nil
+			auth.GasFeeCap = big.NewInt(10)
+			auth.GasTipCap = big.NewInt(1)
+			err := profile.ApplyGasDefaults(auth, tc.fees)
+			require.ErrorContains(t, err, "conflicts with", "A fixed gas price should conflict with the fee options")
+			require.Nil(t, auth.GasPrice, "Conflicting fee options should be left as they are")
+			require.Equal(t, big.NewInt(10), auth.GasFeeCap, "Conflicting fee options should be left as they are")
+		})
+	}
+}
+
+// Test GetClient chain ID check
+// Checks that a connection is refused when the node's chain ID does not match the profile
+func TestGetClientChainIDMismatch(t *testing.T) {
+	// Stub node that only answers eth_chainId with chain ID 9000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"0x2328"}`)
+	}))
+	defer server.Close()
+
+	client, err := util.GetClient(&util.NetworkProfile{Name: "match", HTTP: server.URL, ChainID: 9000})
+	require.NoError(t, err, "Error getting client for matching chain ID")
+	client.Close()
+
+	_, err = util.GetClient(&util.NetworkProfile{Name: "mismatch", HTTP: server.URL, ChainID: 9001})
+	require.True(t, errors.Is(err, util.ErrChainIDMismatch), "GetClient should refuse a mismatched chain ID")
+}
@@ -0,0 +1,248 @@
+/** nonce_test.go contains TDD ( Test Driven Development ) style tests for the
+  nonce manager in scripts/utils/nonce.go, using a stub node and a simulated backend.
+*/
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// stubNonceBackend is a node that accepts transactions with any unused nonce,
+// and can be set to reject the next send with an error
+type stubNonceBackend struct {
+	mu       sync.Mutex
+	pending  uint64
+	queries  int
+	sendErrs []error
+}
+
+func (b *stubNonceBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queries++
+	return b.pending, nil
+}
+
+func (b *stubNonceBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.sendErrs) > 0 {
+		err := b.sendErrs[0]
+		b.sendErrs = b.sendErrs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	if tx.Nonce() < b.pending {
+		return errors.New("nonce too low")
+	}
+
+	b.pending = tx.Nonce() + 1
+	return nil
+}
+
+// stubTransact builds an unsigned transaction with the nonce of the options
+func stubTransact(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return types.NewTx(&types.LegacyTx{Nonce: opts.Nonce.Uint64()}), nil
+}
+
+// Test NonceManager Next
+// Checks that concurrent callers receive unique, sequential nonces from a single node query
+func TestNonceManagerNext(t *testing.T) {
+	backend := &stubNonceBackend{pending: 7}
+	manager := util.NewNonceManager(backend)
+	address := common.HexToAddress("0x1")
+
+	const n = 50
+	nonces := make([]uint64, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nonces[i], errs[i] = manager.Next(context.Background(), address)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err, "Error during Next")
+	}
+
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for i, nonce := range nonces {
+		require.Equal(t, uint64(7+i), nonce, "Nonces should be unique and sequential")
+	}
+	require.Equal(t, 1, backend.queries, "The pending nonce should be queried once")
+}
+
+// Test NonceManager Release
+// Checks that released nonces are handed out again before new nonces
+func TestNonceManagerRelease(t *testing.T) {
+	manager := util.NewNonceManager(&stubNonceBackend{pending: 5})
+	address := common.HexToAddress("0x1")
+
+	next := func() uint64 {
+		nonce, err := manager.Next(context.Background(), address)
+		require.NoError(t, err, "Error during Next")
+		return nonce
+	}
+
+	require.Equal(t, uint64(5), next())
+	require.Equal(t, uint64(6), next())
+	require.Equal(t, uint64(7), next())
+
+	// A gap in the middle is filled first
+	manager.Release(address, 6)
+	require.Equal(t, uint64(6), next())
+	require.Equal(t, uint64(8), next())
+
+	// Releasing the end of the sequence rewinds it
+	manager.Release(address, 7)
+	manager.Release(address, 8)
+	require.Equal(t, uint64(7), next())
+	require.Equal(t, uint64(8), next())
+	require.Equal(t, uint64(9), next())
+}
+
+// Test NonceManager Send
+// Checks that send errors resync, retry or release the nonce
+func TestNonceManagerSend(t *testing.T) {
+	testcases := []struct {
+		name     string
+		sendErrs []error
+		external uint64
+		expErr   bool
+		expNonce uint64
+		expNext  uint64
+	}{
+		{
+			"Successful send",
+			nil,
+			0,
+			false,
+			0,
+			1,
+		},
+		{
+			"Nonce used by another sender",
+			nil,
+			3,
+			false,
+			3,
+			4,
+		},
+		{
+			"Transaction already known",
+			[]error{errors.New("already known")},
+			0,
+			false,
+			0,
+			1,
+		},
+		{
+			"Failed send releases the nonce",
+			[]error{errors.New("insufficient funds for gas * price + value")},
+			0,
+			true,
+			0,
+			0,
+		},
+		{
+			"Ethermint invalid nonce",
+			[]error{errors.New("invalid nonce; got 0, expected 1: invalid sequence")},
+			0,
+			false,
+			1,
+			2,
+		},
+		{
+			"Ethermint nonce too high releases the nonce",
+			[]error{errors.New("invalid nonce; got 5, expected 1: invalid sequence")},
+			0,
+			true,
+			0,
+			0,
+		},
+		{
+			"Nonce too high releases the nonce",
+			[]error{errors.New("nonce too high")},
+			0,
+			true,
+			0,
+			0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := &stubNonceBackend{}
+			manager := util.NewNonceManager(backend)
+			auth := &bind.TransactOpts{From: common.HexToAddress("0x1")}
+
+			// Sync the manager, before another sender uses nonces behind its back
+			nonce, err := manager.Next(context.Background(), auth.From)
+			require.NoError(t, err, "Error during Next")
+			manager.Release(auth.From, nonce)
+			backend.pending += tc.external
+			backend.sendErrs = tc.sendErrs
+
+			tx, err := manager.Send(context.Background(), auth, stubTransact)
+			if tc.expErr {
+				require.Error(t, err, "Send should raise an error")
+			} else {
+				require.NoError(t, err, "Error during Send")
+				require.Equal(t, tc.expNonce, tx.Nonce(), "Incorrect transaction nonce")
+			}
+
+			next, err := manager.Next(context.Background(), auth.From)
+			require.NoError(t, err, "Error during Next")
+			require.Equal(t, tc.expNext, next, "Incorrect next nonce")
+			require.Nil(t, auth.Nonce, "Send should not modify the given options")
+		})
+	}
+}
+
+// Test NonceManager pipelining
+// Checks that many token transfers can be sent from one account before any is mined
+func TestNonceManagerPipelining(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(2)
+	require.NoError(t, err, "Error generating private keys")
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock*10, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	_, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+
+	manager := util.NewNonceManager(client)
+	const n = 20
+	for i := 0; i < n; i++ {
+		_, err := manager.Send(context.Background(), auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.Transfer(opts, addresses[1], big.NewInt(1))
+		})
+		require.NoError(t, err, "Error sending transfer %d", i)
+	}
+	client.Commit()
+
+	balance, err := contract.BalanceOf(nil, addresses[1])
+	require.NoError(t, err, "Error getting balance")
+	require.Equal(t, big.NewInt(n), balance, "All transfers should be mined")
+}
@@ -0,0 +1,401 @@
+/** proxy_test.go contains TDD ( Test Driven Development ) style tests for the proxy
+  helpers in scripts/utils/proxy.go, the storage layout check in
+  scripts/utils/storage_layout.go, and the deploy -proxy, proxy, upgrade and
+  change-admin commands of tokencli. Proxies are deployed from the OpenZeppelin
+  artifacts in contract/build, in front of TokenUpgradeable, and the storage layouts
+  are those compiled into contract/build.
+*/
+
+package tests
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Test ParseProxyKind
+// Checks that only the supported proxy kinds are accepted
+func TestParseProxyKind(t *testing.T) {
+	testcases := []struct {
+		name    string
+		value   string
+		kind    util.ProxyKind
+		isValid bool
+	}{
+		{"UUPS", "uups", util.ProxyUUPS, true},
+		{"Transparent", "Transparent", util.ProxyTransparent, true},
+		{"Unknown kind", "beacon", "", false},
+		{"Empty kind", "", "", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			kind, err := util.ParseProxyKind(tc.value)
+			if !tc.isValid {
+				require.Error(t, err, "Invalid inputs should return an error")
+				return
+			}
+			require.NoError(t, err, "Error during ParseProxyKind")
+			require.Equal(t, tc.kind, kind, "Incorrect proxy kind")
+		})
+	}
+}
+
+// Test Proxy
+// Checks that the OpenZeppelin proxies of contract/build delegate calls to a
+// TokenUpgradeable implementation, with the state initialised through the proxy, and
+// that only the admin of a transparent proxy, or the owner of a UUPS proxy, can
+// upgrade it without losing the balances
+func TestProxy(t *testing.T) {
+	testcases := []struct {
+		name     string
+		kind     util.ProxyKind
+		admin    string
+		owner    string
+		upgrader string
+	}{
+		// Without an owner, only the admin upgrades a transparent proxy
+		{"Transparent", util.ProxyTransparent, "admin", "", "admin"},
+		{"UUPS", util.ProxyUUPS, "", "deployer", "deployer"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := testUtil.NewHarness("deployer", "admin", "alice")
+			require.NoError(t, err, "Error starting harness")
+			defer h.Close()
+			ctx := context.Background()
+
+			proxyArtifact, err := util.ReadArtifact(buildDir, util.ProxyArtifacts[tc.kind])
+			require.NoError(t, err, "Error reading proxy artifact")
+			implementation, _, err := testUtil.Deploy(h, "deployer", testUtil.DeployTokenUpgradeable)
+			require.NoError(t, err, "Error deploying implementation")
+			upgraded, _, err := testUtil.Deploy(h, "deployer", testUtil.DeployTokenUpgradeable)
+			require.NoError(t, err, "Error deploying upgraded implementation")
+
+			params := util.DefaultTokenParams
+			params.Holders = []common.Address{h.Address("alice")}
+			var owner common.Address
+			if tc.owner != "" {
+				owner = h.Address(tc.owner)
+			}
+			initData, err := testUtil.TokenInitializer(params, owner)
+			require.NoError(t, err, "Error packing initializer")
+
+			var admin common.Address
+			if tc.admin != "" {
+				admin = h.Address(tc.admin)
+			}
+			proxy, tx, err := util.DeployProxy(h.Auth("deployer"), h.Backend, tc.kind, proxyArtifact, implementation, admin, initData)
+			_, err = h.Commit(tx, err)
+			require.NoError(t, err, "Error deploying proxy")
+
+			info, err := util.ReadProxy(ctx, h.Backend, proxy)
+			require.NoError(t, err, "Error during ReadProxy")
+			require.Equal(t, util.ProxyInfo{Implementation: implementation, Admin: admin}, *info, "Incorrect proxy slots")
+			_, err = util.ReadProxy(ctx, h.Backend, implementation)
+			require.ErrorIs(t, err, util.ErrNotProxy, "Implementations are not proxies")
+
+			// Calls are delegated to the implementation, with the state of the proxy
+			proxied, err := token.NewToken(proxy, h.Backend)
+			require.NoError(t, err, "Error binding proxy")
+			caller := &bind.CallOpts{From: h.Address("alice")}
+			name, err := proxied.Name(caller)
+			require.NoError(t, err, "Error calling through proxy")
+			require.Equal(t, params.Name, name, "Incorrect name through proxy")
+			balance, err := proxied.BalanceOf(caller, h.Address("alice"))
+			require.NoError(t, err, "Error calling through proxy")
+			require.Equal(t, params.InitialSupply, balance, "The holder should hold the initial supply")
+			if tc.kind == util.ProxyTransparent {
+				_, err = proxied.Decimals(&bind.CallOpts{From: h.Address("admin")})
+				require.Error(t, err, "The admin of a transparent proxy cannot call the implementation")
+			}
+
+			// Neither the proxy nor the implementation can be initialised again
+			upgradeableABI, err := util.LoadABI(buildDir, util.TokenUpgradeableArtifact)
+			require.NoError(t, err, "Error loading TokenUpgradeable ABI")
+			for _, address := range []common.Address{proxy, implementation} {
+				contract := bind.NewBoundContract(address, *upgradeableABI, h.Backend, h.Backend, h.Backend)
+				_, err = h.Commit(contract.Transact(h.Auth("alice"), "initialize", params.Name, params.Symbol, params.Decimals, params.InitialSupply, []common.Address{h.Address("alice")}, h.Address("alice")))
+				require.Error(t, err, "Initialised contracts should not be initialised again")
+			}
+
+			// Only the admin, or the owner, can upgrade
+			for _, other := range []string{"alice", "deployer"} {
+				if other != tc.upgrader {
+					_, err = h.Commit(util.UpgradeProxy(h.Auth(other), h.Backend, proxy, upgraded, nil))
+					require.Error(t, err, "Only the %s should upgrade the proxy", tc.upgrader)
+				}
+			}
+			_, err = h.Commit(util.UpgradeProxy(h.Auth(tc.upgrader), h.Backend, proxy, upgraded, nil))
+			require.NoError(t, err, "Error upgrading proxy")
+
+			info, err = util.ReadProxy(ctx, h.Backend, proxy)
+			require.NoError(t, err, "Error during ReadProxy")
+			require.Equal(t, util.ProxyInfo{Implementation: upgraded, Admin: admin}, *info, "Incorrect proxy slots after upgrade")
+			balance, err = proxied.BalanceOf(caller, h.Address("alice"))
+			require.NoError(t, err, "Error calling through proxy")
+			require.Equal(t, params.InitialSupply, balance, "The balances should be kept by the upgrade")
+		})
+	}
+
+	t.Run("UUPS upgrade to a contract without upgrade function", func(t *testing.T) {
+		h, err := testUtil.NewHarness("deployer", "alice")
+		require.NoError(t, err, "Error starting harness")
+		defer h.Close()
+
+		proxyArtifact, err := util.ReadArtifact(buildDir, util.ProxyArtifacts[util.ProxyUUPS])
+		require.NoError(t, err, "Error reading proxy artifact")
+		implementation, _, err := testUtil.Deploy(h, "deployer", testUtil.DeployTokenUpgradeable)
+		require.NoError(t, err, "Error deploying implementation")
+		tokenAddress, _, err := testUtil.Deploy(h, "deployer", testUtil.DeployToken)
+		require.NoError(t, err, "Error deploying Token")
+		initData, err := testUtil.TokenInitializer(util.DefaultTokenParams, h.Address("deployer"))
+		require.NoError(t, err, "Error packing initializer")
+
+		proxy, tx, err := util.DeployProxy(h.Auth("deployer"), h.Backend, util.ProxyUUPS, proxyArtifact, implementation, common.Address{}, initData)
+		_, err = h.Commit(tx, err)
+		require.NoError(t, err, "Error deploying proxy")
+
+		// The proxy could not be upgraded again, so the upgrade is refused
+		_, err = h.Commit(util.UpgradeProxy(h.Auth("deployer"), h.Backend, proxy, tokenAddress, nil))
+		require.Error(t, err, "Implementations that are not UUPS should be refused")
+	})
+
+	t.Run("Transparent admin change", func(t *testing.T) {
+		h, err := testUtil.NewHarness("deployer", "admin", "alice")
+		require.NoError(t, err, "Error starting harness")
+		defer h.Close()
+
+		proxyArtifact, err := util.ReadArtifact(buildDir, util.ProxyArtifacts[util.ProxyTransparent])
+		require.NoError(t, err, "Error reading proxy artifact")
+		implementation, _, err := testUtil.Deploy(h, "deployer", testUtil.DeployTokenUpgradeable)
+		require.NoError(t, err, "Error deploying implementation")
+		initData, err := testUtil.TokenInitializer(util.DefaultTokenParams, h.Address("deployer"))
+		require.NoError(t, err, "Error packing initializer")
+
+		proxy, tx, err := util.DeployProxy(h.Auth("deployer"), h.Backend, util.ProxyTransparent, proxyArtifact, implementation, h.Address("admin"), initData)
+		_, err = h.Commit(tx, err)
+		require.NoError(t, err, "Error deploying proxy")
+
+		// The admin is changed by the current admin
+		_, err = h.Commit(util.ChangeProxyAdmin(h.Auth("alice"), h.Backend, proxy, h.Address("alice")))
+		require.Error(t, err, "Only the admin should change the admin")
+		_, err = h.Commit(util.ChangeProxyAdmin(h.Auth("admin"), h.Backend, proxy, h.Address("alice")))
+		require.NoError(t, err, "Error changing admin")
+
+		info, err := util.ReadProxy(context.Background(), h.Backend, proxy)
+		require.NoError(t, err, "Error during ReadProxy")
+		require.Equal(t, util.ProxyInfo{Implementation: implementation, Admin: h.Address("alice")}, *info, "Incorrect proxy slots after admin change")
+	})
+}
+
+// Test CheckStorageUpgrade
+// Checks that appending state variables is allowed, and that removing, moving,
+// renaming or retyping them is rejected
+func TestCheckStorageUpgrade(t *testing.T) {
+	old, err := util.LoadStorageLayout(buildDir, "Token")
+	require.NoError(t, err, "Error loading storage layout")
+	require.Len(t, old.Storage, 5, "Incorrect number of state variables")
+
+	_, err = util.LoadStorageLayout(buildDir, "Missing")
+	require.Error(t, err, "Missing layouts should return an error")
+
+	// change returns a copy of the old layout, changed by fn
+	change := func(fn func(l *util.StorageLayout)) *util.StorageLayout {
+		changed, err := util.LoadStorageLayout(buildDir, "Token")
+		require.NoError(t, err, "Error loading storage layout")
+		fn(changed)
+		return changed
+	}
+
+	testcases := []struct {
+		name    string
+		layout  *util.StorageLayout
+		isValid bool
+	}{
+		{"Same layout", old, true},
+		{"Appended variable", change(func(l *util.StorageLayout) {
+			l.Storage = append(l.Storage, util.StorageVariable{Label: "_cap", Slot: "5", Type: "t_uint256"})
+		}), true},
+		{"Recompiled with other AST ids", change(func(l *util.StorageLayout) {
+			l.Types["t_uint256_1"] = l.Types["t_uint256"]
+			l.Storage[2].Type = "t_uint256_1"
+		}), true},
+		{"Removed variable", change(func(l *util.StorageLayout) {
+			l.Storage = l.Storage[:4]
+		}), false},
+		{"Inserted variable", change(func(l *util.StorageLayout) {
+			inserted := util.StorageVariable{Label: "_owner", Slot: "0", Type: "t_address"}
+			for i := range l.Storage {
+				l.Storage[i].Slot = string(rune('1' + i))
+			}
+			l.Storage = append([]util.StorageVariable{inserted}, l.Storage...)
+		}), false},
+		{"Renamed variable", change(func(l *util.StorageLayout) {
+			l.Storage[2].Label = "_supply"
+		}), false},
+		{"Changed type", change(func(l *util.StorageLayout) {
+			l.Storage[2].Type = "t_address"
+		}), false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := util.CheckStorageUpgrade(old, tc.layout)
+			if !tc.isValid {
+				require.Error(t, err, "Incompatible layouts should return an error")
+				return
+			}
+			require.NoError(t, err, "Error during CheckStorageUpgrade")
+		})
+	}
+}
+
+// proxyDeployResult is the JSON output of a deployment by tokencli deploy -proxy
+type proxyDeployResult struct {
+	Address        string            `json:"address"`
+	Proxy          string            `json:"proxy"`
+	Implementation string            `json:"implementation"`
+	Token          *util.TokenParams `json:"token"`
+}
+
+// proxyInfoResult is the JSON output of tokencli proxy
+type proxyInfoResult struct {
+	Proxy          string         `json:"proxy"`
+	Implementation common.Address `json:"implementation"`
+	Admin          common.Address `json:"admin"`
+}
+
+// Test deploy -proxy and upgrade
+// Checks that tokencli deploys TokenUpgradeable behind each kind of proxy with the
+// Token parameters, shows its slots, refuses an upgrade to an incompatible storage
+// layout, and upgrades it to a new implementation without losing the balances
+func TestDeployProxy(t *testing.T) {
+	h, profile := startLocalNode(t)
+	cli, err := testUtil.NewTokencli(t.TempDir(), h, profile)
+	require.NoError(t, err, "Error building tokencli")
+	ctx := context.Background()
+
+	testcases := []struct {
+		name     string
+		kind     util.ProxyKind
+		admin    string
+		upgrader string
+		other    string
+	}{
+		{"TokenUUPS", util.ProxyUUPS, "", "deployer", "alice"},
+		{"TokenTransparent", util.ProxyTransparent, "alice", "alice", "deployer"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []string{"-name", tc.name, "-proxy", string(tc.kind), "-token-name", "Upgradeable Token", "-symbol", "UPG", "-supply", "500"}
+			var admin common.Address
+			if tc.admin != "" {
+				admin = h.Address(tc.admin)
+				args = append(args, "-proxy-admin", admin.Hex())
+			}
+			var result proxyDeployResult
+			require.NoError(t, cli.Run("deployer", &result, "deploy", args...), "Error deploying with -proxy")
+			require.Equal(t, string(tc.kind), result.Proxy, "Incorrect proxy kind")
+
+			proxy := common.HexToAddress(result.Address)
+			proxied, err := token.NewToken(proxy, h.Backend)
+			require.NoError(t, err, "Error binding proxy")
+			supply := new(big.Int).Mul(big.NewInt(500), testUtil.Ten18)
+			symbol, err := proxied.Symbol(nil)
+			require.NoError(t, err, "Error calling through proxy")
+			require.Equal(t, "UPG", symbol, "Incorrect symbol through proxy")
+			balance, err := proxied.BalanceOf(nil, h.Address("deployer"))
+			require.NoError(t, err, "Error calling through proxy")
+			require.Equal(t, supply, balance, "The deployer should hold the initial supply")
+
+			var info proxyInfoResult
+			require.NoError(t, cli.Run("", &info, "proxy", "-contract", tc.name), "Error showing proxy")
+			require.Equal(t, common.HexToAddress(result.Implementation), info.Implementation, "Incorrect implementation")
+			require.Equal(t, admin, info.Admin, "Incorrect admin")
+
+			manifest, err := util.LoadManifest(cli.Manifest)
+			require.NoError(t, err, "Error loading manifest")
+			deployment, err := manifest.Lookup(profile.Name, tc.name)
+			require.NoError(t, err, "Proxy should be recorded in the manifest")
+			require.Equal(t, proxy, deployment.Address, "Manifest should record the proxy")
+			require.Equal(t, "Upgradeable Token", deployment.Token.Name, "Manifest should record the Token parameters")
+			_, err = manifest.Lookup(profile.Name, tc.name+"Implementation")
+			require.NoError(t, err, "Implementation should be recorded in the manifest")
+
+			// A new implementation, deployed on its own
+			next := tc.name + "V2"
+			require.NoError(t, cli.Run("deployer", nil, "deploy", "-name", next, "-artifact", util.TokenUpgradeableArtifact), "Error deploying new implementation")
+
+			// Token drops the state variables of TokenUpgradeable
+			err = cli.Run(tc.upgrader, nil, "upgrade", "-contract", tc.name, "-implementation", next, "-old-layout", util.TokenUpgradeableArtifact, "-new-layout", "Token")
+			require.ErrorContains(t, err, "-skip-layout-check", "Incompatible layouts should be refused")
+
+			upgrade := []string{"-contract", tc.name, "-implementation", next, "-old-layout", util.TokenUpgradeableArtifact, "-new-layout", util.TokenUpgradeableArtifact}
+			err = cli.Run(tc.other, nil, "upgrade", upgrade...)
+			require.ErrorContains(t, err, "failed to upgrade", "Only the %s should upgrade the proxy", tc.upgrader)
+			require.NoError(t, cli.Run(tc.upgrader, nil, "upgrade", upgrade...), "Error upgrading proxy")
+
+			require.NoError(t, cli.Run("", &info, "proxy", "-contract", tc.name), "Error showing proxy")
+			nextDeployment, err := util.LoadManifest(cli.Manifest)
+			require.NoError(t, err, "Error loading manifest")
+			implementation, err := nextDeployment.Lookup(profile.Name, next)
+			require.NoError(t, err, "New implementation should be recorded in the manifest")
+			require.Equal(t, implementation.Address, info.Implementation, "Proxy should use the new implementation")
+			balance, err = proxied.BalanceOf(nil, h.Address("deployer"))
+			require.NoError(t, err, "Error calling through proxy")
+			require.Equal(t, supply, balance, "The balances should be kept by the upgrade")
+
+			if tc.kind == util.ProxyTransparent {
+				other := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+				err = cli.Run(tc.other, nil, "change-admin", "-contract", tc.name, "-admin", other.Hex())
+				require.ErrorContains(t, err, "only the admin", "Only the admin should change the admin")
+				require.NoError(t, cli.Run(tc.admin, nil, "change-admin", "-contract", tc.name, "-admin", other.Hex()), "Error changing admin")
+				require.NoError(t, cli.Run("", &info, "proxy", "-contract", tc.name), "Error showing proxy")
+				require.Equal(t, other, info.Admin, "Incorrect admin after change")
+			}
+		})
+	}
+
+	// Invalid deployments are refused before anything is sent
+	incomplete := t.TempDir()
+	for _, ext := range []string{".abi", ".bin"} {
+		data, err := os.ReadFile(filepath.Join(buildDir, util.TokenUpgradeableArtifact+ext))
+		require.NoError(t, err, "Error reading artifact")
+		require.NoError(t, os.WriteFile(filepath.Join(incomplete, util.TokenUpgradeableArtifact+ext), data, 0o644), "Error writing artifact")
+	}
+	invalid := []struct {
+		name   string
+		args   []string
+		expErr string
+	}{
+		{"Missing proxy artifact", []string{"-proxy", "uups", "-build-dir", incomplete}, "failed to read the uups proxy"},
+		{"Transparent admin holding the token", []string{"-proxy", "transparent"}, "cannot call the token"},
+		{"Unknown initializer", []string{"-proxy", "uups", "-artifact", util.TokenUpgradeableArtifact, "setUp"}, "setUp"},
+		{"Salt", []string{"-proxy", "uups", "-salt", "token-v1"}, "-salt and -proxy cannot be combined"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			nonce, err := h.Backend.PendingNonceAt(ctx, h.Address("deployer"))
+			require.NoError(t, err, "Error getting nonce")
+			err = cli.Run("deployer", nil, "deploy", append([]string{"-name", "Invalid"}, tc.args...)...)
+			require.ErrorContains(t, err, tc.expErr, "Incorrect deploy error")
+			after, err := h.Backend.PendingNonceAt(ctx, h.Address("deployer"))
+			require.NoError(t, err, "Error getting nonce")
+			require.Equal(t, nonce, after, "Nothing should be sent")
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/** receipt_test.go contains TDD ( Test Driven Development ) style tests for the
+  receipt waiting helpers in scripts/utils/receipt.go, using a simulated backend.
+*/
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// deployTokenForReceipts deploys a Token contract on a new simulated backend,
+// without committing the deployment
+func deployTokenForReceipts(t *testing.T) (*backends.SimulatedBackend, *types.Transaction) {
+	privKeys, _, err := testUtil.GeneratePrivKeysAndAddresses(1)
+	require.NoError(t, err, "Error generating private key")
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+
+	_, tx, _, err := testUtil.DeployToken(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+
+	return client, tx
+}
+
+// Test WaitForReceipt
+// Checks that receipts are returned once mined with the required confirmations,
+// in both polling and subscription modes
+func TestWaitForReceipt(t *testing.T) {
+	testcases := []struct {
+		name          string
+		mode          util.WaitMode
+		confirmations uint64
+		commits       int
+	}{
+		{
+			"Poll for mined receipt",
+			util.WaitPoll,
+			1,
+			1,
+		},
+		{
+			"Poll for confirmed receipt",
+			util.WaitPoll,
+			3,
+			3,
+		},
+		{
+			"Subscribe for mined receipt",
+			util.WaitSubscribe,
+			1,
+			1,
+		},
+		{
+			"Subscribe for confirmed receipt",
+			util.WaitSubscribe,
+			3,
+			3,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, tx := deployTokenForReceipts(t)
+			defer client.Close()
+
+			// Mine the required blocks while waiting
+			commits := tc.commits
+			go func() {
+				for i := 0; i < commits; i++ {
+					time.Sleep(20 * time.Millisecond)
+					client.Commit()
+				}
+			}()
+
+			opts := util.WaitOptions{
+				Mode:          tc.mode,
+				Timeout:       5 * time.Second,
+				PollInterval:  10 * time.Millisecond,
+				Confirmations: tc.confirmations,
+			}
+			receipt, err := util.WaitForReceipt(context.Background(), client, tx.Hash(), opts)
+			require.NoError(t, err, "Error during WaitForReceipt")
+			require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "Incorrect receipt status")
+			require.Equal(t, big.NewInt(1), receipt.BlockNumber, "Incorrect receipt block")
+
+			head, err := client.HeaderByNumber(context.Background(), nil)
+			require.NoError(t, err, "Error getting latest header")
+			require.GreaterOrEqual(t, head.Number.Uint64()+1-receipt.BlockNumber.Uint64(), tc.confirmations, "Receipt returned before required confirmations")
+		})
+	}
+}
+
+// Test WaitForReceipt timeout
+// Checks that waiting for a transaction that is never mined times out
+func TestWaitForReceiptTimeout(t *testing.T) {
+	client, tx := deployTokenForReceipts(t)
+	defer client.Close()
+
+	opts := util.WaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond, Confirmations: 1}
+	_, err := util.WaitForReceipt(context.Background(), client, tx.Hash(), opts)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "WaitForReceipt should time out")
+}
+
+// Test WaitForReceipt reverted status
+// Checks that a reverted transaction returns its receipt together with ErrTxReverted
+func TestWaitForReceiptReverted(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(2)
+	require.NoError(t, err, "Error generating private keys")
+
+	client, auth, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	_, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract")
+
+	// A fixed gas limit skips gas estimation, so the failing transfer is mined
+	auth.GasLimit = 100000
+	tx, err := contract.Transfer(auth, addresses[1], new(big.Int).Mul(big.NewInt(1000), testUtil.Ten18))
+	require.NoError(t, err, "Error sending transfer")
+	client.Commit()
+
+	opts := util.WaitOptions{Timeout: time.Second, PollInterval: 10 * time.Millisecond, Confirmations: 1}
+	receipt, err := util.WaitForReceipt(context.Background(), client, tx.Hash(), opts)
+	require.True(t, errors.Is(err, util.ErrTxReverted), "WaitForReceipt should raise ErrTxReverted")
+	require.NotNil(t, receipt, "Reverted receipt should be returned")
+	require.Equal(t, "reverted", util.ReceiptStatus(receipt), "Incorrect receipt status")
+}
+
+// Test GetReceiptBackend
+// Checks that the websocket client dialled in subscribe mode is closed by the returned
+// function, while the HTTP client of poll mode is left open
+func TestGetReceiptBackend(t *testing.T) {
+	_, profile := startLocalNode(t)
+	client, err := util.GetClient(profile)
+	require.NoError(t, err, "Error getting client")
+	defer client.Close()
+
+	testcases := []struct {
+		name   string
+		mode   util.WaitMode
+		closed bool
+	}{
+		{"Poll mode uses the HTTP client", util.WaitPoll, false},
+		{"Subscribe mode closes its websocket client", util.WaitSubscribe, true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := util.DefaultWaitOptions
+			opts.Mode = tc.mode
+			backend, closeBackend, err := util.GetReceiptBackend(profile, client, opts)
+			require.NoError(t, err, "Error during GetReceiptBackend")
+
+			_, err = backend.HeaderByNumber(context.Background(), nil)
+			require.NoError(t, err, "Error getting the latest header")
+
+			closeBackend()
+			_, err = backend.HeaderByNumber(context.Background(), nil)
+			if tc.closed {
+				require.ErrorIs(t, err, rpc.ErrClientQuit, "The backend should be closed")
+				return
+			}
+			require.NoError(t, err, "The client should be left open")
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/** revert_test.go contains TDD ( Test Driven Development ) style tests for the revert
+  reason decoding in scripts/utils/revert.go, and the replay of reverted transactions
+  against the in-process JSON-RPC server.
+*/
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// revertData returns the revert data of the error with the given signature and arguments
+func revertData(t *testing.T, signature string, types []string, values ...interface{}) []byte {
+	args := make(abi.Arguments, len(types))
+	for i, name := range types {
+		argType, err := abi.NewType(name, "", nil)
+		require.NoError(t, err, "Error parsing type %s", name)
+		args[i] = abi.Argument{Type: argType}
+	}
+	packed, err := args.Pack(values...)
+	require.NoError(t, err, "Error packing %s", signature)
+	return append(crypto.Keccak256([]byte(signature))[:4], packed...)
+}
+
+// Test DecodeRevertReason
+// Checks that Error(string) reasons, Panic(uint256) codes and custom errors of the
+// ABIs are decoded, and that other data is reported as undecodable
+func TestDecodeRevertReason(t *testing.T) {
+	customABI, err := abi.JSON(strings.NewReader(`[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`))
+	require.NoError(t, err, "Error parsing custom error ABI")
+	buildABIs, err := util.LoadBuildABIs("../" + util.DefaultBuildDir)
+	require.NoError(t, err, "Error loading build ABIs")
+	abis := append(buildABIs, &customABI)
+
+	testcases := []struct {
+		name   string
+		data   []byte
+		reason string
+		err    string
+	}{
+		{"Error string", revertData(t, "Error(string)", []string{"string"}, "ERC20: transfer amount exceeds balance"), "ERC20: transfer amount exceeds balance", ""},
+		{"Arithmetic panic", revertData(t, "Panic(uint256)", []string{"uint256"}, big.NewInt(0x11)), "panic: arithmetic underflow or overflow (0x11)", ""},
+		{"Unknown panic code", revertData(t, "Panic(uint256)", []string{"uint256"}, big.NewInt(0x99)), "panic: unknown code 0x99", ""},
+		{"Custom error", revertData(t, "InsufficientBalance(uint256,uint256)", []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(2)), "InsufficientBalance(available: 1, required: 2)", ""},
+		{"Unknown custom error", revertData(t, "Unauthorized(address)", []string{"address"}, common.HexToAddress("0x01")), "", "unknown revert data"},
+		{"No revert data", nil, "", "reverted without a reason"},
+		{"Truncated selector", []byte{0x08, 0xc3}, "", "invalid revert data"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, err := util.DecodeRevertReason(tc.data, abis)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err, "Incorrect decoding error")
+				return
+			}
+			require.NoError(t, err, "Error during DecodeRevertReason")
+			require.Equal(t, tc.reason, reason, "Incorrect revert reason")
+		})
+	}
+}
+
+// Test ExplainRevert
+// Checks that gas estimates of reverting transactions, and reverted transactions that
+// are replayed, return their reason with the original error
+func TestExplainRevert(t *testing.T) {
+	h, profile := startLocalNode(t)
+	client, err := util.GetClient(profile)
+	require.NoError(t, err, "Error getting client")
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wait := util.WaitOptions{Mode: util.WaitPoll, Timeout: 5 * time.Second, PollInterval: 10 * time.Millisecond, Confirmations: 1}
+	deployer := util.NewKeySigner(h.Account("deployer").Key)
+	exceedsBalance := "ERC20: transfer amount exceeds balance"
+
+	auth, err := util.GetAuth(client, deployer, util.DefaultFeeOptions)
+	require.NoError(t, err, "Error during GetAuth")
+	_, tx, contract, err := testUtil.DeployToken(auth, client)
+	require.NoError(t, err, "Error deploying Token")
+	_, err = util.WaitForReceipt(ctx, client, tx.Hash(), wait)
+	require.NoError(t, err, "Error waiting for deployment")
+	tooMuch := new(big.Int).Mul(testUtil.Ten18, big.NewInt(1000))
+
+	// The gas estimate of the transfer returns its revert data
+	auth, err = util.GetAuth(client, deployer, util.DefaultFeeOptions)
+	require.NoError(t, err, "Error during GetAuth")
+	_, err = contract.Transfer(auth, h.Address("alice"), tooMuch)
+	err = util.ExplainRevert(err, nil)
+	var revertErr *util.RevertError
+	require.True(t, errors.As(err, &revertErr), "Expected a revert error, got %v", err)
+	require.Equal(t, exceedsBalance, revertErr.Reason, "Incorrect reason of gas estimate")
+	require.Equal(t, 1, strings.Count(err.Error(), exceedsBalance), "The reason should be in the error once: %v", err)
+	require.Equal(t, err, util.ExplainRevert(err, nil), "Explained errors should be returned as they are")
+
+	testcases := []struct {
+		name     string
+		gasLimit uint64
+		reason   string
+	}{
+		{"Replayed revert", 100000, exceedsBalance},
+		{"Out of gas", 25000, "out of gas"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// A fixed gas limit skips the gas estimate, so the transfer is mined
+			auth, err := util.GetAuth(client, deployer, util.DefaultFeeOptions)
+			require.NoError(t, err, "Error during GetAuth")
+			auth.GasLimit = tc.gasLimit
+			tx, err := contract.Transfer(auth, h.Address("alice"), tooMuch)
+			require.NoError(t, err, "Error sending transfer")
+
+			receipt, err := util.WaitForReceipt(ctx, client, tx.Hash(), wait)
+			require.ErrorIs(t, err, util.ErrTxReverted, "The transfer should revert")
+
+			err = util.ExplainRevertedTx(ctx, client, tx, receipt, err, nil)
+			require.ErrorIs(t, err, util.ErrTxReverted, "The revert error should wrap the original error")
+			require.True(t, errors.As(err, &revertErr), "Expected a revert error, got %v", err)
+			require.Equal(t, tc.reason, revertErr.Reason, "Incorrect reason of reverted transaction")
+			require.Contains(t, err.Error(), tx.Hash().Hex(), "The error should name the transaction")
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/** rpc_server_test.go contains TDD ( Test Driven Development ) style tests for the
+  in-process JSON-RPC server in tests/test_utils/rpc_server.go, through an
+  ethclient.Client and the Token bindings, as the tokencli uses them against a node.
+*/
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Test RPCServer
+// Checks that the Token can be deployed, called and transferred over JSON-RPC, that
+// receipts and logs are served by the hash of the transaction sent, and that reverts
+// return their data
+func TestRPCServer(t *testing.T) {
+	h, profile := startLocalNode(t)
+	client, err := util.GetClient(profile)
+	require.NoError(t, err, "Error getting client")
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wait := util.WaitOptions{Mode: util.WaitPoll, Timeout: 5 * time.Second, PollInterval: 10 * time.Millisecond, Confirmations: 1}
+
+	for _, mode := range []string{util.FeeModeDynamic, util.FeeModeLegacy} {
+		t.Run(mode, func(t *testing.T) {
+			fees := util.DefaultFeeOptions
+			fees.Mode = mode
+			deployer := util.NewKeySigner(h.Account("deployer").Key)
+
+			auth, err := util.GetAuth(client, deployer, fees)
+			require.NoError(t, err, "Error during GetAuth")
+			address, tx, contract, err := testUtil.DeployToken(auth, client)
+			require.NoError(t, err, "Error deploying Token")
+			receipt, err := util.WaitForReceipt(ctx, client, tx.Hash(), wait)
+			require.NoError(t, err, "Error waiting for deployment")
+			require.Equal(t, address, receipt.ContractAddress, "Incorrect contract address")
+
+			auth, err = util.GetAuth(client, deployer, fees)
+			require.NoError(t, err, "Error during GetAuth")
+			tx, err = contract.Transfer(auth, h.Address("alice"), testUtil.Ten18)
+			require.NoError(t, err, "Error sending transfer")
+			receipt, err = util.WaitForReceipt(ctx, client, tx.Hash(), wait)
+			require.NoError(t, err, "Error waiting for transfer")
+			require.Equal(t, tx.Hash(), receipt.TxHash, "Incorrect receipt")
+
+			balance, err := contract.BalanceOf(&bind.CallOpts{Context: ctx}, h.Address("alice"))
+			require.NoError(t, err, "Error calling balanceOf")
+			require.Equal(t, 0, testUtil.Ten18.Cmp(balance), "Incorrect balance %v", balance)
+
+			start := receipt.BlockNumber.Uint64()
+			transfers, err := contract.FilterTransfer(&bind.FilterOpts{Start: start, End: &start, Context: ctx}, nil, nil)
+			require.NoError(t, err, "Error filtering transfers")
+			require.True(t, transfers.Next(), "Missing Transfer event")
+			require.Equal(t, h.Address("alice"), transfers.Event.To, "Incorrect Transfer event")
+			require.False(t, transfers.Next(), "Unexpected Transfer event")
+
+			// The transfer of more than the supply reverts
+			data, err := testUtil.GetCallData("transfer", h.Address("alice"), new(big.Int).Mul(testUtil.Ten18, big.NewInt(1000)))
+			require.NoError(t, err, "Error packing transfer")
+			_, err = client.CallContract(ctx, ethereum.CallMsg{From: h.Address("deployer"), To: &address, Data: data}, nil)
+			var dataErr rpc.DataError
+			require.True(t, errors.As(err, &dataErr), "Reverts should return their data, got %v", err)
+			require.NotEmpty(t, dataErr.ErrorData(), "Missing revert data")
+			_, err = client.EstimateGas(ctx, ethereum.CallMsg{From: h.Address("deployer"), To: &address, Data: data})
+			require.Error(t, err, "Reverting calls should not be estimated")
+		})
+	}
+
+	_, err = client.TransactionReceipt(ctx, [32]byte{1})
+	require.ErrorIs(t, err, ethereum.NotFound, "Unknown transactions should not be found")
+
+	// Transactions signed for the chain ID of the backend, not the server, are refused
+	nonce, err := client.PendingNonceAt(ctx, h.Address("deployer"))
+	require.NoError(t, err, "Error getting nonce")
+	tx, err := h.Auth("deployer").Signer(h.Address("deployer"), types.NewTransaction(nonce, h.Address("alice"), big.NewInt(1), 21000, big.NewInt(params.GWei), nil))
+	require.NoError(t, err, "Error signing transaction")
+	require.Error(t, client.SendTransaction(ctx, tx), "Transactions for another chain ID should be refused")
+}
@@ -0,0 +1,167 @@
+/** signer_test.go contains TDD ( Test Driven Development ) style tests for the
+  Signer backends in scripts/utils/signer.go and scripts/utils/keyring.go.
+  The keyring fixtures in testdata/keyring were written by the evmosd keyring for
+  the well-known "test ... junk" development mnemonic.
+*/
+
+package tests
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Address of the key stored in the keyring fixtures
+var keyringFixtureAddress = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+// staticPassphrase returns a PassphraseFunc that always returns the given passphrase
+func staticPassphrase(pass string) util.PassphraseFunc {
+	return func(string) (string, error) { return pass, nil }
+}
+
+// Test NewKeyringSigner
+// Checks that keys are read from the evmosd test and file keyring backends
+func TestNewKeyringSigner(t *testing.T) {
+	home := filepath.Join("testdata", "keyring")
+
+	testcases := []struct {
+		name       string
+		backend    string
+		key        string
+		passphrase string
+		expErr     bool
+	}{
+		{
+			"Test backend",
+			util.KeyringBackendTest,
+			"mykey",
+			"",
+			false,
+		},
+		{
+			"File backend",
+			util.KeyringBackendFile,
+			"mykey",
+			"password123",
+			false,
+		},
+		{
+			"File backend with wrong passphrase",
+			util.KeyringBackendFile,
+			"mykey",
+			"wrong",
+			true,
+		},
+		{
+			"Missing key",
+			util.KeyringBackendTest,
+			"mykey2",
+			"",
+			true,
+		},
+		{
+			"Unsupported backend",
+			"os",
+			"mykey",
+			"",
+			true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			signer, err := util.NewKeyringSigner(home, tc.backend, tc.key, staticPassphrase(tc.passphrase))
+			if tc.expErr {
+				require.Error(t, err, "NewKeyringSigner should raise an error")
+				return
+			}
+			require.NoError(t, err, "Error during NewKeyringSigner")
+			require.Equal(t, keyringFixtureAddress, signer.Address(), "Incorrect keyring address")
+		})
+	}
+}
+
+// Test NewKeystoreSigner
+// Checks that keystore files are decrypted with a passphrase file
+func TestNewKeystoreSigner(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(1)
+	require.NoError(t, err, "Error generating private key")
+
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(privKeys[0], "secret")
+	require.NoError(t, err, "Error writing keystore")
+
+	passFile := filepath.Join(dir, "pass.txt")
+	require.NoError(t, os.WriteFile(passFile, []byte("secret\n"), 0o600), "Error writing passphrase file")
+
+	signer, err := util.NewKeystoreSigner(account.URL.Path, util.PassphraseFromFile(passFile))
+	require.NoError(t, err, "Error during NewKeystoreSigner")
+	require.Equal(t, addresses[0], signer.Address(), "Incorrect keystore address")
+
+	_, err = util.NewKeystoreSigner(account.URL.Path, staticPassphrase("wrong"))
+	require.Error(t, err, "NewKeystoreSigner should raise an error with a wrong passphrase")
+}
+
+// Test SignerOptions
+// Checks that the insecure hex signer must be selected explicitly, and reads its key
+// from the environment
+func TestSignerOptions(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(1)
+	require.NoError(t, err, "Error generating private key")
+	t.Setenv("TEST_SIGNER_KEY", common.Bytes2Hex(crypto.FromECDSA(privKeys[0])))
+
+	opts := util.DefaultSignerOptions
+	opts.KeyringHome = filepath.Join("testdata", "keyring")
+	signer, err := opts.NewSigner()
+	require.NoError(t, err, "Error loading default keyring signer")
+	require.Equal(t, keyringFixtureAddress, signer.Address(), "Incorrect keyring address")
+
+	opts.Backend = util.SignerInsecureHex
+	opts.KeyEnv = "TEST_SIGNER_KEY"
+	signer, err = opts.NewSigner()
+	require.NoError(t, err, "Error loading insecure hex signer")
+	require.Equal(t, addresses[0], signer.Address(), "Incorrect hex key address")
+
+	opts.Backend = "unknown"
+	_, err = opts.NewSigner()
+	require.Error(t, err, "NewSigner should raise an error for an unknown backend")
+}
+
+// Test NewTransactOpts
+// Checks that transactions signed through a Signer are accepted by a simulated backend
+func TestNewTransactOpts(t *testing.T) {
+	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(2)
+	require.NoError(t, err, "Error generating private keys")
+
+	client, _, err := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock, testUtil.TestChainID)
+	require.NoError(t, err, "Error getting simulated backend")
+	defer client.Close()
+
+	auth := util.NewTransactOpts(util.NewKeySigner(privKeys[0]), testUtil.TestChainID)
+	_, _, contract, err := testUtil.DeployContractAndCommit(auth, client)
+	require.NoError(t, err, "Error deploying contract with signer")
+
+	_, err = contract.Transfer(auth, addresses[1], big.NewInt(1))
+	require.NoError(t, err, "Error transferring with signer")
+	client.Commit()
+
+	balance, err := contract.BalanceOf(nil, addresses[1])
+	require.NoError(t, err, "Error getting balance")
+	require.Equal(t, big.NewInt(1), balance, "Incorrect recipient balance")
+
+	// A signer only signs for its own address
+	auth.From = addresses[1]
+	_, err = auth.Signer(auth.From, nil)
+	require.Error(t, err, "Signer should not sign for another address")
+}
@@ -0,0 +1,145 @@
+/** solc_test.go contains TDD ( Test Driven Development ) style tests for the
+  compile-and-bind pipeline in scripts/utils/solc.go. The committed bindings and
+  artifacts are checked to be in sync with their sources.
+*/
+
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// copyFile copies the file at src to dst, creating the directories of dst
+func copyFile(t *testing.T, src, dst string) {
+	data, err := os.ReadFile(src)
+	require.NoError(t, err, "Error reading %s", src)
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755), "Error creating directory")
+	require.NoError(t, os.WriteFile(dst, data, 0o644), "Error writing %s", dst)
+}
+
+// solcOutput returns the standard JSON output of solc for the committed Token artifacts
+func solcOutput(t *testing.T, errors ...map[string]string) []byte {
+	artifact, err := util.ReadArtifact(buildDir, "Token")
+	require.NoError(t, err, "Error reading Token artifact")
+	source, err := os.ReadFile("../contract/Token.sol")
+	require.NoError(t, err, "Error reading Token source")
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"compiler": map[string]string{"version": "0.8.6+commit.11564f7e"},
+		"sources": map[string]interface{}{
+			"contract/Token.sol": map[string]string{"keccak256": crypto.Keccak256Hash(source).Hex()},
+		},
+	})
+	require.NoError(t, err, "Error encoding metadata")
+
+	output, err := json.Marshal(map[string]interface{}{
+		"errors": errors,
+		"contracts": map[string]interface{}{
+			"contract/Token.sol": map[string]interface{}{
+				"Token": map[string]interface{}{
+					"abi":      json.RawMessage(artifact.ABI),
+					"metadata": string(metadata),
+					"evm":      map[string]interface{}{"bytecode": map[string]string{"object": artifact.Bin}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err, "Error encoding solc output")
+	return output
+}
+
+// Test CheckBuild
+// Checks that the committed binding, artifacts and sources agree, and that changing
+// any of them is detected
+func TestCheckBuild(t *testing.T) {
+	info, err := util.LoadBuildInfo(filepath.Join(buildDir, util.DefaultBuildInfoFile))
+	require.NoError(t, err, "Error loading build info")
+	require.NoError(t, util.CheckBuild("..", "contract/build", info), "Committed build should be in sync, run go run ./scripts/bindgen")
+
+	artifact, err := util.ReadArtifact(buildDir, "Token")
+	require.NoError(t, err, "Error reading Token artifact")
+	version, err := util.BytecodeCompilerVersion(artifact.Bin)
+	require.NoError(t, err, "Error reading bytecode compiler version")
+	require.Equal(t, "0.8.21", version, "Incorrect compiler version")
+	_, err = util.BytecodeCompilerVersion("")
+	require.Error(t, err, "Interfaces have no bytecode metadata")
+
+	testcases := []struct {
+		name   string
+		file   string
+		change string
+	}{
+		{"Changed source", "contract/Token.sol", "// changed\n"},
+		{"Changed bytecode", "contract/build/Token.bin", "00"},
+		{"Changed ABI", "contract/build/ERC20.abi", " "},
+		{"Changed binding", "scripts/token/Token.go", "// changed\n"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Copy the committed build, and change a single file
+			root := t.TempDir()
+			for _, file := range []string{"contract/Token.sol", "contract/TokenUpgradeable.sol", "scripts/token/Token.go", "contract/build/build-info.json"} {
+				copyFile(t, filepath.Join("..", file), filepath.Join(root, file))
+			}
+			for name := range info.Contracts {
+				for _, ext := range []string{".abi", ".bin"} {
+					file := filepath.Join("contract/build", name+ext)
+					copyFile(t, filepath.Join("..", file), filepath.Join(root, file))
+				}
+			}
+			require.NoError(t, util.CheckBuild(root, "contract/build", info), "Copied build should be in sync")
+
+			file, err := os.OpenFile(filepath.Join(root, tc.file), os.O_APPEND|os.O_WRONLY, 0o644)
+			require.NoError(t, err, "Error opening %s", tc.file)
+			_, err = file.WriteString(tc.change)
+			require.NoError(t, err, "Error changing %s", tc.file)
+			require.NoError(t, file.Close(), "Error closing %s", tc.file)
+
+			require.Error(t, util.CheckBuild(root, "contract/build", info), "Changed %s should be detected", tc.file)
+		})
+	}
+}
+
+// Test NewBuildInfo
+// Checks that solc output is written as artifacts and build info, and that the
+// generated binding is the committed binding
+func TestNewBuildInfo(t *testing.T) {
+	binding := util.BindingConfig{Contract: "Token", Package: "token", Out: "scripts/token/Token.go"}
+
+	output, err := util.ParseSolcOutput(solcOutput(t))
+	require.NoError(t, err, "Error during ParseSolcOutput")
+
+	info, artifacts, err := util.NewBuildInfo(output, util.DefaultCompilerSettings, binding)
+	require.NoError(t, err, "Error during NewBuildInfo")
+	require.Equal(t, "0.8.6+commit.11564f7e", info.Compiler.Version, "Incorrect compiler version")
+	require.Contains(t, info.Sources, "contract/Token.sol", "Sources should be recorded")
+	require.Len(t, artifacts, 1, "Incorrect number of artifacts")
+
+	dir := t.TempDir()
+	require.NoError(t, util.WriteArtifacts(dir, artifacts), "Error during WriteArtifacts")
+	require.NoError(t, util.CompareArtifacts(dir, artifacts), "Written artifacts should match")
+	require.NoError(t, util.CompareArtifacts(buildDir, artifacts), "Committed artifacts should match")
+
+	code, err := util.GenerateBinding(artifacts[0].ABI, artifacts[0].Bin, binding)
+	require.NoError(t, err, "Error during GenerateBinding")
+	committed, err := os.ReadFile("../scripts/token/Token.go")
+	require.NoError(t, err, "Error reading committed binding")
+	require.Equal(t, string(committed), code, "Generated binding should match the committed binding")
+
+	_, _, err = util.NewBuildInfo(output, util.DefaultCompilerSettings, util.BindingConfig{Contract: "Missing"})
+	require.Error(t, err, "NewBuildInfo should reject a missing binding contract")
+
+	_, err = util.ParseSolcOutput(solcOutput(t, map[string]string{"severity": "warning", "formattedMessage": "unused variable"}))
+	require.NoError(t, err, "Warnings should be ignored")
+	_, err = util.ParseSolcOutput(solcOutput(t, map[string]string{"severity": "error", "formattedMessage": "ParserError"}))
+	require.Error(t, err, "Compilation errors should be returned")
+}
@@ -0,0 +1,236 @@
+/** erc20_model.go contains a reference model of the ERC20 specification, as implemented
+  by the OpenZeppelin ERC20 contract that Token extends, and the operations run against
+  it by the property tests. Operations are generated at random from a seed, or decoded
+  from the bytes of a native fuzz target.
+*/
+
+package testUtil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// ERC20OpKind is the method called by an ERC20Op
+type ERC20OpKind uint8
+
+const (
+	OpTransfer ERC20OpKind = iota
+	OpApprove
+	OpTransferFrom
+	OpIncreaseAllowance
+	OpDecreaseAllowance
+
+	numERC20OpKinds
+)
+
+// String returns the name of the method
+func (k ERC20OpKind) String() string {
+	switch k {
+	case OpTransfer:
+		return "transfer"
+	case OpApprove:
+		return "approve"
+	case OpTransferFrom:
+		return "transferFrom"
+	case OpIncreaseAllowance:
+		return "increaseAllowance"
+	case OpDecreaseAllowance:
+		return "decreaseAllowance"
+	}
+	return fmt.Sprintf("ERC20OpKind(%d)", uint8(k))
+}
+
+// ERC20Op is a call of an ERC20 method by the Sender account. To is the recipient of
+// transfers, or the spender of allowance changes, and From is the owner of the tokens
+// sent by transferFrom.
+type ERC20Op struct {
+	Kind   ERC20OpKind
+	Sender string
+	From   string
+	To     string
+	Amount *big.Int
+}
+
+// String returns the op as a call, such as alice.transferFrom(deployer, bob, 5)
+func (op ERC20Op) String() string {
+	if op.Kind == OpTransferFrom {
+		return fmt.Sprintf("%s.%s(%s, %s, %s)", op.Sender, op.Kind, op.From, op.To, op.Amount)
+	}
+	return fmt.Sprintf("%s.%s(%s, %s)", op.Sender, op.Kind, op.To, op.Amount)
+}
+
+// ERC20Event is a Transfer or Approval event. From and To are the owner and spender
+// of Approval events.
+type ERC20Event struct {
+	Name  string
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// String returns the event as it is declared, such as Transfer(0x.., 0x.., 5)
+func (e ERC20Event) String() string {
+	return fmt.Sprintf("%s(%s, %s, %s)", e.Name, e.From.Hex(), e.To.Hex(), e.Value)
+}
+
+// ErrERC20Rejected is returned by the model for calls that the contract reverts
+var ErrERC20Rejected = errors.New("rejected by the ERC20 model")
+
+// ERC20Model is the expected state of an ERC20 contract. Calls that are rejected
+// leave the model unchanged.
+type ERC20Model struct {
+	TotalSupply *big.Int
+
+	balances   map[common.Address]*big.Int
+	allowances map[common.Address]map[common.Address]*big.Int
+}
+
+// NewERC20Model returns the model of a contract that minted the supply to the holder
+func NewERC20Model(holder common.Address, supply *big.Int) *ERC20Model {
+	return &ERC20Model{
+		TotalSupply: new(big.Int).Set(supply),
+		balances:    map[common.Address]*big.Int{holder: new(big.Int).Set(supply)},
+		allowances:  map[common.Address]map[common.Address]*big.Int{},
+	}
+}
+
+// BalanceOf returns the balance of the account
+func (m *ERC20Model) BalanceOf(account common.Address) *big.Int {
+	if balance, ok := m.balances[account]; ok {
+		return new(big.Int).Set(balance)
+	}
+	return new(big.Int)
+}
+
+// Allowance returns the amount the spender may transfer from the owner
+func (m *ERC20Model) Allowance(owner, spender common.Address) *big.Int {
+	if allowance, ok := m.allowances[owner][spender]; ok {
+		return new(big.Int).Set(allowance)
+	}
+	return new(big.Int)
+}
+
+// Apply calls the op on the model, with the addresses of its named accounts, and
+// returns the events the contract emits for it
+func (m *ERC20Model) Apply(op ERC20Op, address func(string) common.Address) ([]ERC20Event, error) {
+	sender, to := address(op.Sender), address(op.To)
+	switch op.Kind {
+	case OpTransfer:
+		return m.transfer(sender, to, op.Amount)
+	case OpApprove:
+		return m.approve(sender, to, op.Amount), nil
+	case OpTransferFrom:
+		return m.transferFrom(sender, address(op.From), to, op.Amount)
+	case OpIncreaseAllowance:
+		allowance := new(big.Int).Add(m.Allowance(sender, to), op.Amount)
+		if allowance.Cmp(math.MaxBig256) > 0 {
+			return nil, fmt.Errorf("%w: allowance overflow", ErrERC20Rejected)
+		}
+		return m.approve(sender, to, allowance), nil
+	case OpDecreaseAllowance:
+		allowance := new(big.Int).Sub(m.Allowance(sender, to), op.Amount)
+		if allowance.Sign() < 0 {
+			return nil, fmt.Errorf("%w: decreased allowance below zero", ErrERC20Rejected)
+		}
+		return m.approve(sender, to, allowance), nil
+	}
+	return nil, fmt.Errorf("unknown ERC20 op %s", op.Kind)
+}
+
+// transfer moves the amount from the sender to the recipient
+func (m *ERC20Model) transfer(from, to common.Address, amount *big.Int) ([]ERC20Event, error) {
+	balance := m.BalanceOf(from)
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: transfer amount exceeds balance", ErrERC20Rejected)
+	}
+	m.balances[from] = balance.Sub(balance, amount)
+	m.balances[to] = new(big.Int).Add(m.BalanceOf(to), amount)
+	return []ERC20Event{{Name: "Transfer", From: from, To: to, Value: new(big.Int).Set(amount)}}, nil
+}
+
+// approve sets the allowance of the spender
+func (m *ERC20Model) approve(owner, spender common.Address, amount *big.Int) []ERC20Event {
+	if m.allowances[owner] == nil {
+		m.allowances[owner] = map[common.Address]*big.Int{}
+	}
+	m.allowances[owner][spender] = new(big.Int).Set(amount)
+	return []ERC20Event{{Name: "Approval", From: owner, To: spender, Value: new(big.Int).Set(amount)}}
+}
+
+// transferFrom spends the allowance of the spender to move the amount from the owner.
+// An allowance of the max uint256 is infinite, and is not spent.
+func (m *ERC20Model) transferFrom(spender, from, to common.Address, amount *big.Int) ([]ERC20Event, error) {
+	allowance := m.Allowance(from, spender)
+	if allowance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: insufficient allowance", ErrERC20Rejected)
+	}
+	if m.BalanceOf(from).Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: transfer amount exceeds balance", ErrERC20Rejected)
+	}
+
+	var events []ERC20Event
+	if allowance.Cmp(math.MaxBig256) != 0 {
+		events = m.approve(from, spender, allowance.Sub(allowance, amount))
+	}
+	transferred, err := m.transfer(from, to, amount)
+	return append(events, transferred...), err
+}
+
+// RandomERC20Sequence returns length ops between the named accounts. Amounts are
+// mostly small, with edge cases such as the whole supply, one more than it, and the
+// max uint256.
+func RandomERC20Sequence(rng *rand.Rand, accounts []string, supply *big.Int, length int) []ERC20Op {
+	ops := make([]ERC20Op, length)
+	for i := range ops {
+		ops[i] = ERC20Op{
+			Kind:   ERC20OpKind(rng.Intn(int(numERC20OpKinds))),
+			Sender: accounts[rng.Intn(len(accounts))],
+			From:   accounts[rng.Intn(len(accounts))],
+			To:     accounts[rng.Intn(len(accounts))],
+			Amount: erc20Amount(byte(rng.Intn(256)), supply),
+		}
+	}
+	return ops
+}
+
+// DecodeERC20Sequence returns the ops encoded by the bytes of a fuzz input, five bytes
+// per op, for the kind, the sender, the owner, the recipient and the amount. Trailing
+// bytes are ignored.
+func DecodeERC20Sequence(data []byte, accounts []string, supply *big.Int) []ERC20Op {
+	var ops []ERC20Op
+	for ; len(data) >= 5; data = data[5:] {
+		ops = append(ops, ERC20Op{
+			Kind:   ERC20OpKind(data[0] % byte(numERC20OpKinds)),
+			Sender: accounts[int(data[1])%len(accounts)],
+			From:   accounts[int(data[2])%len(accounts)],
+			To:     accounts[int(data[3])%len(accounts)],
+			Amount: erc20Amount(data[4], supply),
+		})
+	}
+	return ops
+}
+
+// erc20Amount maps a byte to an amount, most of them a fraction of the supply
+func erc20Amount(b byte, supply *big.Int) *big.Int {
+	switch b {
+	case 0:
+		return new(big.Int)
+	case 252:
+		return new(big.Int).Set(supply)
+	case 253:
+		return new(big.Int).Add(supply, big.NewInt(1))
+	case 254:
+		return new(big.Int).Sub(math.MaxBig256, big.NewInt(1))
+	case 255:
+		return new(big.Int).Set(math.MaxBig256)
+	}
+	// b thousandths of the supply
+	amount := new(big.Int).Mul(supply, big.NewInt(int64(b)))
+	return amount.Div(amount, big.NewInt(1000))
+}
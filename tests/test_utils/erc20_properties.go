@@ -0,0 +1,283 @@
+/** erc20_properties.go contains a stateful property tester of the Token contract. A
+  sequence of ERC20Ops is sent to a Token deployed on a Harness, and applied to an
+  ERC20Model. After each op the invariants of the ERC20 specification are checked:
+  balances sum to the total supply, balances and allowances only move as the model
+  does, failed calls change nothing, and the events match the state changes.
+  Failing sequences are shrunk to a minimal reproduction.
+*/
+
+package testUtil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ERC20Accounts are the accounts of the property tests. The deployer holds the supply.
+var ERC20Accounts = []string{"deployer", "alice", "bob", "carol"}
+
+// erc20OpGasLimit is the gas limit of each op. It is set so that calls that revert are
+// mined rather than rejected by gas estimation.
+const erc20OpGasLimit = 200000
+
+// ERC20PropertyError is returned when an invariant does not hold after an op
+type ERC20PropertyError struct {
+	// Ops is the sequence, up to and including the op that failed
+	Ops []ERC20Op
+	Err error
+}
+
+func (e *ERC20PropertyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "after op %d of:\n", len(e.Ops))
+	for i, op := range e.Ops {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, op)
+	}
+	fmt.Fprintf(&b, "%v", e.Err)
+	return b.String()
+}
+
+func (e *ERC20PropertyError) Unwrap() error {
+	return e.Err
+}
+
+// erc20State is the balance of every account, and every allowance between them
+type erc20State struct {
+	totalSupply *big.Int
+	balances    []*big.Int
+	allowances  [][]*big.Int
+}
+
+// CheckERC20Sequence deploys a Token from the deployer of ERC20Accounts, and sends
+// each op, checking the invariants after every one. It returns an
+// ERC20PropertyError for the first op after which an invariant does not hold.
+func CheckERC20Sequence(ops []ERC20Op) error {
+	h, err := NewHarness(ERC20Accounts...)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	_, contract, err := Deploy(h, ERC20Accounts[0], DeployToken)
+	if err != nil {
+		return err
+	}
+	supply, err := contract.TotalSupply(nil)
+	if err != nil {
+		return err
+	}
+	model := NewERC20Model(h.Address(ERC20Accounts[0]), supply)
+
+	state, err := readERC20State(h, contract)
+	if err != nil {
+		return err
+	}
+	if err := checkERC20State(h, model, state); err != nil {
+		return &ERC20PropertyError{Err: err}
+	}
+
+	for i, op := range ops {
+		next, err := checkERC20Op(h, contract, model, state, op)
+		if err != nil {
+			return &ERC20PropertyError{Ops: ops[:i+1], Err: err}
+		}
+		state = next
+	}
+	return nil
+}
+
+// checkERC20Op sends the op, applies it to the model, checks the invariants, and
+// returns the state of the contract after it
+func checkERC20Op(h *Harness, contract *token.Token, model *ERC20Model, before erc20State, op ERC20Op) (erc20State, error) {
+	want, modelErr := model.Apply(op, h.Address)
+	receipt, err := h.Commit(sendERC20Op(h, contract, op))
+	if err != nil && !errors.Is(err, util.ErrTxReverted) {
+		return before, err
+	}
+	if (err == nil) != (modelErr == nil) {
+		return before, fmt.Errorf("contract error %v, model error %v", err, modelErr)
+	}
+
+	after, err := readERC20State(h, contract)
+	if err != nil {
+		return before, err
+	}
+	if modelErr != nil && !before.equal(after) {
+		return after, errors.New("a failed call changed the state of the contract")
+	}
+	if err := checkERC20State(h, model, after); err != nil {
+		return after, err
+	}
+
+	got, err := erc20Events(contract, receipt)
+	if err != nil {
+		return after, err
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		return after, fmt.Errorf("events %v, want %v", got, want)
+	}
+	return after, nil
+}
+
+// sendERC20Op sends the op to the contract, with a fixed gas limit
+func sendERC20Op(h *Harness, contract *token.Token, op ERC20Op) (*types.Transaction, error) {
+	opts := h.Auth(op.Sender)
+	opts.GasLimit = erc20OpGasLimit
+	to := h.Address(op.To)
+
+	switch op.Kind {
+	case OpTransfer:
+		return contract.Transfer(opts, to, op.Amount)
+	case OpApprove:
+		return contract.Approve(opts, to, op.Amount)
+	case OpTransferFrom:
+		return contract.TransferFrom(opts, h.Address(op.From), to, op.Amount)
+	case OpIncreaseAllowance:
+		return contract.IncreaseAllowance(opts, to, op.Amount)
+	case OpDecreaseAllowance:
+		return contract.DecreaseAllowance(opts, to, op.Amount)
+	}
+	return nil, fmt.Errorf("unknown ERC20 op %s", op.Kind)
+}
+
+// readERC20State reads the supply, and the balances and allowances of ERC20Accounts
+func readERC20State(h *Harness, contract *token.Token) (erc20State, error) {
+	opts := &bind.CallOpts{}
+	state := erc20State{
+		balances:   make([]*big.Int, len(ERC20Accounts)),
+		allowances: make([][]*big.Int, len(ERC20Accounts)),
+	}
+
+	var err error
+	if state.totalSupply, err = contract.TotalSupply(opts); err != nil {
+		return state, err
+	}
+	for i, owner := range ERC20Accounts {
+		if state.balances[i], err = contract.BalanceOf(opts, h.Address(owner)); err != nil {
+			return state, err
+		}
+		state.allowances[i] = make([]*big.Int, len(ERC20Accounts))
+		for j, spender := range ERC20Accounts {
+			if state.allowances[i][j], err = contract.Allowance(opts, h.Address(owner), h.Address(spender)); err != nil {
+				return state, err
+			}
+		}
+	}
+	return state, nil
+}
+
+// equal returns whether both states hold the same amounts
+func (s erc20State) equal(other erc20State) bool {
+	if s.totalSupply.Cmp(other.totalSupply) != 0 {
+		return false
+	}
+	for i := range s.balances {
+		if s.balances[i].Cmp(other.balances[i]) != 0 {
+			return false
+		}
+		for j := range s.allowances[i] {
+			if s.allowances[i][j].Cmp(other.allowances[i][j]) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkERC20State checks that the balances sum to the supply, and that the state of
+// the contract is the state of the model
+func checkERC20State(h *Harness, model *ERC20Model, state erc20State) error {
+	if state.totalSupply.Cmp(model.TotalSupply) != 0 {
+		return fmt.Errorf("total supply %s, want %s", state.totalSupply, model.TotalSupply)
+	}
+
+	sum := new(big.Int)
+	for i, owner := range ERC20Accounts {
+		sum.Add(sum, state.balances[i])
+		if want := model.BalanceOf(h.Address(owner)); state.balances[i].Cmp(want) != 0 {
+			return fmt.Errorf("balance of %s %s, want %s", owner, state.balances[i], want)
+		}
+		for j, spender := range ERC20Accounts {
+			if want := model.Allowance(h.Address(owner), h.Address(spender)); state.allowances[i][j].Cmp(want) != 0 {
+				return fmt.Errorf("allowance of %s from %s %s, want %s", spender, owner, state.allowances[i][j], want)
+			}
+		}
+	}
+	if sum.Cmp(state.totalSupply) != 0 {
+		return fmt.Errorf("balances sum to %s, not the total supply %s", sum, state.totalSupply)
+	}
+	return nil
+}
+
+// erc20Events returns the Transfer and Approval events of the receipt, in order
+func erc20Events(contract *token.Token, receipt *types.Receipt) ([]ERC20Event, error) {
+	var events []ERC20Event
+	for _, log := range receipt.Logs {
+		if transfer, err := contract.ParseTransfer(*log); err == nil {
+			events = append(events, ERC20Event{Name: "Transfer", From: transfer.From, To: transfer.To, Value: transfer.Value})
+			continue
+		}
+		approval, err := contract.ParseApproval(*log)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected log %v", log.Topics)
+		}
+		events = append(events, ERC20Event{Name: "Approval", From: approval.Owner, To: approval.Spender, Value: approval.Value})
+	}
+	return events, nil
+}
+
+// ShrinkERC20Sequence returns a minimal sequence that still fails, by removing ever
+// smaller chunks of ops and lowering their amounts, until neither changes the sequence
+func ShrinkERC20Sequence(ops []ERC20Op, fails func([]ERC20Op) bool) []ERC20Op {
+	ops = append([]ERC20Op(nil), ops...)
+
+	for shrunk := true; shrunk; {
+		shrunk = false
+
+		for chunk := len(ops) / 2; chunk > 0; {
+			removed := false
+			for start := 0; start+chunk <= len(ops); {
+				candidate := append(append([]ERC20Op(nil), ops[:start]...), ops[start+chunk:]...)
+				if fails(candidate) {
+					ops, removed, shrunk = candidate, true, true
+					continue
+				}
+				start++
+			}
+			if !removed {
+				chunk /= 2
+			}
+		}
+
+		for i := range ops {
+			for _, amount := range shrinkAmounts(ops[i].Amount) {
+				candidate := append([]ERC20Op(nil), ops...)
+				candidate[i].Amount = amount
+				if fails(candidate) {
+					ops, shrunk = candidate, true
+					break
+				}
+			}
+		}
+	}
+	return ops
+}
+
+// shrinkAmounts returns the amounts smaller than the given one, smallest first
+func shrinkAmounts(amount *big.Int) []*big.Int {
+	var amounts []*big.Int
+	for _, candidate := range []*big.Int{new(big.Int), big.NewInt(1), new(big.Int).Set(Ten18), new(big.Int).Rsh(amount, 1)} {
+		if candidate.Cmp(amount) < 0 {
+			amounts = append(amounts, candidate)
+		}
+	}
+	return amounts
+}
@@ -0,0 +1,188 @@
+/** gas.go contains a per-method gas report for the transactions committed by a Harness.
+  The gas used by every successful transaction is recorded by the 4 byte selector of
+  the method it called, or as a deployment, and summarised with its min, average and
+  max. The report can be written as a JSON baseline, and checked against a committed
+  baseline so that a suite fails when a method's gas use grows.
+*/
+
+package testUtil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GasDeployment is the report entry of contract creation transactions
+const GasDeployment = "deployment"
+
+// DefaultGasTolerance is the fraction by which a method's gas use may exceed its baseline
+const DefaultGasTolerance = 0.05
+
+// ErrGasRegression is returned when a method's gas use exceeds its baseline
+var ErrGasRegression = errors.New("gas regression")
+
+// GasStats summarises the gas used by the calls of a method
+type GasStats struct {
+	Calls int    `json:"calls"`
+	Min   uint64 `json:"min"`
+	Avg   uint64 `json:"avg"`
+	Max   uint64 `json:"max"`
+}
+
+// GasReport records the gas used by transactions, by method. It is safe for
+// concurrent use, so a single report can be shared by every spec of a suite.
+type GasReport struct {
+	mu      sync.Mutex
+	methods map[[4]byte]string
+	used    map[string][]uint64
+}
+
+// NewGasReport returns an empty report, which names the methods of the given ABIs.
+// Calls of other methods are reported by their selector.
+func NewGasReport(abis ...*abi.ABI) *GasReport {
+	r := &GasReport{methods: map[[4]byte]string{}, used: map[string][]uint64{}}
+	for _, contractABI := range abis {
+		for _, method := range contractABI.Methods {
+			var selector [4]byte
+			copy(selector[:], method.ID)
+			r.methods[selector] = method.Name
+		}
+	}
+	return r
+}
+
+// Record adds the gas used by the transaction to its method. Reverted transactions
+// are skipped, as they stop early and would lower the method's minimum.
+func (r *GasReport) Record(tx *types.Transaction, receipt *types.Receipt) {
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return
+	}
+	name := r.methodOf(tx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.used[name] = append(r.used[name], receipt.GasUsed)
+}
+
+// methodOf returns the name of the method called by the transaction
+func (r *GasReport) methodOf(tx *types.Transaction) string {
+	if tx.To() == nil {
+		return GasDeployment
+	}
+	data := tx.Data()
+	if len(data) < 4 {
+		return "fallback"
+	}
+	var selector [4]byte
+	copy(selector[:], data)
+	if name, ok := r.methods[selector]; ok {
+		return name
+	}
+	return hexutil.Encode(selector[:])
+}
+
+// Stats returns the gas statistics of every recorded method
+func (r *GasReport) Stats() map[string]GasStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[string]GasStats, len(r.used))
+	for name, used := range r.used {
+		s := GasStats{Calls: len(used), Min: used[0], Max: used[0]}
+		var total uint64
+		for _, gas := range used {
+			total += gas
+			if gas < s.Min {
+				s.Min = gas
+			}
+			if gas > s.Max {
+				s.Max = gas
+			}
+		}
+		s.Avg = total / uint64(len(used))
+		stats[name] = s
+	}
+	return stats
+}
+
+// WriteTable writes the statistics of every method as a table, sorted by method
+func (r *GasReport) WriteTable(w io.Writer) error {
+	stats := r.Stats()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Method\tCalls\tMin\tAvg\tMax\t")
+	for _, name := range names {
+		s := stats[name]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t\n", name, s.Calls, s.Min, s.Avg, s.Max)
+	}
+	return tw.Flush()
+}
+
+// WriteBaseline writes the statistics of every method to the JSON file at path
+func (r *GasReport) WriteBaseline(path string) error {
+	data, err := json.MarshalIndent(r.Stats(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadGasBaseline reads the statistics written by WriteBaseline
+func LoadGasBaseline(path string) (map[string]GasStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline map[string]GasStats
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid gas baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// CheckBaseline returns ErrGasRegression, naming every method, when the max or average
+// gas used by a method exceeds its baseline by more than the tolerance, a fraction such
+// as DefaultGasTolerance. Methods missing from either the report or the baseline are
+// not compared.
+func (r *GasReport) CheckBaseline(baseline map[string]GasStats, tolerance float64) error {
+	stats := r.Stats()
+	var regressions []string
+	for name, s := range stats {
+		base, ok := baseline[name]
+		if !ok {
+			continue
+		}
+		for _, c := range []struct {
+			stat      string
+			got, want uint64
+		}{{"max", s.Max, base.Max}, {"avg", s.Avg, base.Avg}} {
+			if float64(c.got) > float64(c.want)*(1+tolerance) {
+				regressions = append(regressions, fmt.Sprintf("%s %s %d > %d", name, c.stat, c.got, c.want))
+			}
+		}
+	}
+	if len(regressions) > 0 {
+		sort.Strings(regressions)
+		return fmt.Errorf("%w beyond %.1f%%: %v", ErrGasRegression, tolerance*100, regressions)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+/** harness.go contains a reusable simulated chain harness, for testing any contract
+  with abigen bindings. The harness starts a simulated backend with named, funded
+  accounts, deploys contracts with their abigen DeployX functions, commits and checks
+  transactions, and can snapshot and revert the chain, or move its time forward.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultAccountBalance is the native balance of each harness account at genesis
+var DefaultAccountBalance = new(big.Int).Mul(big.NewInt(1000), Ten18)
+
+// Account is a funded account of a Harness
+type Account struct {
+	Name    string
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// HarnessOptions configures the simulated chain started by NewHarnessWithOptions
+type HarnessOptions struct {
+	// Accounts are the names of the accounts funded at genesis
+	Accounts []string
+	// Balance is the native balance of each account, DefaultAccountBalance if nil
+	Balance *big.Int
+	// BlockGasLimit is the gas limit of each block, 10 * MaxGasPerBlock if 0
+	BlockGasLimit uint64
+}
+
+// Harness is a simulated chain with named, funded accounts
+type Harness struct {
+	Backend *backends.SimulatedBackend
+	// Gas records the gas used by every transaction committed, when not nil
+	Gas *GasReport
+
+	accounts map[string]*Account
+	names    []string
+}
+
+// Snapshot is a point of the chain that a Harness can be reverted to
+type Snapshot struct {
+	number uint64
+	hash   common.Hash
+}
+
+// NewHarness starts a simulated chain with an account of DefaultAccountBalance for
+// each of the given names, such as "deployer", "alice" and "bob"
+func NewHarness(names ...string) (*Harness, error) {
+	return NewHarnessWithOptions(HarnessOptions{Accounts: names})
+}
+
+// NewHarnessWithOptions starts a simulated chain configured by the options
+func NewHarnessWithOptions(opts HarnessOptions) (*Harness, error) {
+	if len(opts.Accounts) == 0 {
+		return nil, errors.New("the harness needs at least one account")
+	}
+	balance := opts.Balance
+	if balance == nil {
+		balance = DefaultAccountBalance
+	}
+	gasLimit := opts.BlockGasLimit
+	if gasLimit == 0 {
+		gasLimit = MaxGasPerBlock * 10
+	}
+
+	privKeys, addresses, err := GeneratePrivKeysAndAddresses(uint64(len(opts.Accounts)))
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Harness{accounts: map[string]*Account{}}
+	genesisAlloc := core.GenesisAlloc{}
+	for i, name := range opts.Accounts {
+		if _, ok := h.accounts[name]; ok {
+			return nil, fmt.Errorf("duplicate account name %q", name)
+		}
+		h.accounts[name] = &Account{Name: name, Key: privKeys[i], Address: addresses[i]}
+		h.names = append(h.names, name)
+		genesisAlloc[addresses[i]] = core.GenesisAccount{Balance: new(big.Int).Set(balance)}
+	}
+
+	h.Backend = backends.NewSimulatedBackend(genesisAlloc, gasLimit)
+	return h, nil
+}
+
+// Close stops the simulated chain
+func (h *Harness) Close() error {
+	return h.Backend.Close()
+}
+
+// Account returns the named account. It panics if there is no such account, as that
+// is a mistake in the test itself.
+func (h *Harness) Account(name string) *Account {
+	account, ok := h.accounts[name]
+	if !ok {
+		panic(fmt.Sprintf("testUtil: no harness account %q", name))
+	}
+	return account
+}
+
+// Address returns the address of the named account
+func (h *Harness) Address(name string) common.Address {
+	return h.Account(name).Address
+}
+
+// Addresses returns the addresses of every account, in the order they were named
+func (h *Harness) Addresses() []common.Address {
+	addresses := make([]common.Address, len(h.names))
+	for i, name := range h.names {
+		addresses[i] = h.accounts[name].Address
+	}
+	return addresses
+}
+
+// Auth returns new transaction options signed by the named account
+func (h *Harness) Auth(name string) *bind.TransactOpts {
+	auth, err := bind.NewKeyedTransactorWithChainID(h.Account(name).Key, TestChainID)
+	if err != nil {
+		// Only fails for a nil chain ID
+		panic(err)
+	}
+	return auth
+}
+
+// Deploy deploys a contract from the named account with an abigen DeployX function,
+// commits the deployment, and checks that it succeeded. Constructor arguments are
+// passed by wrapping DeployX in a closure.
+func Deploy[T any](h *Harness, from string, deploy func(*bind.TransactOpts, bind.ContractBackend) (common.Address, *types.Transaction, T, error)) (common.Address, T, error) {
+	address, tx, instance, err := deploy(h.Auth(from), h.Backend)
+	if _, err := h.Commit(tx, err); err != nil {
+		var zero T
+		return common.Address{}, zero, fmt.Errorf("failed to deploy contract: %w", err)
+	}
+	return address, instance, nil
+}
+
+// Commit mines the pending block holding the transaction, and returns its receipt.
+// It takes the return values of an abigen transaction method, so that it can wrap
+// the call directly, and returns util.ErrTxReverted if the transaction failed.
+func (h *Harness) Commit(tx *types.Transaction, err error) (*types.Receipt, error) {
+	if err != nil {
+		return nil, err
+	}
+	h.Backend.Commit()
+
+	receipt, err := h.Backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt of %s: %w", tx.Hash().Hex(), err)
+	}
+	if h.Gas != nil {
+		h.Gas.Record(tx, receipt)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s", util.ErrTxReverted, tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// Snapshot returns the current head of the chain, so that it can be reverted to
+func (h *Harness) Snapshot() Snapshot {
+	head := h.Backend.Blockchain().CurrentBlock()
+	return Snapshot{number: head.NumberU64(), hash: head.Hash()}
+}
+
+// Revert rewinds the chain to the snapshot, removing every later block and any
+// pending transactions
+func (h *Harness) Revert(snapshot Snapshot) error {
+	chain := h.Backend.Blockchain()
+	if header := chain.GetHeaderByNumber(snapshot.number); header == nil || header.Hash() != snapshot.hash {
+		return fmt.Errorf("snapshot block %d is no longer on the chain", snapshot.number)
+	}
+	if err := chain.SetHead(snapshot.number); err != nil {
+		return err
+	}
+	if chain.CurrentBlock().Hash() != snapshot.hash {
+		return fmt.Errorf("failed to revert to block %d, the state of the block is no longer available", snapshot.number)
+	}
+
+	// Rebuild the pending block on the reverted head
+	h.Backend.Rollback()
+	return nil
+}
+
+// AdvanceTime mines an empty block, timestamped the given duration after the
+// previous block, so that contracts see the time move forward
+func (h *Harness) AdvanceTime(d time.Duration) error {
+	if err := h.Backend.AdjustTime(d); err != nil {
+		return err
+	}
+	h.Backend.Commit()
+	return nil
+}
+
+// Now returns the timestamp of the latest block
+func (h *Harness) Now() time.Time {
+	return time.Unix(int64(h.Backend.Blockchain().CurrentBlock().Time()), 0)
+}
+
+// Events returns every event of the receipt that the abigen ParseX function decodes,
+// such as TokenFilterer.ParseTransfer. Logs of other events are skipped.
+func Events[T any](receipt *types.Receipt, parse func(types.Log) (*T, error)) []*T {
+	var events []*T
+	for _, log := range receipt.Logs {
+		// Anonymous events cannot be told apart by their first topic
+		if len(log.Topics) == 0 {
+			continue
+		}
+		if event, err := parse(*log); err == nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// ExpectEvent returns the single event of the receipt that the abigen ParseX function
+// decodes, or an error if the receipt has no such event, or more than one
+func ExpectEvent[T any](receipt *types.Receipt, parse func(types.Log) (*T, error)) (*T, error) {
+	events := Events(receipt, parse)
+	if len(events) != 1 {
+		return nil, fmt.Errorf("expected 1 %T event in %s, got %d", *new(T), receipt.TxHash.Hex(), len(events))
+	}
+	return events[0], nil
+}
@@ -0,0 +1,52 @@
+/** proxy.go contains helpers deploying the TokenUpgradeable contract behind the
+  OpenZeppelin proxies compiled into contract/build, for tests of the proxy helpers
+  in scripts/utils/proxy.go.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"path/filepath"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReadBuildArtifact reads the artifact of a contract compiled into contract/build
+func ReadBuildArtifact(name string) (util.Artifact, error) {
+	return util.ReadArtifact(filepath.Join(repoRoot(), util.DefaultBuildDir), name)
+}
+
+// DeployTokenUpgradeable deploys a TokenUpgradeable implementation, which is locked
+// until it is initialised through a proxy
+func DeployTokenUpgradeable(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *bind.BoundContract, error) {
+	artifact, err := ReadBuildArtifact(util.TokenUpgradeableArtifact)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(artifact.ABI))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return bind.DeployContract(auth, contractABI, common.FromHex(artifact.Bin), backend)
+}
+
+// TokenInitializer returns the call data of the initialize method of
+// TokenUpgradeable, with the Token parameters and the owner allowed to upgrade a UUPS
+// proxy
+func TokenInitializer(params util.TokenParams, owner common.Address) ([]byte, error) {
+	artifact, err := ReadBuildArtifact(util.TokenUpgradeableArtifact)
+	if err != nil {
+		return nil, err
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(artifact.ABI))
+	if err != nil {
+		return nil, err
+	}
+	return contractABI.Pack("initialize", params.Name, params.Symbol, params.Decimals, params.InitialSupply, params.Holders, owner)
+}
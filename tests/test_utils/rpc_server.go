@@ -0,0 +1,380 @@
+/** rpc_server.go contains an in-process Ethereum JSON-RPC server backed by the simulated
+  chain of a Harness, so that code using an ethclient.Client, such as scripts/utils,
+  can be tested over real HTTP without a running node. Every transaction sent is
+  mined at once, in its own block.
+  The simulated backend always runs with chain ID 1337. The server can report another
+  chain ID, such as the 9000 of the local node: transactions signed for it by harness
+  accounts are signed again for the backend, and are known to clients by the hash of
+  the transaction they sent.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCServer serves the eth namespace of a Harness over HTTP at URL, and over a
+// websocket at WSURL
+type RPCServer struct {
+	URL   string
+	WSURL string
+
+	chainID *big.Int
+	rpc     *rpc.Server
+	http    *httptest.Server
+	ws      *httptest.Server
+}
+
+// NewRPCServer starts a JSON-RPC server for the simulated chain of the harness, which
+// reports the given chain ID, or the TestChainID of the backend if it is nil
+func NewRPCServer(h *Harness, chainID *big.Int) (*RPCServer, error) {
+	if chainID == nil {
+		chainID = TestChainID
+	}
+	api := &ethAPI{
+		harness:  h,
+		chainID:  new(big.Int).Set(chainID),
+		internal: map[common.Hash]common.Hash{},
+		external: map[common.Hash]common.Hash{},
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", api); err != nil {
+		return nil, err
+	}
+
+	s := &RPCServer{
+		chainID: api.chainID,
+		rpc:     server,
+		http:    httptest.NewServer(server),
+		ws:      httptest.NewServer(server.WebsocketHandler([]string{"*"})),
+	}
+	s.URL = s.http.URL
+	s.WSURL = "ws" + strings.TrimPrefix(s.ws.URL, "http")
+	return s, nil
+}
+
+// Close stops the server. The harness is left running.
+func (s *RPCServer) Close() {
+	s.http.Close()
+	s.ws.Close()
+	s.rpc.Stop()
+}
+
+// Profile returns a network profile for the server, named after it
+func (s *RPCServer) Profile() *util.NetworkProfile {
+	return &util.NetworkProfile{
+		Name:    "simulated",
+		HTTP:    s.URL,
+		WS:      s.WSURL,
+		ChainID: s.chainID.Int64(),
+	}
+}
+
+// ethAPI implements the eth methods used by ethclient and the abigen bindings
+type ethAPI struct {
+	harness *Harness
+	// chainID is the chain ID reported to clients
+	chainID *big.Int
+
+	// mu serialises sent transactions, as each is mined before the next is sent, and
+	// guards the hashes of the transactions signed again for the backend
+	mu       sync.Mutex
+	internal map[common.Hash]common.Hash
+	external map[common.Hash]common.Hash
+}
+
+// callArgs are the arguments of eth_call and eth_estimateGas
+type callArgs struct {
+	From                 *common.Address `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"`
+}
+
+// callMsg returns the call message of the arguments
+func (args callArgs) callMsg() ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		To:        args.To,
+		GasPrice:  (*big.Int)(args.GasPrice),
+		GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+		GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+		Value:     (*big.Int)(args.Value),
+	}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	return msg
+}
+
+// blockNumber returns the number of a block parameter, nil for the latest block, and
+// whether it is the pending block. The simulated backend only holds the state of the
+// latest and pending blocks.
+func (api *ethAPI) blockNumber(ctx context.Context, block *rpc.BlockNumberOrHash) (*big.Int, bool, error) {
+	if block == nil {
+		return nil, false, nil
+	}
+	if hash, ok := block.Hash(); ok {
+		header, err := api.harness.Backend.HeaderByHash(ctx, hash)
+		if err != nil {
+			return nil, false, err
+		}
+		return header.Number, false, nil
+	}
+
+	number, _ := block.Number()
+	switch number {
+	case rpc.PendingBlockNumber:
+		return nil, true, nil
+	case rpc.LatestBlockNumber:
+		return nil, false, nil
+	case rpc.EarliestBlockNumber:
+		return new(big.Int), false, nil
+	}
+	return big.NewInt(number.Int64()), false, nil
+}
+
+// ChainId returns the chain ID reported to clients
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Set(api.chainID))
+}
+
+// BlockNumber returns the number of the latest block
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.harness.Backend.Blockchain().CurrentBlock().NumberU64())
+}
+
+// GetBlockByNumber returns the header of the block. Transactions are not returned, so
+// it serves ethclient.HeaderByNumber, but not BlockByNumber.
+func (api *ethAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (*types.Header, error) {
+	var header *types.Header
+	var err error
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		header, err = api.harness.Backend.HeaderByNumber(ctx, nil)
+	default:
+		header, err = api.harness.Backend.HeaderByNumber(ctx, big.NewInt(number.Int64()))
+	}
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	return header, err
+}
+
+// GetBalance returns the native balance of the account
+func (api *ethAPI) GetBalance(ctx context.Context, address common.Address, block *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	number, _, err := api.blockNumber(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := api.harness.Backend.BalanceAt(ctx, address, number)
+	return (*hexutil.Big)(balance), err
+}
+
+// GetCode returns the code of the account
+func (api *ethAPI) GetCode(ctx context.Context, address common.Address, block *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	number, pending, err := api.blockNumber(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return api.harness.Backend.PendingCodeAt(ctx, address)
+	}
+	return api.harness.Backend.CodeAt(ctx, address, number)
+}
+
+// GetStorageAt returns the value of a storage slot of the account, such as the
+// ERC-1967 slots of a proxy
+func (api *ethAPI) GetStorageAt(ctx context.Context, address common.Address, slot common.Hash, block *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	number, _, err := api.blockNumber(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	return api.harness.Backend.StorageAt(ctx, address, slot, number)
+}
+
+// GetTransactionCount returns the nonce of the account
+func (api *ethAPI) GetTransactionCount(ctx context.Context, address common.Address, block *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	number, pending, err := api.blockNumber(ctx, block)
+	if err != nil {
+		return 0, err
+	}
+	var nonce uint64
+	if pending {
+		nonce, err = api.harness.Backend.PendingNonceAt(ctx, address)
+	} else {
+		nonce, err = api.harness.Backend.NonceAt(ctx, address, number)
+	}
+	return hexutil.Uint64(nonce), err
+}
+
+// GasPrice returns the suggested legacy gas price
+func (api *ethAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := api.harness.Backend.SuggestGasPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+// MaxPriorityFeePerGas returns the suggested tip of dynamic fee transactions
+func (api *ethAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tip, err := api.harness.Backend.SuggestGasTipCap(ctx)
+	return (*hexutil.Big)(tip), err
+}
+
+// SendRawTransaction sends the signed transaction, and mines it in a new block
+func (api *ethAPI) SendRawTransaction(ctx context.Context, encoded hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(encoded); err != nil {
+		return common.Hash{}, err
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	sent, err := api.resign(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.harness.Backend.SendTransaction(ctx, sent); err != nil {
+		return common.Hash{}, err
+	}
+	api.harness.Backend.Commit()
+
+	if sent != tx {
+		api.internal[tx.Hash()] = sent.Hash()
+		api.external[sent.Hash()] = tx.Hash()
+	}
+	return tx.Hash(), nil
+}
+
+// resign returns the transaction signed for the chain ID of the backend, with the key
+// of the harness account that signed it for the chain ID of the server
+func (api *ethAPI) resign(tx *types.Transaction) (*types.Transaction, error) {
+	if api.chainID.Cmp(TestChainID) == 0 {
+		return tx, nil
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(api.chainID), tx)
+	if err != nil {
+		return nil, err
+	}
+	var key *ecdsa.PrivateKey
+	for _, account := range api.harness.accounts {
+		if account.Address == from {
+			key = account.Key
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("sender %s is not a harness account", from.Hex())
+	}
+
+	var inner types.TxData
+	switch tx.Type() {
+	case types.LegacyTxType:
+		inner = &types.LegacyTx{Nonce: tx.Nonce(), GasPrice: tx.GasPrice(), Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data()}
+	case types.AccessListTxType:
+		inner = &types.AccessListTx{ChainID: TestChainID, Nonce: tx.Nonce(), GasPrice: tx.GasPrice(), Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList()}
+	case types.DynamicFeeTxType:
+		inner = &types.DynamicFeeTx{ChainID: TestChainID, Nonce: tx.Nonce(), GasTipCap: tx.GasTipCap(), GasFeeCap: tx.GasFeeCap(), Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList()}
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+	return types.SignNewTx(key, types.LatestSignerForChainID(TestChainID), inner)
+}
+
+// externalHash returns the hash a client knows a transaction of the backend by
+func (api *ethAPI) externalHash(hash common.Hash) common.Hash {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if external, ok := api.external[hash]; ok {
+		return external
+	}
+	return hash
+}
+
+// GetTransactionReceipt returns the receipt of the transaction, or null if it was not
+// mined
+func (api *ethAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	api.mu.Lock()
+	internal, ok := api.internal[hash]
+	api.mu.Unlock()
+	if !ok {
+		internal = hash
+	}
+
+	receipt, err := api.harness.Backend.TransactionReceipt(ctx, internal)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.TxHash = hash
+	for _, log := range receipt.Logs {
+		log.TxHash = hash
+	}
+	return receipt, nil
+}
+
+// Call executes the call without sending a transaction. Reverts return the revert
+// data of the call with the error.
+func (api *ethAPI) Call(ctx context.Context, args callArgs, block *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	number, pending, err := api.blockNumber(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return api.harness.Backend.PendingCallContract(ctx, args.callMsg())
+	}
+	return api.harness.Backend.CallContract(ctx, args.callMsg(), number)
+}
+
+// EstimateGas returns the gas needed to execute the call on the pending state
+func (api *ethAPI) EstimateGas(ctx context.Context, args callArgs, block *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	gas, err := api.harness.Backend.EstimateGas(ctx, args.callMsg())
+	return hexutil.Uint64(gas), err
+}
+
+// GetLogs returns the logs matching the filter
+func (api *ethAPI) GetLogs(ctx context.Context, criteria filters.FilterCriteria) ([]types.Log, error) {
+	logs, err := api.harness.Backend.FilterLogs(ctx, ethereum.FilterQuery(criteria))
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter logs: %w", err)
+	}
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	for i := range logs {
+		logs[i].TxHash = api.externalHash(logs[i].TxHash)
+	}
+	return logs, nil
+}
@@ -45,13 +45,20 @@ type ReceiptBackend interface {
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
+// DeployToken deploys an instance of the Token contract with the default parameters
+// of the deploy command, minting 100 TOK to the sender
+func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *token.Token, error) {
+	params := util.DefaultTokenParams
+	return token.DeployToken(auth, backend, params.Name, params.Symbol, params.Decimals, params.InitialSupply, nil)
+}
+
 // DeployContractAndCommit deploys an instance of the ERC20 Token contract
 // and commits the transaction to the simulated backend.
 // The function returns the contract address, the transaction, and an
 // instance of the contract binding.
 func DeployContractAndCommit(auth *bind.TransactOpts, client *backends.SimulatedBackend) (common.Address, *types.Transaction, *token.Token, error) {
 	// Deploy contract
-	contractAddress, tx, contract, err := token.DeployToken(auth, client)
+	contractAddress, tx, contract, err := DeployToken(auth, client)
 	if err != nil {
 		return common.Address{}, nil, nil, err
 	}
@@ -96,13 +103,13 @@ func FillTransactionSignerFields(auth *bind.TransactOpts, client *ethclient.Clie
 	return auth, nil
 }
 
-// GetClientAndTransactionSigner connects to a local Evmos node on port 8545,
-// queries the chain id and uses this together with the private key to create
-// a transaction signer.
+// GetClientAndTransactionSigner connects to the Evmos node of the given network
+// profile, queries the chain id and uses this together with the private key to
+// create a transaction signer.
 // The function returns the client and the transaction signer.
-func GetClientAndTransactionSigner(privKey *ecdsa.PrivateKey) (*ethclient.Client, *bind.TransactOpts, error) {
+func GetClientAndTransactionSigner(profile *util.NetworkProfile, privKey *ecdsa.PrivateKey) (*ethclient.Client, *bind.TransactOpts, error) {
 	// Connect to blockchain node given a valid URL
-	client, err := util.GetClient()
+	client, err := util.GetClient(profile)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -159,8 +166,8 @@ func GetCallData(name string, args ...interface{}) ([]byte, error) {
 		return nil, err
 	}
 
-	// Generate the call data for the transfer method using the ABI
-	callData, err := tokenABI.Pack("transfer", args...)
+	// Generate the call data for the named method using the ABI
+	callData, err := tokenABI.Pack(name, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -210,3 +217,14 @@ func GetSimulatedClientAndTransactionSigner(privKey *ecdsa.PrivateKey, blockGasL
 
 	return client, auth, nil
 }
+
+// SimulatedAuthBackend wraps a simulated backend with the ChainID method, so that
+// it can be used as a util.AuthBackend by GetAuth
+type SimulatedAuthBackend struct {
+	*backends.SimulatedBackend
+}
+
+// ChainID returns the fixed chain ID of simulated backends
+func (b SimulatedAuthBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(TestChainID), nil
+}
@@ -0,0 +1,103 @@
+/** tokencli.go contains a runner of the tokencli command against the JSON-RPC server of
+  a Harness, so that its subcommands are tested end to end on the simulated chain.
+  tokencli is built into a directory of the test, and run from the repository root,
+  so that it reads the artifacts of contract/build.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// Tokencli runs tokencli against a network profile, with its own networks file and
+// deployment manifest
+type Tokencli struct {
+	// Manifest is the deployment manifest written by the runs
+	Manifest string
+
+	bin     string
+	harness *Harness
+	env     []string
+}
+
+// repoRoot returns the root of the repository, two directories above this file
+func repoRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+// NewTokencli builds tokencli into dir, and returns a runner against the network
+// profile, such as that of an RPCServer, of which the harness accounts sign
+// transactions. The profile is the only network of the networks file written to dir.
+func NewTokencli(dir string, h *Harness, profile *util.NetworkProfile) (*Tokencli, error) {
+	root := repoRoot()
+	bin := filepath.Join(dir, "tokencli")
+	build := exec.Command("go", "build", "-o", bin, "./scripts/tokencli")
+	build.Dir = root
+	if out, err := build.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to build tokencli: %w: %s", err, out)
+	}
+
+	networks, err := json.Marshal(util.NetworkConfig{
+		Default:  profile.Name,
+		Networks: map[string]util.NetworkProfile{profile.Name: *profile},
+	})
+	if err != nil {
+		return nil, err
+	}
+	networksFile := filepath.Join(dir, "networks.json")
+	if err := os.WriteFile(networksFile, networks, 0o644); err != nil {
+		return nil, err
+	}
+
+	return &Tokencli{
+		Manifest: filepath.Join(dir, "deployments.json"),
+		bin:      bin,
+		harness:  h,
+		env:      append(os.Environ(), util.EnvNetworksFile+"="+networksFile, util.EnvNetwork+"="),
+	}, nil
+}
+
+// Run runs a tokencli subcommand with the arguments following the subcommand, and
+// decodes its JSON output into result, if it is not nil. If from is not empty, the
+// transactions are signed by the key of that harness account.
+func (c *Tokencli) Run(from string, result interface{}, subcommand string, args ...string) error {
+	flags := []string{subcommand, "-manifest", c.Manifest, "-output", "json"}
+	env := c.env
+	if from != "" {
+		key := c.harness.Account(from).Key
+		flags = append(flags, "-signer", "insecure-hex", "-wait-mode", "poll", "-poll-interval", "10ms")
+		env = append(env, util.DefaultKeyEnv+"="+common.Bytes2Hex(crypto.FromECDSA(key)))
+	}
+
+	cmd := exec.Command(c.bin, append(flags, args...)...)
+	cmd.Dir = repoRoot()
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("tokencli %s %s: %w: %s", subcommand, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(out, result); err != nil {
+		return fmt.Errorf("invalid output of tokencli %s: %w: %s", subcommand, err, out)
+	}
+	return nil
+}
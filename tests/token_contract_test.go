@@ -6,81 +6,202 @@
 package tests
 
 import (
-	"context"
-	"crypto/ecdsa"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
 	"math/big"
+	"os"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	token "github.com/K1-R1/EvmosDeployContract/scripts/token"
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
 	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/stretchr/testify/suite"
+// tokenGasBaseline is the committed gas report of the Token suite
+const tokenGasBaseline = "testdata/gas/token.json"
+
+var (
+	updateGas    = flag.Bool("update-gas", false, "rewrite the gas baseline of the Token suite")
+	gasTolerance = flag.Float64("gas-tolerance", testUtil.DefaultGasTolerance, "fraction by which the gas of a Token method may exceed its baseline")
+
+	// tokenGas records the gas used by every transaction of the Token suite
+	tokenGas *testUtil.GasReport
 )
 
-// ContractTestSuite defines the test suite for the Token smart contract.
-// It contains slices of private keys and addresses, which are used to test the
-// contract methods.
-type ContractTestSuite struct {
-	suite.Suite
+// TestToken runs the BDD specs of the Token contract.
+func TestToken(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Token Suite")
+}
 
-	addresses       []common.Address
-	auth            *bind.TransactOpts
-	client          *backends.SimulatedBackend
+var _ = BeforeSuite(func() {
+	tokenABI, err := token.TokenMetaData.GetAbi()
+	Expect(err).To(BeNil())
+	tokenGas = testUtil.NewGasReport(tokenABI)
+})
+
+/*
+  - The gas report is only compared with the baseline when every spec ran in a single
+    process, as the averages depend on which specs ran.
+*/
+var _ = AfterSuite(func() {
+	fmt.Fprintln(os.Stdout, "\nToken gas report:")
+	Expect(tokenGas.WriteTable(os.Stdout)).To(Succeed())
+
+	if *updateGas {
+		Expect(tokenGas.WriteBaseline(tokenGasBaseline)).To(Succeed())
+		return
+	}
+	suiteConfig, _ := GinkgoConfiguration()
+	if suiteConfig.ParallelTotal > 1 || len(suiteConfig.FocusStrings) > 0 || len(suiteConfig.SkipStrings) > 0 || suiteConfig.LabelFilter != "" {
+		return
+	}
+
+	baseline, err := testUtil.LoadGasBaseline(tokenGasBaseline)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintln(os.Stdout, "No gas baseline, run the suite with -update-gas to write one")
+		return
+	}
+	Expect(err).To(BeNil())
+	Expect(tokenGas.CheckBaseline(baseline, *gasTolerance)).To(Succeed())
+})
+
+// tokenSpec holds the simulated chain and Token contract of a single spec
+type tokenSpec struct {
+	harness         *testUtil.Harness
 	contract        *token.Token
-	contractAddress common.Address
 	deployerBalance *big.Int
-	privKeys        []*ecdsa.PrivateKey
 }
 
-// Initialize the test suite
-var s *ContractTestSuite
-
-// SetupTest defines the procedure for setting up BDD tests of the contract
-// methods. For this, an instance of the Token smart contract is deployed
-// to a simulated backend.
-func (suite *ContractTestSuite) SetupTest() {
-	// Generate testing accounts
-	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(3)
-	if err != nil {
-		log.Fatalf("Error generating private key: %v\n", err)
+// newTokenSpec starts a simulated chain with a deployer, spender and recipient account,
+// and deploys an instance of the Token smart contract from the deployer.
+// The chain is stopped when the spec ends.
+func newTokenSpec() *tokenSpec {
+	harness, err := testUtil.NewHarness("deployer", "spender", "recipient")
+	Expect(err).To(BeNil())
+	DeferCleanup(harness.Close)
+	harness.Gas = tokenGas
+
+	_, contract, err := testUtil.Deploy(harness, "deployer", testUtil.DeployToken)
+	Expect(err).To(BeNil())
+
+	return &tokenSpec{
+		harness:         harness,
+		contract:        contract,
+		deployerBalance: new(big.Int).Mul(big.NewInt(100), testUtil.Ten18), // 100 TOK
 	}
+}
 
-	// Get simulated backend and transaction signer for testing
-	client, auth, _ := testUtil.GetSimulatedClientAndTransactionSigner(privKeys[0], testUtil.MaxGasPerBlock, testUtil.TestChainID)
+// deployToken deploys an instance of the Token smart contract with the parameters
+// from the deployer of the harness, and returns the deployment error
+func deployToken(harness *testUtil.Harness, params util.TokenParams) (*token.Token, error) {
+	_, contract, err := testUtil.Deploy(harness, "deployer", func(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *token.Token, error) {
+		return token.DeployToken(auth, backend, params.Name, params.Symbol, params.Decimals, params.InitialSupply, params.Holders)
+	})
+	return contract, err
+}
 
-	// Deploy contract
-	contractAddress, _, contract, _ := testUtil.DeployContractAndCommit(auth, client)
+var _ = Describe("constructor:", func() {
+	var harness *testUtil.Harness
 
-	// Assign to testing suite
-	suite.addresses = addresses
-	suite.auth = auth
-	suite.client = client
-	suite.contract = contract
-	suite.contractAddress = contractAddress
-	suite.deployerBalance = new(big.Int).Mul(big.NewInt(100), testUtil.Ten18) // 100 TOK
-	suite.privKeys = privKeys
-}
+	BeforeEach(func() {
+		var err error
+		harness, err = testUtil.NewHarness("deployer", "alice", "bob", "carol")
+		Expect(err).To(BeNil())
+		DeferCleanup(harness.Close)
+	})
 
-// TestToken initializes the test suite and runs the tests.
-func TestToken(t *testing.T) {
-	s = new(ContractTestSuite)
-	suite.Run(t, s)
+	Context("When deployed with the default parameters", func() {
+		It("should have the name, symbol and decimals of the original Token", func() {
+			contract, err := deployToken(harness, util.DefaultTokenParams)
+			Expect(err).To(BeNil())
 
-	RegisterFailHandler(Fail)
-	RunSpecs(t, "Token Suite")
-}
+			name, err := contract.Name(nil)
+			Expect(name, err).To(Equal("Token"))
+			symbol, err := contract.Symbol(nil)
+			Expect(symbol, err).To(Equal("TOK"))
+			decimals, err := contract.Decimals(nil)
+			Expect(decimals, err).To(Equal(uint8(18)))
+		})
+
+		It("should have minted 100 TOK to the deployer", func() {
+			contract, err := deployToken(harness, util.DefaultTokenParams)
+			Expect(err).To(BeNil())
+
+			expected := new(big.Int).Mul(big.NewInt(100), testUtil.Ten18)
+			totalSupply, err := contract.TotalSupply(nil)
+			Expect(totalSupply.Cmp(expected), err).To(Equal(0))
+			deployerBalance, err := contract.BalanceOf(nil, harness.Address("deployer"))
+			Expect(deployerBalance.Cmp(expected), err).To(Equal(0))
+		})
+	})
+
+	Context("When deployed with a custom name, symbol, decimals and supply", func() {
+		params := util.TokenParams{Name: "Evmos Dollar", Symbol: "EUSD", Decimals: 6, InitialSupply: big.NewInt(2_500_000_000)}
+
+		It("should have the given name, symbol and decimals", func() {
+			contract, err := deployToken(harness, params)
+			Expect(err).To(BeNil())
+
+			name, err := contract.Name(nil)
+			Expect(name, err).To(Equal(params.Name))
+			symbol, err := contract.Symbol(nil)
+			Expect(symbol, err).To(Equal(params.Symbol))
+			decimals, err := contract.Decimals(nil)
+			Expect(decimals, err).To(Equal(params.Decimals))
+		})
+
+		It("should have minted the initial supply to the deployer", func() {
+			contract, err := deployToken(harness, params)
+			Expect(err).To(BeNil())
+
+			totalSupply, err := contract.TotalSupply(nil)
+			Expect(totalSupply.Cmp(params.InitialSupply), err).To(Equal(0))
+			deployerBalance, err := contract.BalanceOf(nil, harness.Address("deployer"))
+			Expect(deployerBalance.Cmp(params.InitialSupply), err).To(Equal(0))
+		})
+	})
+
+	Context("When deployed with initial holders", func() {
+		It("should have split the initial supply between the holders, with the remainder to the first", func() {
+			params := util.DefaultTokenParams
+			params.InitialSupply = big.NewInt(1000)
+			params.Holders = []common.Address{harness.Address("alice"), harness.Address("bob"), harness.Address("carol")}
+			contract, err := deployToken(harness, params)
+			Expect(err).To(BeNil())
+
+			for i, share := range []int64{334, 333, 333} {
+				balance, err := contract.BalanceOf(nil, params.Holders[i])
+				Expect(balance.Cmp(big.NewInt(share)), err).To(Equal(0))
+			}
+			Expect(params.Shares()).To(Equal([]*big.Int{big.NewInt(334), big.NewInt(333), big.NewInt(333)}))
+
+			deployerBalance, err := contract.BalanceOf(nil, harness.Address("deployer"))
+			Expect(deployerBalance.Sign(), err).To(Equal(0))
+		})
+
+		It("should fail to deploy with the zero address as a holder", func() {
+			params := util.DefaultTokenParams
+			params.Holders = []common.Address{harness.Address("alice"), {}}
+			_, err := deployToken(harness, params)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
 
 var _ = Describe("approve:", func() {
+	var s *tokenSpec
+
 	BeforeEach(func() {
-		s.SetupTest()
+		s = newTokenSpec()
 	})
 
 	Context("When approving a transaction amount", Ordered, func() {
@@ -88,27 +209,56 @@ var _ = Describe("approve:", func() {
 			// Define approved amount
 			amount := testUtil.Ten18
 
-			// Approve tokens from account1 to account2
-			_, err := s.contract.Approve(s.auth, s.addresses[1], amount)
+			// Approve tokens from the deployer to the spender, and commit the transaction
+			_, err := s.harness.Commit(s.contract.Approve(s.harness.Auth("deployer"), s.harness.Address("spender"), amount))
 			Expect(err).To(BeNil())
 
-			// Commit transaction
-			s.client.Commit()
-
-			allowance, err := s.contract.Allowance(nil, s.addresses[0], s.addresses[1])
+			allowance, err := s.contract.Allowance(nil, s.harness.Address("deployer"), s.harness.Address("spender"))
 			Expect(allowance.Cmp(amount), err).To(Equal(0))
 		})
 	})
 })
 
+var _ = Describe("increaseAllowance:", func() {
+	var s *tokenSpec
+
+	BeforeEach(func() {
+		s = newTokenSpec()
+	})
+
+	Context("When increasing and decreasing an allowance", Ordered, func() {
+		It("should have moved the allowance of the spender by each amount", func() {
+			// Increase the spender's allowance twice, and decrease it once
+			_, err := s.harness.Commit(s.contract.IncreaseAllowance(s.harness.Auth("deployer"), s.harness.Address("spender"), testUtil.Ten18))
+			Expect(err).To(BeNil())
+			_, err = s.harness.Commit(s.contract.IncreaseAllowance(s.harness.Auth("deployer"), s.harness.Address("spender"), testUtil.Ten18))
+			Expect(err).To(BeNil())
+			_, err = s.harness.Commit(s.contract.DecreaseAllowance(s.harness.Auth("deployer"), s.harness.Address("spender"), testUtil.Ten18))
+			Expect(err).To(BeNil())
+
+			allowance, err := s.contract.Allowance(nil, s.harness.Address("deployer"), s.harness.Address("spender"))
+			Expect(allowance.Cmp(testUtil.Ten18), err).To(Equal(0))
+		})
+	})
+
+	Context("When decreasing an allowance below zero", func() {
+		It("should not be able to decrease the allowance", func() {
+			_, err := s.harness.Commit(s.contract.DecreaseAllowance(s.harness.Auth("deployer"), s.harness.Address("spender"), testUtil.Ten18))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
 var _ = Describe("balance:", func() {
+	var s *tokenSpec
+
 	BeforeEach(func() {
-		s.SetupTest()
+		s = newTokenSpec()
 	})
 
 	Context("Deployer balance after deployment", func() {
 		It("should be 100", func() {
-			balance, err := s.contract.BalanceOf(nil, s.addresses[0])
+			balance, err := s.contract.BalanceOf(nil, s.harness.Address("deployer"))
 			Expect(err).To(BeNil())
 			Expect(balance.Cmp(s.deployerBalance)).To(Equal(0))
 		})
@@ -116,7 +266,7 @@ var _ = Describe("balance:", func() {
 
 	Context("Other accounts' balances after deployment", func() {
 		It("should be 0", func() {
-			balance, err := s.contract.BalanceOf(nil, s.addresses[1])
+			balance, err := s.contract.BalanceOf(nil, s.harness.Address("spender"))
 			Expect(err).To(BeNil())
 			Expect(balance.Cmp(new(big.Int))).To(Equal(0))
 		})
@@ -124,8 +274,10 @@ var _ = Describe("balance:", func() {
 })
 
 var _ = Describe("transfer:", func() {
+	var s *tokenSpec
+
 	BeforeEach(func() {
-		s.SetupTest()
+		s = newTokenSpec()
 	})
 
 	Context("When sender has sufficient tokens", Ordered, func() {
@@ -133,22 +285,19 @@ var _ = Describe("transfer:", func() {
 		amount := testUtil.Ten18
 
 		BeforeEach(func() {
-			// Transfer tokens from account1 to account2
-			_, err := s.contract.Transfer(s.auth, s.addresses[1], amount)
+			// Transfer tokens from the deployer to the spender, and commit the transaction
+			_, err := s.harness.Commit(s.contract.Transfer(s.harness.Auth("deployer"), s.harness.Address("spender"), amount))
 			Expect(err).To(BeNil())
-
-			// Commit transaction
-			s.client.Commit()
 		})
 
 		It("should have deducted the transferred amount from the sender balance", func() {
-			senderBalance, err := s.contract.BalanceOf(nil, s.addresses[0])
+			senderBalance, err := s.contract.BalanceOf(nil, s.harness.Address("deployer"))
 			Expect(err).To(BeNil())
 			Expect(senderBalance.Cmp(new(big.Int).Sub(s.deployerBalance, amount))).To(Equal(0))
 		})
 
 		It("should have increased the recipient balance by the transferred amount", func() {
-			recipientBalance, err := s.contract.BalanceOf(nil, s.addresses[1])
+			recipientBalance, err := s.contract.BalanceOf(nil, s.harness.Address("spender"))
 			Expect(err).To(BeNil())
 			Expect(recipientBalance.Cmp(amount)).To(Equal(0))
 		})
@@ -159,49 +308,31 @@ var _ = Describe("transfer:", func() {
 		amount := new(big.Int).Mul(big.NewInt(100000), testUtil.Ten18)
 
 		BeforeEach(func() {
-			// Transfer tokens from account1 to account2
-			_, err := s.contract.Transfer(s.auth, s.addresses[1], amount)
-			Expect(err).Error()
-
-			// Commit transaction
-			s.client.Commit()
+			// Transfer tokens from the deployer to the spender
+			_, err := s.harness.Commit(s.contract.Transfer(s.harness.Auth("deployer"), s.harness.Address("spender"), amount))
+			Expect(err).To(HaveOccurred())
 		})
 
 		It("should not have deducted the transferred amount from the sender balance", func() {
-			senderBalance, err := s.contract.BalanceOf(nil, s.addresses[0])
+			senderBalance, err := s.contract.BalanceOf(nil, s.harness.Address("deployer"))
 			Expect(senderBalance.Cmp(s.deployerBalance), err).To(Equal(0))
 		})
 
 		It("should not have increased the recipient balance", func() {
-			recipientBalance, err := s.contract.BalanceOf(nil, s.addresses[1])
+			recipientBalance, err := s.contract.BalanceOf(nil, s.harness.Address("spender"))
 			Expect(recipientBalance.Cmp(big.NewInt(0)), err).To(Equal(0))
 		})
 	})
 })
 
 var _ = Describe("transferFrom:", func() {
-	BeforeEach(func() {
-		s.SetupTest()
-
-		/** In order to transfer from, the sender must have sufficient Evmos
-		  tokens to pay the gas cost of the transfer. Hence, before the
-		  tests, an appropriate amount of Evmos is sent to the sender address,
-		  which upon genesis of the simulated backend, does not hold any.
-		*/
-
-		// Define transaction
-		tx := types.NewTransaction(1, s.addresses[1], big.NewInt(1e15), testUtil.MaxGasPerBlock, big.NewInt(817704169), nil)
-
-		// Sign the transaction with the private key of the deployer account
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(testUtil.TestChainID), s.privKeys[0])
-		Expect(err).To(BeNil())
-
-		// Send Evmos to account1 for gas usage.
-		err = s.client.SendTransaction(context.Background(), signedTx)
-		Expect(err).To(BeNil())
+	var s *tokenSpec
 
-		// Commit transaction
-		s.client.Commit()
+	/** Every harness account is funded at genesis, so the spender can pay the
+	  gas cost of transferring from the deployer without being sent Evmos first.
+	*/
+	BeforeEach(func() {
+		s = newTokenSpec()
 	})
 
 	Context("When no approval was given", func() {
@@ -209,12 +340,9 @@ var _ = Describe("transferFrom:", func() {
 			// Define transferred amount
 			amount := testUtil.Ten18
 
-			// Transfer tokens from account 1 to account 2
-			_, err := s.contract.TransferFrom(s.auth, s.addresses[1], s.addresses[2], amount)
-			Expect(err).Error()
-
-			// Commit transaction
-			s.client.Commit()
+			// Transfer tokens from the spender to the recipient, without an allowance
+			_, err := s.harness.Commit(s.contract.TransferFrom(s.harness.Auth("deployer"), s.harness.Address("spender"), s.harness.Address("recipient"), amount))
+			Expect(err).To(HaveOccurred())
 		})
 	})
 
@@ -223,60 +351,43 @@ var _ = Describe("transferFrom:", func() {
 		amount := testUtil.Ten18
 
 		BeforeEach(func() {
-			// Approve tokens from deployer account to sender account
-			_, err := s.contract.Approve(s.auth, s.addresses[1], amount)
+			// Approve tokens from the deployer to the spender
+			_, err := s.harness.Commit(s.contract.Approve(s.harness.Auth("deployer"), s.harness.Address("spender"), amount))
 			Expect(err).To(BeNil())
-
-			// Commit transaction
-			s.client.Commit()
 		})
 
 		Context("and the approver has sufficient tokens in his balance", func() {
 			BeforeEach(func() {
-				// Define transactor for sender account
-				auth, err := bind.NewKeyedTransactorWithChainID(s.privKeys[1], testUtil.TestChainID)
+				// Transfer tokens from the deployer to the recipient, as the spender
+				_, err := s.harness.Commit(s.contract.TransferFrom(s.harness.Auth("spender"), s.harness.Address("deployer"), s.harness.Address("recipient"), amount))
 				Expect(err).To(BeNil())
-
-				// Transfer tokens from sender account to recipient
-				_, err = s.contract.TransferFrom(auth, s.addresses[0], s.addresses[2], amount)
-				Expect(err).To(BeNil())
-
-				// Commit transaction
-				s.client.Commit()
 			})
 
 			It("should have deducted the transferred amount from the approver's balance", func() {
-				approverBalance, err := s.contract.BalanceOf(nil, s.addresses[0])
+				approverBalance, err := s.contract.BalanceOf(nil, s.harness.Address("deployer"))
 				Expect(approverBalance.Cmp(new(big.Int).Sub(s.deployerBalance, amount)), err).To(Equal(0))
 			})
 
 			It("should have increased recipient's balance by the transferred amount", func() {
-				recipientBalance, err := s.contract.BalanceOf(nil, s.addresses[2])
+				recipientBalance, err := s.contract.BalanceOf(nil, s.harness.Address("recipient"))
 				Expect(recipientBalance.Cmp(amount), err).To(Equal(0))
 			})
 		})
 
 		Context("and the approver does not have sufficient tokens in his balance", func() {
 			BeforeEach(func() {
-				// Define transactor for sender account
-				auth, err := bind.NewKeyedTransactorWithChainID(s.privKeys[1], testUtil.TestChainID)
-				Expect(err).To(BeNil())
-
-				// Transfer twice the approved tokens from sender account to recipient
-				_, err = s.contract.TransferFrom(auth, s.addresses[0], s.addresses[2], new(big.Int).Mul(big.NewInt(2), amount))
-				Expect(err).Error()
-
-				// Commit transaction
-				s.client.Commit()
+				// Transfer twice the approved tokens from the deployer to the recipient, as the spender
+				_, err := s.harness.Commit(s.contract.TransferFrom(s.harness.Auth("spender"), s.harness.Address("deployer"), s.harness.Address("recipient"), new(big.Int).Mul(big.NewInt(2), amount)))
+				Expect(err).To(HaveOccurred())
 			})
 
 			It("should not have deducted the transferred amount from the approver's balance", func() {
-				approverBalance, err := s.contract.BalanceOf(nil, s.addresses[0])
+				approverBalance, err := s.contract.BalanceOf(nil, s.harness.Address("deployer"))
 				Expect(approverBalance.Cmp(s.deployerBalance), err).To(Equal(0))
 			})
 
 			It("should not have increased recipient's balance by the transferred amount", func() {
-				recipientBalance, err := s.contract.BalanceOf(nil, s.addresses[2])
+				recipientBalance, err := s.contract.BalanceOf(nil, s.harness.Address("recipient"))
 				Expect(recipientBalance.Cmp(big.NewInt(0)), err).To(Equal(0))
 			})
 		})
@@ -0,0 +1,65 @@
+/** token_params_test.go contains TDD ( Test Driven Development ) style tests for the
+  Token constructor parameters in scripts/utils/token.go.
+*/
+
+package tests
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+)
+
+// Test TokenParams
+// Checks that only Token parameters that can be deployed are valid, and that the
+// initial supply is split between the holders like the constructor splits it
+func TestTokenParams(t *testing.T) {
+	require.NoError(t, util.DefaultTokenParams.Validate(), "Default parameters should be valid")
+
+	alice := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	bob := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	maxUint256 := bigFromString(t, "115792089237316195423570985008687907853269984665640564039457584007913129639935")
+
+	testcases := []struct {
+		name   string
+		change func(p *util.TokenParams)
+		expErr string
+	}{
+		{"Holders", func(p *util.TokenParams) { p.Holders = []common.Address{alice, bob} }, ""},
+		{"Max uint256 supply", func(p *util.TokenParams) { p.InitialSupply = maxUint256 }, ""},
+		{"Empty name", func(p *util.TokenParams) { p.Name = " " }, "empty token name"},
+		{"Empty symbol", func(p *util.TokenParams) { p.Symbol = "" }, "invalid token symbol"},
+		{"Symbol with spaces", func(p *util.TokenParams) { p.Symbol = "T OK" }, "invalid token symbol"},
+		{"Too many decimals", func(p *util.TokenParams) { p.Decimals = 78 }, "invalid decimals 78"},
+		{"Zero supply", func(p *util.TokenParams) { p.InitialSupply = big.NewInt(0) }, "must be positive"},
+		{"Supply overflow", func(p *util.TokenParams) { p.InitialSupply = new(big.Int).Add(maxUint256, big.NewInt(1)) }, "does not fit in a uint256"},
+		{"Zero address holder", func(p *util.TokenParams) { p.Holders = []common.Address{alice, {}} }, "zero address"},
+		{"Duplicate holder", func(p *util.TokenParams) { p.Holders = []common.Address{alice, bob, alice} }, "more than once"},
+		{"Supply below one unit per holder", func(p *util.TokenParams) {
+			p.InitialSupply = big.NewInt(1)
+			p.Holders = []common.Address{alice, bob}
+		}, "cannot be split between 2 holders"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := util.DefaultTokenParams
+			tc.change(&params)
+			err := params.Validate()
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr, "Incorrect Validate error")
+				return
+			}
+			require.NoError(t, err, "Error during Validate")
+		})
+	}
+
+	params := util.TokenParams{Name: "Token", Symbol: "TOK", InitialSupply: big.NewInt(11), Holders: []common.Address{alice, bob}}
+	require.Equal(t, []*big.Int{big.NewInt(6), big.NewInt(5)}, params.Shares(), "The remainder should go to the first holder")
+	params.Holders = nil
+	require.Nil(t, params.Shares(), "There are no shares without holders")
+}
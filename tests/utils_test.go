@@ -6,11 +6,12 @@ package tests
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/require"
 
@@ -18,12 +19,32 @@ import (
 	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
 )
 
+// startLocalNode starts a simulated chain with the chain ID of the local node, served
+// over JSON-RPC, and returns the harness and the local network profile pointing to it.
+// Both are stopped when the test ends.
+func startLocalNode(t *testing.T) (*testUtil.Harness, *util.NetworkProfile) {
+	h, err := testUtil.NewHarness("deployer", "alice")
+	require.NoError(t, err, "Error starting harness")
+	t.Cleanup(func() { h.Close() })
+
+	server, err := testUtil.NewRPCServer(h, big.NewInt(util.LocalNetwork.ChainID))
+	require.NoError(t, err, "Error starting JSON-RPC server")
+	t.Cleanup(server.Close)
+
+	profile := util.LocalNetwork
+	profile.HTTP = server.URL
+	profile.WS = server.WSURL
+	return h, &profile
+}
+
 // Test GetClient
 // checks if connection to node is successful,
 // and that the chain ID is correct for the local node
 func TestGetClient(t *testing.T) {
+	_, profile := startLocalNode(t)
+
 	// Check that connection to node is a success
-	client, err := util.GetClient()
+	client, err := util.GetClient(profile)
 	require.NoError(t, err, "Error getting client")
 
 	// Check if chain ID is correct
@@ -59,41 +80,46 @@ func TestGetPKAndAddress(t *testing.T) {
 	}
 }
 
+// zeroSigner is a Signer with the zero address, used to test address validation
+type zeroSigner struct{}
+
+func (zeroSigner) Address() common.Address { return common.Address{} }
+
+func (zeroSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, errors.New("zeroSigner cannot sign")
+}
+
 // Test GetAuth
 // Checks that valid transaction options are only generated with valid inputs
 func TestGetAuth(t *testing.T) {
-	privKeys, addresses, err := testUtil.GeneratePrivKeysAndAddresses(1)
-	require.NoError(t, err, "Error generating private key and address")
+	h, profile := startLocalNode(t)
 
-	client, err := util.GetClient()
+	client, err := util.GetClient(profile)
 	require.NoError(t, err, "Error getting client")
 
 	testcases := []struct {
-		name    string
-		expErr  bool
-		client  *ethclient.Client
-		pk      *ecdsa.PrivateKey
-		address common.Address
+		name   string
+		expErr bool
+		client *ethclient.Client
+		signer util.Signer
 	}{
 		{
 			"Valid inputs",
 			false,
 			client,
-			privKeys[0],
-			addresses[0],
+			util.NewKeySigner(h.Account("deployer").Key),
 		},
 		{
 			"Invalid address",
 			true,
 			client,
-			privKeys[0],
-			common.HexToAddress("0x0"),
+			zeroSigner{},
 		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			auth, err := util.GetAuth(tc.client, tc.pk, tc.address)
+			auth, err := util.GetAuth(tc.client, tc.signer, util.DefaultFeeOptions)
 			if tc.expErr {
 				require.Error(t, err, "GetAuth should raise an error")
 			} else {
@@ -0,0 +1,140 @@
+/** verify_test.go contains TDD ( Test Driven Development ) style tests for the
+  bytecode verification in scripts/utils/verify.go.
+*/
+
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	util "github.com/K1-R1/EvmosDeployContract/scripts/utils"
+	testUtil "github.com/K1-R1/EvmosDeployContract/tests/test_utils"
+)
+
+// Test VerifyCode
+// Checks that deployed contracts match the artifacts they were deployed from, with
+// their constructor arguments
+func TestVerifyCode(t *testing.T) {
+	h, err := testUtil.NewHarness("deployer")
+	require.NoError(t, err, "Error starting harness")
+	defer h.Close()
+	ctx := context.Background()
+
+	tokenArtifact, err := util.ReadArtifact(buildDir, "Token")
+	require.NoError(t, err, "Error reading Token artifact")
+	erc20Artifact, err := util.ReadArtifact(buildDir, "ERC20")
+	require.NoError(t, err, "Error reading ERC20 artifact")
+	ierc20Artifact, err := util.ReadArtifact(buildDir, "IERC20")
+	require.NoError(t, err, "Error reading IERC20 artifact")
+
+	tokenAddress, tokenTx, _, err := testUtil.DeployToken(h.Auth("deployer"), h.Backend)
+	_, err = h.Commit(tokenTx, err)
+	require.NoError(t, err, "Error deploying Token")
+	tokenArgs, err := util.ConstructorArgs(common.FromHex(tokenArtifact.Bin), tokenTx.Data())
+	require.NoError(t, err, "Error reading Token constructor arguments")
+
+	// Deploy the ERC20 artifact, which has constructor arguments
+	erc20ABI, err := abi.JSON(strings.NewReader(string(erc20Artifact.ABI)))
+	require.NoError(t, err, "Error parsing ERC20 ABI")
+	erc20Address, tx, _, err := bind.DeployContract(h.Auth("deployer"), erc20ABI, common.FromHex(erc20Artifact.Bin), h.Backend, "Other", "OTH")
+	_, err = h.Commit(tx, err)
+	require.NoError(t, err, "Error deploying ERC20")
+	erc20Args, err := util.ConstructorArgs(common.FromHex(erc20Artifact.Bin), tx.Data())
+	require.NoError(t, err, "Error reading constructor arguments")
+	_, err = util.ConstructorArgs(common.FromHex(tokenArtifact.Bin), tx.Data())
+	require.Error(t, err, "Input of another artifact should be rejected")
+
+	testcases := []struct {
+		name     string
+		address  common.Address
+		artifact util.Artifact
+		args     []byte
+		status   util.VerifyStatus
+		isValid  bool
+	}{
+		{"Token with constructor arguments", tokenAddress, tokenArtifact, tokenArgs, util.VerifyMatch, true},
+		{"Token without its constructor arguments", tokenAddress, tokenArtifact, nil, "", false},
+		{"ERC20 with constructor arguments", erc20Address, erc20Artifact, erc20Args, util.VerifyMatch, true},
+		// Token overrides decimals, so its runtime code is not that of ERC20
+		{"ERC20 runtime code at Token", tokenAddress, erc20Artifact, erc20Args, util.VerifyMismatch, true},
+		{"ERC20 without its constructor arguments", erc20Address, erc20Artifact, nil, "", false},
+		{"Interface artifact", tokenAddress, ierc20Artifact, nil, "", false},
+		{"No code at address", h.Address("deployer"), tokenArtifact, nil, "", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := util.VerifyCode(ctx, h.Backend, tc.address, tc.artifact, tc.args, h.Address("deployer"))
+			if !tc.isValid {
+				require.Error(t, err, "Invalid inputs should return an error")
+				return
+			}
+			require.NoError(t, err, "Error during VerifyCode")
+			require.Equal(t, tc.status, result.Status, "Incorrect verify status")
+		})
+	}
+}
+
+// Test CompareBytecode
+// Checks that differences in the metadata hash and immutable references are told
+// apart from differences in the code
+func TestCompareBytecode(t *testing.T) {
+	artifact, err := util.ReadArtifact(buildDir, "Token")
+	require.NoError(t, err, "Error reading Token artifact")
+	tokenABI, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	require.NoError(t, err, "Error parsing Token ABI")
+	params := util.DefaultTokenParams
+	args, err := tokenABI.Pack("", params.Name, params.Symbol, params.Decimals, params.InitialSupply, []common.Address{})
+	require.NoError(t, err, "Error packing Token constructor arguments")
+	runtimeCode, err := util.DeployedBytecode(append(common.FromHex(artifact.Bin), args...), common.HexToAddress("0x01"))
+	require.NoError(t, err, "Error during DeployedBytecode")
+
+	code, metadata := util.SplitMetadata(runtimeCode)
+	require.Len(t, metadata, 0x35, "Solidity 0.8 metadata should be 51 bytes and the length")
+	version, err := util.BytecodeCompilerVersion(common.Bytes2Hex(metadata))
+	require.NoError(t, err, "Metadata should hold the compiler version")
+	require.Equal(t, "0.8.21", version, "Incorrect compiler version")
+
+	// change returns a copy of the runtime code, with the byte at i incremented
+	change := func(i int) []byte {
+		changed := common.CopyBytes(runtimeCode)
+		changed[i]++
+		return changed
+	}
+	immutable := []util.ImmutableReference{{Start: 10, Length: 32}}
+
+	testcases := []struct {
+		name       string
+		actual     []byte
+		immutables []util.ImmutableReference
+		status     util.VerifyStatus
+		difference int
+	}{
+		{"Identical code", runtimeCode, nil, util.VerifyMatch, -1},
+		{"Changed metadata hash", change(len(code) + 10), nil, util.VerifyMetadataOnly, -1},
+		{"Changed code", change(100), nil, util.VerifyMismatch, 100},
+		{"Truncated code", code[:50], nil, util.VerifyMismatch, 50},
+		{"Changed immutable", change(20), immutable, util.VerifyMatch, -1},
+		{"Changed code after immutable", change(42), immutable, util.VerifyMismatch, 42},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := util.CompareBytecode(runtimeCode, tc.actual, tc.immutables)
+			require.Equal(t, tc.status, result.Status, "Incorrect verify status")
+			require.Equal(t, tc.difference, result.FirstDifference, "Incorrect first difference")
+		})
+	}
+
+	plain := []byte{0x60, 0x00, 0x60, 0x00}
+	code, metadata = util.SplitMetadata(plain)
+	require.Equal(t, plain, code, "Code without metadata should be returned whole")
+	require.Nil(t, metadata, "Code without metadata should have no metadata")
+}